@@ -1,8 +1,10 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/chuxorg/chux-yanzi-cli/internal/cmd"
 	"github.com/chuxorg/chux-yanzi-cli/internal/config"
@@ -12,70 +14,129 @@ import (
 var version = "dev"
 
 func main() {
-	if len(os.Args) < 2 {
+	args, format, err := splitOutputFlag(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	cmd.SetOutputFormat(format)
+	if len(args) < 1 {
 		usage()
 		os.Exit(1)
 	}
 
 	initialized, err := yanzilibrary.Initialize()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		exit(err)
 	}
 	if initialized {
-		fmt.Println("Yanzi initialized at ~/.yanzi")
+		// Keep stdout to the command's own document in structured output.
+		if cmd.StructuredOutput() {
+			fmt.Fprintln(os.Stderr, "Yanzi initialized at ~/.yanzi")
+		} else {
+			fmt.Println("Yanzi initialized at ~/.yanzi")
+		}
 	}
 
-	if os.Args[1] == "--version" {
+	if args[0] == "--version" {
 		if err := printVersion(); err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
+			exit(err)
 		}
 		return
 	}
 
-	if isHelpArg(os.Args[1]) {
+	if isHelpArg(args[0]) {
 		usage()
 		return
 	}
 
 	err = nil
-	switch os.Args[1] {
+	switch args[0] {
 	case "capture":
-		err = cmd.RunCapture(os.Args[2:])
+		err = cmd.RunCapture(args[1:])
 	case "verify":
-		err = cmd.RunVerify(os.Args[2:])
+		err = cmd.RunVerify(args[1:])
 	case "chain":
-		err = cmd.RunChain(os.Args[2:])
+		err = cmd.RunChain(args[1:])
 	case "list":
-		err = cmd.RunList(os.Args[2:])
+		err = cmd.RunList(args[1:])
 	case "show":
-		err = cmd.RunShow(os.Args[2:])
+		err = cmd.RunShow(args[1:])
+	case "search":
+		err = cmd.RunSearch(args[1:])
 	case "mode":
-		err = cmd.RunMode(os.Args[2:])
+		err = cmd.RunMode(args[1:])
 	case "project":
-		err = cmd.RunProject(os.Args[2:])
+		err = cmd.RunProject(args[1:])
 	case "checkpoint":
-		err = cmd.RunCheckpoint(os.Args[2:])
+		err = cmd.RunCheckpoint(args[1:])
+	case "key":
+		err = cmd.RunKey(args[1:])
 	case "rehydrate":
-		err = cmd.RunRehydrate(os.Args[2:])
+		err = cmd.RunRehydrate(args[1:])
 	case "export":
-		err = cmd.RunExport(os.Args[2:], version)
+		err = cmd.RunExport(args[1:], version)
+	case "attachment":
+		err = cmd.RunAttachment(args[1:])
+	case "import":
+		err = cmd.RunImport(args[1:])
+	case "meta":
+		err = cmd.RunMeta(args[1:], version)
+	case "pause":
+		err = cmd.RunPause(args[1:])
+	case "resume":
+		err = cmd.RunResume(args[1:])
 	case "version":
-		if err := printVersion(); err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
-		}
-		return
+		err = runVersion(args[1:])
 	default:
 		usage()
 		os.Exit(1)
 	}
 
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		exit(err)
+	}
+}
+
+// exit reports err on stderr, as a JSON object in structured output, and exits with
+// the error's status.
+func exit(err error) {
+	code := 1
+	var exitErr *cmd.ExitError
+	if errors.As(err, &exitErr) {
+		code = exitErr.Code
+	}
+	cmd.WriteError(os.Stderr, err, code)
+	os.Exit(code)
+}
+
+// splitOutputFlag removes the global --output flag, given as --output <format> or
+// --output=<format> before the command name, and returns the remaining args. Commands
+// parse --output among their own flags, so a flag value such as --title --output is
+// never taken for it.
+func splitOutputFlag(args []string) ([]string, cmd.OutputFormat, error) {
+	format := cmd.OutputText
+	i := 0
+	for i < len(args) {
+		value, ok := strings.CutPrefix(args[i], "--output=")
+		if !ok {
+			if args[i] != "--output" {
+				break
+			}
+			if i+1 == len(args) {
+				return nil, "", errors.New("--output requires a value (text, json, ndjson, or yaml)")
+			}
+			i++
+			value = args[i]
+		}
+		parsed, err := cmd.ParseOutputFormat(value)
+		if err != nil {
+			return nil, "", err
+		}
+		format = parsed
+		i++
 	}
+	return args[i:], format, nil
 }
 
 func usage() {
@@ -84,42 +145,89 @@ func usage() {
 
 commands:
   capture  Create a new intent record via the library API.
-  verify   Verify an intent by id.
+  verify   Verify an intent by id, or audit the whole ledger.
   chain    Print an intent chain by id.
   list     List intent records.
   show     Show intent details by id.
+  search   Full-text search intent titles, prompts, and responses.
   mode     Show or set runtime mode (local | http).
   project  Manage project context.
   checkpoint  Manage checkpoints.
+  key      Manage Ed25519 keys that sign intents and checkpoints.
   rehydrate  Rehydrate active project context.
   export  Export active project history.
+  import   Import chat transcripts and AI chat exports.
+  attachment  Retrieve capture attachments.
+  meta     Run @yanzi meta-commands against the active project.
+  pause    Pause capture for the active project.
+  resume   Resume capture for the active project.
   version  Print the CLI version.
 
+global args:
+  --output <format>       text | json | ndjson | yaml (default text); before the command name or among its flags.
+                          Structured formats print errors to stderr as {"error", "exit_code"}.
+
 capture args:
   --author <name>         Required author name.
   --prompt <text>         Prompt text (exclusive with --prompt-file).
-  --prompt-file <path>    Prompt file path (exclusive with --prompt).
+  --prompt-file <path>    Prompt file path, or - for stdin (exclusive with --prompt).
   --response <text>       Response text (exclusive with --response-file).
-  --response-file <path>  Response file path (exclusive with --response).
+  --response-file <path>  Response file path, or - for stdin (exclusive with --response).
+  --json <path>           Capture object file, or - for stdin (replaces prompt/response flags).
+  --edit                  Compose title, prompt, response, and meta in $EDITOR.
+  --batch <path>          NDJSON capture objects, or - for stdin, stored in one transaction (local mode).
   --title <title>         Optional title.
   --source <source>       Optional source type (default "cli").
-  --prev-hash <hash>      Optional previous hash.
+  --prev-hash <hash>      Previous hash (default: head of the active project's chain).
+  --no-chain              Store without linking to or advancing the project chain.
+  --merge <intent>        Also name this intent as a parent, reconciling a fork (repeatable).
+  --no-git                Do not record git work tree context in meta.
+  --dry-run               Report what would be redacted without storing the capture.
   --meta key=value        Optional metadata (repeatable).
+  --meta-json key=<json>  Optional typed metadata: number, boolean, array, or object (repeatable).
+  --attach <path>         Store a file as a content-addressed attachment (repeatable; local mode).
+  --when-paused <mode>    skip | queue while the project is paused (default: config paused_capture, else skip).
 
 verify args:
-  <intent-id>             Intent id to verify.
+  <intent-id>             Intent id, hash, or unique prefix of 4+ characters (local mode).
+  --all                   Audit every hash and link in the local ledger (exit 5 tampered, 6 unreadable).
+  --project <name>        Limit --all to one project.
+  --workers <n>           Concurrent hash checks for --all (default: number of CPUs).
+  --require-signed        Fail records that carry no signature (local mode).
 
 chain args:
-  <intent-id>             Intent id to chain.
+  [intent-id]             Intent id, hash, or unique prefix (default: head of the active project's chain).
+  --graph                 Print the project's chain graph: heads, forks, merges, and common ancestor (local mode).
+  --project <name>        Project for --graph (default: active project).
+  --no-abbrev             Print full ids and hashes.
+  --format <template>     Print each intent, oldest first, with a Go template or named template.
 
 list args:
+  --project <name>        Optional project filter.
   --author <name>         Optional author filter.
   --source <source>       Optional source filter.
-  --meta k=v              Optional meta filter (repeatable; exact match; AND).
-  --limit <n>             Max records to return (default 20).
+  --meta k=v              Optional meta filter (repeatable; typed match; AND).
+  --since <time>          Only intents at or after an RFC3339 time, duration ago (2h, 3d), or checkpoint id (local mode).
+  --until <time>          Only intents at or before an RFC3339 time, duration ago, or checkpoint id (local mode).
+  --limit <n>             Max records to return (default 20; page size with --all, default 500).
+  --after <cursor>        Continue after the next_cursor of a previous page.
+  --all                   Stream every page.
+  --no-abbrev             Print full ids instead of the shortest unique prefix (7+ characters).
+  --format <template>     Print each intent with a Go template ('{{.ID}} {{.Title}}') or named template.
 
 show args:
-  <intent-id>             Intent id to show.
+  <intent-id>             Intent id, hash, or unique prefix of 4+ characters (local mode).
+  --format <template>     Print the intent with a Go template or named template.
+
+search args:
+  <query>                 Words to match in title, prompt, or response (all must match).
+  --fts                   Treat the query as SQLite FTS5 syntax (AND, OR, NOT, "phrases", prefix*).
+  --project <name>        Optional project filter.
+  --author <name>         Optional author filter.
+  --source <source>       Optional source filter.
+  --since <time>          Only intents at or after an RFC3339 time, duration ago (2h, 3d), or checkpoint id.
+  --until <time>          Only intents at or before an RFC3339 time, duration ago, or checkpoint id.
+  --limit <n>             Max results to return (default 20).
 
 mode args:
   (no args)              Show current mode.
@@ -130,17 +238,68 @@ project args:
   create <name>         Create a new project.
   use <name>            Set the active project.
   current               Show the active project.
-  list                  List projects.
+  list [--all]          List projects; --all includes archived ones.
+  describe <name> <text> Set a project's description.
+  archive <name>        Archive a project.
+  unarchive <name>      Restore an archived project.
+  rename <name> <new>   Rename a project that has no intents or checkpoints yet.
+  log [name]            Print the project ledger, or one project's history.
 
 checkpoint args:
   create --summary "..." Create a checkpoint for the active project.
+  create --no-git        Do not record git work tree context in meta.
   list                   List checkpoints for the active project.
+  list --since/--until <time>  Only checkpoints within a time range.
+  list --format <template>     Print each checkpoint with a Go template or named template.
+  verify <checkpoint-id>       Recompute a checkpoint hash and resolve its links (exit 5 on failure).
+  verify --all [--project <name>]  Verify every checkpoint.
+  verify --require-signed      Fail checkpoints that carry no signature.
+  log [checkpoint-id]          Print the lineage from the newest checkpoint to genesis, verifying each.
+
+key args:
+  generate [--owner <name>] [--trust]  Create a key in ~/.yanzi/keys; --trust adds it to the trusted-keys file.
+  list                   List local keys with their active and trusted state.
+  export [key-id]        Print a key's trusted-keys line (default: signing.key).
 
 rehydrate args:
   (no args)             Rehydrate the active project context.
+  --until <time>        Rehydrate as of a time: latest checkpoint and artifacts up to it.
+  --since <time>        Only list artifacts at or after a time.
 
 export args:
   --format markdown     Export active project history to ./YANZI_LOG.md.
+  --since/--until <time>  Only export history within a time range.
+
+import args:
+  transcript <file>     JSON array or NDJSON of {role, content} messages, or - for stdin.
+  chatgpt <file>        ChatGPT conversations.json export.
+  claude <file>         Claude data export conversations.json.
+  aider <file>          Aider .aider.chat.history.md file.
+  --author <name>       Author for imported turns (required unless every --route names one).
+  --project <name>      Target project (default: active project).
+  --route id=project[:author]  Send one conversation to another project/author (repeatable).
+  --list                List conversation ids, dates, turn counts, and titles without importing.
+
+attachment args:
+  get <digest>          Write an attachment's content to stdout.
+  --out <path>          Write to a file instead of stdout.
+
+meta args:
+  <command>             pause | resume | export | checkpoint "Summary" | role <Name>.
+  --file <path>         Scan agent text (or - for stdin) for lines starting with @yanzi.
+  --author <name>       Event author (default: active role, else Engineer).
+
+pause args:
+  --reason <text>       Optional reason shown in project current and rehydrate.
+
+resume args:
+  (no args)             Resume capture and store any queued captures.
+
+exit codes:
+  3                     capture skipped because the project is paused.
+  4                     capture queued because the project is paused.
+  5                     ledger tampered: verify or checkpoint verify/log found a mismatch, missing link, orphan, or bad signature.
+  6                     ledger unreadable: verify or checkpoint verify/log could not read the database or some rows.
 
 notes:
   mode set to http does not start libraryd.
@@ -148,10 +307,26 @@ notes:
 examples:
   yanzi capture --author "Ada" --prompt-file prompt.txt --response-file response.txt --meta lang=go
   yanzi capture --author "Ada" --prompt "Hello" --response "World"
+  agent-run | yanzi capture --author "Ada" --prompt "Hello" --response-file -
+  echo '{"prompt":"Hello","response":"World"}' | yanzi capture --author "Ada" --json -
+  yanzi capture --author "Ada" --edit
+  yanzi capture --author "ci" --batch records.ndjson
+  yanzi capture --author "Ada" --prompt "Hello" --response "World" --meta-json tokens=1200
+  yanzi capture --author "Ada" --prompt "Fix login" --response-file out.md --attach fix.diff
+  yanzi attachment get 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08 --out fix.diff
   yanzi verify 01HZX9Q4X8N9JZ1K2G9N8M4V3P
+  yanzi verify --all --project alpha
   yanzi chain 01HZX9Q4X8N9JZ1K2G9N8M4V3P
+  yanzi chain
+  yanzi chain --graph
   yanzi list --limit 10
+  yanzi list --since 1d
+  yanzi list --output ndjson --author "Ada"
+  yanzi list --all --output ndjson > ledger.ndjson
+  yanzi list --format '{{.ID}} {{truncate 50 .Title}}'
   yanzi show 01HZX9Q4X8N9JZ1K2G9N8M4V3P
+  yanzi show 9f86d08
+  yanzi search --since 7d "token refresh"
   yanzi mode
   yanzi mode local
   yanzi mode http
@@ -161,8 +336,18 @@ examples:
   yanzi project list
   yanzi checkpoint create --summary "Weekly snapshot"
   yanzi checkpoint list
+  yanzi checkpoint log
+  yanzi key generate --owner "Ada" --trust
+  yanzi key export >> team_trusted_keys
   yanzi rehydrate
   yanzi export --format markdown
+  yanzi import transcript --author "Ada" session.ndjson
+  yanzi import chatgpt --list conversations.json
+  yanzi import claude --author "Ada" --route 6f1c2d=billing:Bob conversations.json
+  yanzi meta checkpoint "Auth flow complete"
+  agent-run | yanzi meta --file -
+  yanzi pause --reason "exploratory spike"
+  yanzi resume
   yanzi version`)
 }
 
@@ -170,14 +355,38 @@ func isHelpArg(arg string) bool {
 	return arg == "-h" || arg == "--help" || arg == "?"
 }
 
+// versionInfo is the version command's structured output.
+type versionInfo struct {
+	Version string      `json:"version"`
+	Mode    config.Mode `json:"mode"`
+	BaseURL string      `json:"base_url,omitempty"`
+}
+
+func runVersion(args []string) error {
+	args, err := cmd.ParseArgs("version", args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return errors.New("usage: yanzi version")
+	}
+	return printVersion()
+}
+
 func printVersion() error {
 	cfg, err := config.Load()
 	if err != nil {
 		return err
 	}
-	fmt.Printf("yanzi %s\n", version)
-	fmt.Printf("mode: %s\n", formatMode(cfg))
-	return nil
+	info := versionInfo{Version: version, Mode: config.ModeLocal}
+	if cfg.Mode == config.ModeHTTP {
+		info.Mode = cfg.Mode
+		info.BaseURL = cfg.BaseURL
+	}
+	return cmd.PrintResult(info, func() {
+		fmt.Printf("yanzi %s\n", version)
+		fmt.Printf("mode: %s\n", formatMode(cfg))
+	})
 }
 
 func formatMode(cfg config.Config) string {
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/chuxorg/chux-yanzi-cli/internal/cmd"
 	"github.com/chuxorg/chux-yanzi-cli/internal/config"
 )
 
@@ -35,6 +36,31 @@ func TestFormatMode(t *testing.T) {
 	}
 }
 
+func TestSplitOutputFlag(t *testing.T) {
+	args, format, err := splitOutputFlag([]string{"--output", "json", "list", "--limit", "5"})
+	if err != nil || format != cmd.OutputJSON || strings.Join(args, " ") != "list --limit 5" {
+		t.Fatalf("unexpected split: %q %q %v", args, format, err)
+	}
+	args, format, err = splitOutputFlag([]string{"--output=yaml", "show", "id", "--", "--output=json"})
+	if err != nil || format != cmd.OutputYAML || strings.Join(args, " ") != "show id -- --output=json" {
+		t.Fatalf("unexpected split: %q %q %v", args, format, err)
+	}
+	// After the command name --output is left to the command's own flags.
+	args, format, err = splitOutputFlag([]string{"capture", "--title", "--output", "--prompt", "x"})
+	if err != nil || format != cmd.OutputText || strings.Join(args, " ") != "capture --title --output --prompt x" {
+		t.Fatalf("unexpected split: %q %q %v", args, format, err)
+	}
+	if _, format, _ := splitOutputFlag([]string{"list"}); format != cmd.OutputText {
+		t.Fatalf("expected text by default, got %q", format)
+	}
+	if _, _, err := splitOutputFlag([]string{"--output"}); err == nil {
+		t.Fatal("expected error for missing value")
+	}
+	if _, _, err := splitOutputFlag([]string{"--output", "xml", "list"}); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
+
 func TestUsagePrintsHelp(t *testing.T) {
 	output := captureStderr(t, func() {
 		usage()
@@ -51,6 +77,9 @@ func TestUsagePrintsHelp(t *testing.T) {
 	if !strings.Contains(output, "--meta key=value") {
 		t.Fatalf("expected capture metadata help text, got: %s", output)
 	}
+	if !strings.Contains(output, "  5                     ledger tampered") || !strings.Contains(output, "  6                     ledger unreadable") {
+		t.Fatalf("expected ledger exit codes in the exit codes section, got: %s", output)
+	}
 }
 
 func captureStderr(t *testing.T, fn func()) string {
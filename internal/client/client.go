@@ -31,6 +31,11 @@ type VerifyResponse struct {
 	ComputedHash string  `json:"computed_hash"`
 	PrevHash     string  `json:"prev_hash"`
 	Error        *string `json:"error"`
+	// KeyID, Signer, and SignatureStatus describe the intent's signature. Only local
+	// mode checks signatures; they are omitted otherwise.
+	KeyID           string `json:"key_id,omitempty"`
+	Signer          string `json:"signer,omitempty"`
+	SignatureStatus string `json:"signature_status,omitempty"`
 }
 
 // ChainResponse is returned by the /chain endpoint.
@@ -41,9 +46,11 @@ type ChainResponse struct {
 	MissingLinks []string       `json:"missing_links,omitempty"`
 }
 
-// ListResponse is returned by the /intents endpoint.
+// ListResponse is returned by the /intents endpoint. NextCursor is set when more
+// intents follow; pass it back as the after cursor to fetch the next page.
 type ListResponse struct {
-	Intents []IntentRecord `json:"intents"`
+	Intents    []IntentRecord `json:"intents"`
+	NextCursor string         `json:"next_cursor,omitempty"`
 }
 
 // CreateIntentRequest is the payload for POST /v0/intents.
@@ -96,8 +103,8 @@ func (c *Client) ChainIntent(ctx context.Context, id string) (ChainResponse, err
 	return out, nil
 }
 
-// ListIntents calls GET /v0/intents.
-func (c *Client) ListIntents(ctx context.Context, author, source string, limit int, metaFilters map[string]string) (ListResponse, error) {
+// ListIntents calls GET /v0/intents, starting after the after cursor when it is set.
+func (c *Client) ListIntents(ctx context.Context, author, source, after string, limit int, metaFilters map[string]string) (ListResponse, error) {
 	var out ListResponse
 	params := url.Values{}
 	if author != "" {
@@ -109,6 +116,9 @@ func (c *Client) ListIntents(ctx context.Context, author, source string, limit i
 	for key, value := range metaFilters {
 		params.Set("meta_"+key, value)
 	}
+	if after != "" {
+		params.Set("after", after)
+	}
 	if limit > 0 {
 		params.Set("limit", fmt.Sprintf("%d", limit))
 	}
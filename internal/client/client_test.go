@@ -39,15 +39,21 @@ func TestListIntentsQueryParams(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		gotQuery = r.URL.Query()
 		w.Header().Set("Content-Type", "application/json")
-		_, _ = w.Write([]byte(`{"intents": []}`))
+		_, _ = w.Write([]byte(`{"intents": [], "next_cursor": "next-page"}`))
 	}))
 	t.Cleanup(srv.Close)
 
 	cli := New(srv.URL)
-	_, err := cli.ListIntents(context.Background(), "alice", "cli", 25, map[string]string{"team": "core"})
+	resp, err := cli.ListIntents(context.Background(), "alice", "cli", "page-2", 25, map[string]string{"team": "core"})
 	if err != nil {
 		t.Fatalf("ListIntents error: %v", err)
 	}
+	if resp.NextCursor != "next-page" {
+		t.Fatalf("expected next_cursor, got %q", resp.NextCursor)
+	}
+	if gotQuery.Get("after") != "page-2" {
+		t.Fatalf("expected after=page-2, got %q", gotQuery.Get("after"))
+	}
 
 	if gotQuery.Get("author") != "alice" {
 		t.Fatalf("expected author=alice, got %q", gotQuery.Get("author"))
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
+)
+
+// RunAttachment handles attachment subcommands.
+func RunAttachment(args []string) error {
+	if len(args) == 0 {
+		return attachmentUsageError()
+	}
+
+	switch args[0] {
+	case "get":
+		return runAttachmentGet(args[1:])
+	default:
+		return attachmentUsageError()
+	}
+}
+
+func runAttachmentGet(args []string) error {
+	fs := newFlagSet("attachment get")
+	out := fs.String("out", "", "write the attachment to this path instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: yanzi attachment get [--out <path>] <digest>")
+	}
+	digest := strings.ToLower(strings.TrimSpace(fs.Arg(0)))
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Mode != config.ModeLocal {
+		return errors.New("attachments are only available in local mode")
+	}
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	content, err := loadAttachmentBlob(context.Background(), db, digest)
+	if err != nil {
+		return err
+	}
+	if *out != "" {
+		if err := os.WriteFile(*out, content, 0o644); err != nil {
+			return fmt.Errorf("write attachment: %w", err)
+		}
+		return printResult(attachmentFile{Digest: digest, Path: *out, Size: int64(len(content))}, func() {})
+	}
+	if _, err := os.Stdout.Write(content); err != nil {
+		return fmt.Errorf("write attachment: %w", err)
+	}
+	return nil
+}
+
+// attachmentFile describes an attachment written with --out in structured output.
+// Without --out the content itself is written to stdout in every output format.
+type attachmentFile struct {
+	Digest string `json:"digest"`
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+}
+
+// attachmentInput is an artifact read for a capture, held until the intent is stored.
+type attachmentInput struct {
+	Name      string `json:"name"`
+	MediaType string `json:"media_type,omitempty"`
+	Content   []byte `json:"content"`
+}
+
+// reference returns the digest reference recorded on the intent.
+func (a attachmentInput) reference() model.Attachment {
+	return model.Attachment{
+		Name:      a.Name,
+		Digest:    attachmentDigest(a.Content),
+		Size:      int64(len(a.Content)),
+		MediaType: a.MediaType,
+	}
+}
+
+// readAttachment loads a file for --attach, naming it by its base name and
+// detecting its media type from the extension, else from its content.
+func readAttachment(path string) (attachmentInput, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return attachmentInput{}, fmt.Errorf("read attachment: %w", err)
+	}
+	mediaType := mime.TypeByExtension(filepath.Ext(path))
+	if mediaType == "" {
+		mediaType = http.DetectContentType(content)
+	}
+	return attachmentInput{
+		Name:      filepath.Base(path),
+		MediaType: mediaType,
+		Content:   content,
+	}, nil
+}
+
+// attachmentDigest is the hex SHA-256 of an attachment's content.
+func attachmentDigest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// saveAttachmentBlob stores content once per digest; identical artifacts share a blob.
+func saveAttachmentBlob(ctx context.Context, conn sqlConn, attachment attachmentInput, createdAt string) error {
+	_, err := conn.ExecContext(
+		ctx,
+		`INSERT INTO attachment_blobs (digest, size, content, created_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(digest) DO NOTHING`,
+		attachmentDigest(attachment.Content),
+		len(attachment.Content),
+		attachment.Content,
+		createdAt,
+	)
+	if err != nil {
+		return fmt.Errorf("store attachment %s: %w", attachment.Name, err)
+	}
+	return nil
+}
+
+func loadAttachmentBlob(ctx context.Context, db *sql.DB, digest string) ([]byte, error) {
+	var content []byte
+	err := db.QueryRowContext(ctx, `SELECT content FROM attachment_blobs WHERE digest = ?`, digest).Scan(&content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("attachment not found: %s", digest)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// verifyAttachmentBlobs checks that every referenced blob exists and still hashes
+// to its digest.
+func verifyAttachmentBlobs(ctx context.Context, db *sql.DB, attachments []model.Attachment) error {
+	for _, attachment := range attachments {
+		content, err := loadAttachmentBlob(ctx, db, attachment.Digest)
+		if err != nil {
+			return fmt.Errorf("attachment %s: %w", attachment.Name, err)
+		}
+		if attachmentDigest(content) != attachment.Digest || int64(len(content)) != attachment.Size {
+			return fmt.Errorf("attachment %s: content does not match digest %s", attachment.Name, attachment.Digest)
+		}
+	}
+	return nil
+}
+
+// formatAttachment renders an attachment reference as "name digest (size bytes, type)".
+func formatAttachment(attachment model.Attachment) string {
+	details := fmt.Sprintf("%d bytes", attachment.Size)
+	if attachment.MediaType != "" {
+		details += ", " + attachment.MediaType
+	}
+	return fmt.Sprintf("%s %s (%s)", attachment.Name, attachment.Digest, details)
+}
+
+func attachmentUsageError() error {
+	return errors.New("usage: yanzi attachment get [--out <path>] <digest>")
+}
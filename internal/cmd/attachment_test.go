@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+)
+
+func TestCaptureAttachmentRoundTrip(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+
+	diffPath := filepath.Join(home, "fix.diff")
+	if err := os.WriteFile(diffPath, []byte("--- a\n+++ b\n"), 0o600); err != nil {
+		t.Fatalf("write attachment: %v", err)
+	}
+
+	output, err := captureStdout(func() error {
+		return RunCapture([]string{"--author", "Ada", "--prompt", "p", "--response", "r", "--attach", diffPath, "--attach", diffPath})
+	})
+	if err != nil {
+		t.Fatalf("RunCapture: %v", err)
+	}
+	record := loadCapturedIntent(t, output)
+	if len(record.Attachments) != 2 {
+		t.Fatalf("expected 2 attachment references, got %+v", record.Attachments)
+	}
+	attachment := record.Attachments[0]
+	if attachment.Name != "fix.diff" || attachment.Size != 12 || attachment.Digest != attachmentDigest([]byte("--- a\n+++ b\n")) {
+		t.Fatalf("unexpected attachment reference: %+v", attachment)
+	}
+
+	content, err := captureStdout(func() error {
+		return RunAttachment([]string{"get", attachment.Digest})
+	})
+	if err != nil {
+		t.Fatalf("RunAttachment get: %v", err)
+	}
+	if content != "--- a\n+++ b\n" {
+		t.Fatalf("unexpected attachment content: %q", content)
+	}
+
+	shown, err := captureStdout(func() error {
+		return RunShow([]string{record.ID})
+	})
+	if err != nil {
+		t.Fatalf("RunShow: %v", err)
+	}
+	if !strings.Contains(shown, "Attachments:\n  fix.diff "+attachment.Digest+" (12 bytes") {
+		t.Fatalf("expected attachment in show output, got %q", shown)
+	}
+
+	verified, err := captureStdout(func() error {
+		return RunVerify([]string{record.ID})
+	})
+	if err != nil {
+		t.Fatalf("RunVerify: %v", err)
+	}
+	if !strings.HasPrefix(verified, "✔ VALID") {
+		t.Fatalf("expected valid record, got %q", verified)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if _, err := db.ExecContext(context.Background(), `UPDATE attachment_blobs SET content = ? WHERE digest = ?`, []byte("tampered"), attachment.Digest); err != nil {
+		t.Fatalf("tamper blob: %v", err)
+	}
+	db.Close()
+
+	verified, err = captureStdout(func() error {
+		return RunVerify([]string{record.ID})
+	})
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitLedgerTampered {
+		t.Fatalf("expected RunVerify after tamper to exit %d, got %v", ExitLedgerTampered, err)
+	}
+	if !strings.HasPrefix(verified, "✖ INVALID") || !strings.Contains(verified, "content does not match digest") {
+		t.Fatalf("expected tampered attachment to fail verification, got %q", verified)
+	}
+}
+
+func TestCaptureJSONAttachment(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	withStdin(t, `{"prompt":"p","response":"r","attachments":[{"name":"note.txt","media_type":"text/plain","content":"aGVsbG8="}]}`)
+
+	output, err := captureStdout(func() error {
+		return RunCapture([]string{"--author", "Ada", "--json", "-"})
+	})
+	if err != nil {
+		t.Fatalf("RunCapture: %v", err)
+	}
+	record := loadCapturedIntent(t, output)
+	if len(record.Attachments) != 1 || record.Attachments[0].Digest != attachmentDigest([]byte("hello")) {
+		t.Fatalf("unexpected attachments: %+v", record.Attachments)
+	}
+}
@@ -1,127 +1,381 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"strings"
 	"unicode"
 
 	"github.com/chuxorg/chux-yanzi-cli/internal/client"
 	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	yanzilibrary "github.com/chuxorg/chux-yanzi-cli/internal/library"
 )
 
 // RunCapture posts a new intent record to the library API.
-func RunCapture(args []string) error {
-	fs := flag.NewFlagSet("capture", flag.ContinueOnError)
-	fs.SetOutput(os.Stderr)
+func RunCapture(args []string) (err error) {
+	fs := newFlagSet("capture")
 
 	var (
 		title      = fs.String("title", "", "optional title")
 		author     = fs.String("author", "", "required author")
 		source     = fs.String("source", "cli", "source type")
 		promptFlag = stringFlag{help: "prompt text (exclusive with --prompt-file)"}
-		promptFile = fs.String("prompt-file", "", "prompt file path (exclusive with --prompt)")
+		promptFile = fs.String("prompt-file", "", "prompt file path, or - for stdin (exclusive with --prompt)")
 		respFlag   = stringFlag{help: "response text (exclusive with --response-file)"}
-		respFile   = fs.String("response-file", "", "response file path (exclusive with --response)")
-		prevHash   = fs.String("prev-hash", "", "previous hash")
+		respFile   = fs.String("response-file", "", "response file path, or - for stdin (exclusive with --response)")
+		jsonFile   = fs.String("json", "", "capture object file path, or - for stdin (exclusive with prompt/response flags)")
+		batchFile  = fs.String("batch", "", "NDJSON file of capture objects, or - for stdin, stored in one transaction")
+		edit       = fs.Bool("edit", false, "compose the capture in $EDITOR (exclusive with prompt/response flags)")
+		whenPaused = fs.String("when-paused", "", "skip or queue the capture while the project is paused (default: config paused_capture)")
+		prevHash   = fs.String("prev-hash", "", "previous hash (default: head of the active project's chain)")
+		noChain    = fs.Bool("no-chain", false, "store the capture without linking it to the project chain")
+		noGit      = fs.Bool("no-git", false, "do not record git work tree context in meta")
+		dryRun     = fs.Bool("dry-run", false, "report what would be redacted without storing the capture")
 		metaPairs  = &kvPairs{}
+		metaJSON   = &jsonPairs{}
+		attachArgs = &stringList{}
+		mergeRefs  = &stringList{}
 	)
 	fs.Var(&promptFlag, "prompt", promptFlag.help)
 	fs.Var(&respFlag, "response", respFlag.help)
 	fs.Var(metaPairs, "meta", "key=value (optional, repeatable)")
+	fs.Var(metaJSON, "meta-json", "key=<json value> keeping its JSON type (optional, repeatable)")
+	fs.Var(attachArgs, "attach", "file to store as a content-addressed attachment (optional, repeatable)")
+	fs.Var(mergeRefs, "merge", "intent to join as an extra parent, reconciling a fork (optional, repeatable)")
 
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 
-	if *author == "" {
-		return errors.New("--author is required")
-	}
-	promptInline := promptFlag.set
-	promptFromFile := *promptFile != ""
-	if promptInline == promptFromFile {
-		return errors.New("exactly one of --prompt or --prompt-file must be provided")
-	}
-	responseInline := respFlag.set
-	responseFromFile := *respFile != ""
-	if responseInline == responseFromFile {
-		return errors.New("exactly one of --response or --response-file must be provided")
+	// Flag values are read when the defaults are loaded, after --edit has filled in the title.
+	loadDefaults := func() (captureDefaults, error) {
+		return newCaptureDefaults(fs, captureFlagValues{
+			author:   *author,
+			title:    *title,
+			source:   *source,
+			prevHash: *prevHash,
+			noGit:    *noGit,
+		}, metaPairs, metaJSON)
 	}
 
-	hasStdin, err := stdinHasData()
-	if err != nil {
-		return err
+	if *edit && *author == "" {
+		return errors.New("--author is required")
 	}
 
-	if hasStdin {
-		return errors.New("stdin is not supported; use --prompt or --prompt-file")
+	if *batchFile != "" {
+		if *edit || *jsonFile != "" || promptFlag.set || *promptFile != "" || respFlag.set || *respFile != "" || len(*attachArgs) > 0 || *whenPaused != "" || *prevHash != "" || len(*mergeRefs) > 0 {
+			return errors.New("--batch cannot be combined with --edit, --json, --attach, --when-paused, --prev-hash, --merge, --prompt, --prompt-file, --response, or --response-file")
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		defaults, err := loadDefaults()
+		if err != nil {
+			return err
+		}
+		return runBatchCapture(cfg, *batchFile, defaults, *noChain, *dryRun)
 	}
 
-	var promptContent []byte
+	var input createIntentInput
 	switch {
-	case promptInline:
-		promptContent = []byte(promptFlag.value)
-	case promptFromFile:
-		content, err := os.ReadFile(*promptFile)
+	case *edit:
+		if promptFlag.set || *promptFile != "" || respFlag.set || *respFile != "" || *jsonFile != "" {
+			return errors.New("--edit cannot be combined with --json, --prompt, --prompt-file, --response, or --response-file")
+		}
+		flagMeta, metaErr := metaPairs.ToJSON()
+		if metaErr != nil {
+			return metaErr
+		}
+		edited, path, editErr := readCaptureFromEditor(createIntentInput{Title: *title, Meta: flagMeta})
+		if editErr != nil {
+			return editErr
+		}
+		// The template already carries the flag title and meta; keep the file until the capture is stored.
+		*metaPairs = nil
+		*title = edited.Title
+		defer func() {
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "capture template kept at %s\n", path)
+				return
+			}
+			_ = os.Remove(path)
+		}()
+		input = edited
+	case *jsonFile != "":
+		if promptFlag.set || *promptFile != "" || respFlag.set || *respFile != "" {
+			return errors.New("--json cannot be combined with --prompt, --prompt-file, --response, or --response-file")
+		}
+		decoded, err := readCaptureJSON(*jsonFile)
 		if err != nil {
-			return fmt.Errorf("read prompt file: %w", err)
+			return err
 		}
-		promptContent = content
+		input = decoded
 	default:
-		return errors.New("prompt must be provided via --prompt or --prompt-file")
+		if *promptFile == stdinPath && *respFile == stdinPath {
+			return errors.New("only one of --prompt-file or --response-file may read stdin; use --json - to pipe both")
+		}
+		promptContent, err := readCaptureText("prompt", promptFlag, *promptFile)
+		if err != nil {
+			return err
+		}
+		responseContent, err := readCaptureText("response", respFlag, *respFile)
+		if err != nil {
+			return err
+		}
+		input.Prompt = promptContent
+		input.Response = responseContent
 	}
 
-	var responseContent []byte
-	switch {
-	case responseInline:
-		responseContent = []byte(respFlag.value)
-	case responseFromFile:
-		content, err := os.ReadFile(*respFile)
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	defaults, err := loadDefaults()
+	if err != nil {
+		return err
+	}
+	input, err = defaults.apply(input)
+	if err != nil {
+		return err
+	}
+	for _, path := range *attachArgs {
+		attachment, err := readAttachment(path)
 		if err != nil {
-			return fmt.Errorf("read response file: %w", err)
+			return err
 		}
-		responseContent = content
-	default:
-		return errors.New("response must be provided via --response or --response-file")
+		input.Attachments = append(input.Attachments, attachment)
 	}
 
-	meta, err := metaPairs.ToJSON()
+	if *noChain && (input.PrevHash != "" || len(*mergeRefs) > 0) {
+		return errors.New("--no-chain cannot be combined with a previous hash or --merge")
+	}
+	if len(*mergeRefs) > 0 {
+		parents, err := resolveMergeParents(cfg, *mergeRefs)
+		if err != nil {
+			return err
+		}
+		input.Meta, err = attachMergeParentsMeta(input.Meta, parents)
+		if err != nil {
+			return err
+		}
+	}
+	chainProject := defaults.project
+	if *noChain {
+		chainProject = ""
+	}
+	redactor, err := newRedactor(cfg)
 	if err != nil {
 		return err
 	}
-	activeProject, err := loadActiveProject()
+	input, report, err := redactCapture(redactor, input)
 	if err != nil {
 		return err
 	}
-	meta, err = attachProjectMeta(meta, activeProject)
+	if *dryRun {
+		return printRedactionReport(report)
+	}
+	// Pre-hooks run only for captures that are stored now; queued captures run them
+	// when the queue is drained.
+	if defaults.project != "" {
+		pause, paused, err := loadPauseState(defaults.project)
+		if err != nil {
+			return err
+		}
+		if paused {
+			mode := cfg.PausedCapture
+			if *whenPaused != "" {
+				mode = config.PausedCapture(*whenPaused)
+			}
+			return handlePausedCapture(mode, defaults.project, pause, input, !*noChain)
+		}
+	}
+	input, err = runCapturePreHooks(cfg, redactor, input)
 	if err != nil {
 		return err
 	}
-
-	cfg, err := config.Load()
+	intent, err := storeIntent(cfg, input, chainProject)
 	if err != nil {
 		return err
 	}
-	input := createIntentInput{
-		Author:     *author,
-		SourceType: *source,
-		Title:      *title,
-		Prompt:     string(promptContent),
-		Response:   string(responseContent),
-		PrevHash:   *prevHash,
-		Meta:       meta,
+
+	if err := printResult(intent, func() {
+		fmt.Printf("id: %s\n", intent.ID)
+		fmt.Printf("hash: %s\n", intent.Hash)
+		if intent.PrevHash != "" {
+			fmt.Printf("prev_hash: %s\n", intent.PrevHash)
+		}
+	}); err != nil {
+		return err
+	}
+	runPostHooks(hookPostCapture, cfg.Hooks.PostCapture, intent)
+
+	return nil
+}
+
+// resolveMergeParents turns --merge references into intent hashes. Local mode accepts an
+// id, hash, or unique prefix of either; http mode sends each value as a hash unchanged.
+func resolveMergeParents(cfg config.Config, refs []string) ([]string, error) {
+	if cfg.Mode != config.ModeLocal {
+		return refs, nil
+	}
+	ctx := context.Background()
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	parents := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		record, err := dbResolveIntent(ctx, db, ref)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("--merge: intent not found: %s", ref)
+			}
+			return nil, fmt.Errorf("--merge: %w", err)
+		}
+		parents = append(parents, record.Hash)
 	}
+	return parents, nil
+}
+
+// attachMergeParentsMeta records merge parents under the merge_parents meta key,
+// replacing any value given with --meta.
+func attachMergeParentsMeta(meta json.RawMessage, parents []string) (json.RawMessage, error) {
+	overlay, err := json.Marshal(map[string][]string{yanzilibrary.MergeParentsMetaKey: parents})
+	if err != nil {
+		return nil, err
+	}
+	return mergeMeta(meta, overlay)
+}
+
+// captureFlagValues are the capture flags that apply to every record in a run.
+type captureFlagValues struct {
+	author   string
+	title    string
+	source   string
+	prevHash string
+	noGit    bool
+}
+
+// captureDefaults completes and validates capture inputs with the values shared by a run:
+// explicitly set flags, --meta and --meta-json pairs, git context, and the active project.
+type captureDefaults struct {
+	flags   captureFlagValues
+	set     map[string]bool
+	meta    json.RawMessage
+	gitMeta json.RawMessage
+	project string
+}
 
-	var intent client.IntentRecord
+// newCaptureDefaults resolves the shared capture values once per run.
+func newCaptureDefaults(fs *flag.FlagSet, flags captureFlagValues, metaPairs *kvPairs, metaJSON *jsonPairs) (captureDefaults, error) {
+	defaults := captureDefaults{flags: flags, set: map[string]bool{}}
+	fs.Visit(func(f *flag.Flag) {
+		defaults.set[f.Name] = true
+	})
+
+	flagMeta, err := metaPairs.ToJSON()
+	if err != nil {
+		return captureDefaults{}, err
+	}
+	typedMeta, err := metaJSON.ToJSON()
+	if err != nil {
+		return captureDefaults{}, err
+	}
+	defaults.meta, err = mergeMeta(flagMeta, typedMeta)
+	if err != nil {
+		return captureDefaults{}, err
+	}
+	if !flags.noGit {
+		defaults.gitMeta, err = gitContextMeta()
+		if err != nil {
+			return captureDefaults{}, err
+		}
+	}
+	defaults.project, err = loadActiveProject()
+	if err != nil {
+		return captureDefaults{}, err
+	}
+	return defaults, nil
+}
+
+// apply overlays explicit flags onto input, fills defaults, validates the result, and
+// assembles its meta. Explicit flags take precedence over fields decoded from JSON.
+func (d captureDefaults) apply(input createIntentInput) (createIntentInput, error) {
+	if d.set["author"] {
+		input.Author = d.flags.author
+	}
+	if d.set["title"] {
+		input.Title = d.flags.title
+	}
+	if d.set["source"] {
+		input.SourceType = d.flags.source
+	}
+	if d.set["prev-hash"] {
+		input.PrevHash = d.flags.prevHash
+	}
+	if input.SourceType == "" {
+		input.SourceType = d.flags.source
+	}
+	if err := validateCaptureInput(input); err != nil {
+		return createIntentInput{}, err
+	}
+
+	meta, err := mergeMeta(input.Meta, d.meta)
+	if err != nil {
+		return createIntentInput{}, err
+	}
+	meta, err = mergeMeta(d.gitMeta, meta)
+	if err != nil {
+		return createIntentInput{}, err
+	}
+	input.Meta, err = attachProjectMeta(meta, d.project)
+	if err != nil {
+		return createIntentInput{}, err
+	}
+	return input, nil
+}
+
+// validateCaptureInput checks the fields every stored capture requires.
+func validateCaptureInput(input createIntentInput) error {
+	if input.Author == "" {
+		return errors.New("--author is required")
+	}
+	if input.Prompt == "" {
+		return errors.New("prompt must not be empty")
+	}
+	if input.Response == "" {
+		return errors.New("response must not be empty")
+	}
+	for _, attachment := range input.Attachments {
+		if strings.TrimSpace(attachment.Name) == "" {
+			return errors.New("attachment name must not be empty")
+		}
+	}
+	return nil
+}
+
+// storeIntent persists a prepared intent through the configured mode and returns the stored record.
+// In local mode a non-empty chainProject links the intent to that project's chain head; the
+// HTTP library assigns no chain, so only an explicit input.PrevHash is sent there.
+func storeIntent(cfg config.Config, input createIntentInput, chainProject string) (client.IntentRecord, error) {
 	switch cfg.Mode {
 	case config.ModeHTTP:
+		if cfg.Signing.Key != "" {
+			return client.IntentRecord{}, errSigningLocalOnly
+		}
+		if len(input.Attachments) > 0 {
+			return client.IntentRecord{}, errors.New("attachments are only available in local mode")
+		}
 		cli := client.New(cfg.BaseURL)
 		req := client.CreateIntentRequest{
 			Author:     input.Author,
@@ -134,37 +388,139 @@ func RunCapture(args []string) error {
 		if input.Meta != nil {
 			req.Meta = input.Meta
 		}
-		intent, err = cli.CreateIntent(context.Background(), req)
+		intent, err := cli.CreateIntent(context.Background(), req)
 		if err != nil {
-			return fmt.Errorf("http request to %s failed: %w", cfg.BaseURL, err)
+			return client.IntentRecord{}, fmt.Errorf("http request to %s failed: %w", cfg.BaseURL, err)
 		}
+		return intent, nil
 	case config.ModeLocal:
+		signer, err := loadSigner(cfg)
+		if err != nil {
+			return client.IntentRecord{}, err
+		}
 		ctx := context.Background()
 		db, err := openLocalDB(cfg)
 		if err != nil {
-			return err
+			return client.IntentRecord{}, err
 		}
 		defer db.Close()
 
-		record, err := buildLocalIntent(input)
+		record, err := storeLocalIntent(ctx, db, input, chainProject, signer)
 		if err != nil {
-			return err
+			return client.IntentRecord{}, err
 		}
-		if err := createLocalIntent(ctx, db, record); err != nil {
-			return err
-		}
-		intent = record
+		return record, nil
 	default:
-		return fmt.Errorf("invalid mode: %s", cfg.Mode)
+		return client.IntentRecord{}, fmt.Errorf("invalid mode: %s", cfg.Mode)
 	}
+}
 
-	fmt.Printf("id: %s\n", intent.ID)
-	fmt.Printf("hash: %s\n", intent.Hash)
+// stdinPath is the file argument that selects stdin as a capture source.
+const stdinPath = "-"
 
-	if err := saveLastHash(intent.Hash); err != nil {
-		return err
+// readCaptureText resolves prompt or response content from exactly one of an
+// inline flag or a file path ("-" reads stdin), trimming trailing whitespace.
+func readCaptureText(name string, inline stringFlag, path string) (string, error) {
+	fromFile := path != ""
+	if inline.set == fromFile {
+		return "", fmt.Errorf("exactly one of --%s or --%s-file must be provided", name, name)
+	}
+	if inline.set {
+		return trimCaptureText(inline.value), nil
+	}
+	if path == stdinPath {
+		content, err := readStdin()
+		if err != nil {
+			return "", err
+		}
+		return trimCaptureText(string(content)), nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read %s file: %w", name, err)
+	}
+	return trimCaptureText(string(content)), nil
+}
+
+// readCaptureJSON decodes a single capture object from a file or stdin.
+func readCaptureJSON(path string) (createIntentInput, error) {
+	var data []byte
+	var err error
+	if path == stdinPath {
+		data, err = readStdin()
+	} else {
+		data, err = os.ReadFile(path)
+		if err != nil {
+			err = fmt.Errorf("read capture json: %w", err)
+		}
+	}
+	if err != nil {
+		return createIntentInput{}, err
+	}
+	return decodeCaptureJSON(data)
+}
+
+// decodeCaptureJSON parses a capture object and applies the shared text trimming rules.
+func decodeCaptureJSON(data []byte) (createIntentInput, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var input createIntentInput
+	if err := dec.Decode(&input); err != nil {
+		return createIntentInput{}, fmt.Errorf("decode capture json: %w", err)
+	}
+	if dec.More() {
+		return createIntentInput{}, errors.New("decode capture json: expected a single JSON object")
+	}
+	if len(input.Meta) > 0 && string(input.Meta) == "null" {
+		input.Meta = nil
+	}
+	input.Prompt = trimCaptureText(input.Prompt)
+	input.Response = trimCaptureText(input.Response)
+	return input, nil
+}
+
+// trimCaptureText applies the capture trimming rule: trailing whitespace is removed,
+// everything else is preserved, regardless of where the text came from.
+func trimCaptureText(value string) string {
+	return strings.TrimRightFunc(value, unicode.IsSpace)
+}
+
+// mergeMeta overlays the keys of overlay onto base, preserving raw JSON values.
+func mergeMeta(base, overlay json.RawMessage) (json.RawMessage, error) {
+	if len(overlay) == 0 {
+		return base, nil
+	}
+	if len(base) == 0 {
+		return overlay, nil
+	}
+
+	merged := map[string]json.RawMessage{}
+	if err := json.Unmarshal(base, &merged); err != nil {
+		return nil, fmt.Errorf("decode meta: %w", err)
+	}
+	var extra map[string]json.RawMessage
+	if err := json.Unmarshal(overlay, &extra); err != nil {
+		return nil, fmt.Errorf("decode meta: %w", err)
 	}
+	for key, value := range extra {
+		merged[key] = value
+	}
+	encoded, err := json.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("encode meta: %w", err)
+	}
+	return json.RawMessage(encoded), nil
+}
+
+// stringList collects a repeatable string flag.
+type stringList []string
 
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
 	return nil
 }
 
@@ -199,6 +555,37 @@ func (k *kvPairs) ToJSON() (json.RawMessage, error) {
 	return json.RawMessage(b), nil
 }
 
+// jsonPairs collects repeated key=<json> flags whose values keep their JSON type.
+type jsonPairs []string
+
+func (j *jsonPairs) String() string {
+	return strings.Join(*j, ",")
+}
+
+func (j *jsonPairs) Set(value string) error {
+	*j = append(*j, value)
+	return nil
+}
+
+func (j *jsonPairs) ToJSON() (json.RawMessage, error) {
+	if len(*j) == 0 {
+		return nil, nil
+	}
+	obj := make(map[string]json.RawMessage, len(*j))
+	for _, pair := range *j {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || !json.Valid([]byte(parts[1])) {
+			return nil, fmt.Errorf("invalid --meta-json argument %q (expected key=<json>)", pair)
+		}
+		obj[parts[0]] = json.RawMessage(parts[1])
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("encode meta: %w", err)
+	}
+	return json.RawMessage(b), nil
+}
+
 // stringFlag tracks whether a string flag was explicitly set.
 type stringFlag struct {
 	set   bool
@@ -216,19 +603,20 @@ func (s *stringFlag) Set(value string) error {
 	return nil
 }
 
-func saveLastHash(hash string) error {
-	dir, err := config.StateDir()
+// readStdin reads all of stdin, refusing to block on an interactive terminal.
+func readStdin() ([]byte, error) {
+	hasData, err := stdinHasData()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if err := os.MkdirAll(dir, 0o700); err != nil {
-		return fmt.Errorf("create state dir: %w", err)
+	if !hasData {
+		return nil, errors.New("stdin is a terminal; pipe input or pass a file path")
 	}
-	path := filepath.Join(dir, "last_hash")
-	if err := os.WriteFile(path, []byte(hash+"\n"), 0o600); err != nil {
-		return fmt.Errorf("write last hash: %w", err)
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("read stdin: %w", err)
 	}
-	return nil
+	return data, nil
 }
 
 // stdinHasData reports whether stdin is connected to a non-terminal input.
@@ -239,49 +627,3 @@ func stdinHasData() (bool, error) {
 	}
 	return info.Mode()&os.ModeCharDevice == 0, nil
 }
-
-// readPromptFromStdin reads stdin and trims trailing whitespace only.
-func readPromptFromStdin() ([]byte, error) {
-	data, err := io.ReadAll(os.Stdin)
-	if err != nil {
-		return nil, fmt.Errorf("read stdin: %w", err)
-	}
-	trimmed := strings.TrimRightFunc(string(data), unicode.IsSpace)
-	return []byte(trimmed), nil
-}
-
-// readPromptFromEditor opens $EDITOR to capture the prompt text.
-func readPromptFromEditor() ([]byte, error) {
-	editor := strings.TrimSpace(os.Getenv("EDITOR"))
-	if editor == "" {
-		return nil, errors.New("$EDITOR is not set")
-	}
-
-	tmp, err := os.CreateTemp("", "yanzi-prompt-*.txt")
-	if err != nil {
-		return nil, fmt.Errorf("create temp file: %w", err)
-	}
-	path := tmp.Name()
-	if err := tmp.Close(); err != nil {
-		return nil, fmt.Errorf("close temp file: %w", err)
-	}
-	defer os.Remove(path)
-
-	fields := strings.Fields(editor)
-	if len(fields) == 0 {
-		return nil, errors.New("invalid $EDITOR value")
-	}
-	cmd := exec.Command(fields[0], append(fields[1:], path)...)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("editor failed: %w", err)
-	}
-
-	content, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("read temp file: %w", err)
-	}
-	return content, nil
-}
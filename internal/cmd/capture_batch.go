@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
+)
+
+// batchLine is one capture object read from a batch file with its 1-based line number.
+type batchLine struct {
+	Number int
+	Input  createIntentInput
+}
+
+// batchCapture is one stored batch record in structured output.
+type batchCapture struct {
+	Line   int                `json:"line"`
+	Intent model.IntentRecord `json:"intent"`
+}
+
+// runBatchCapture validates and redacts every capture in an NDJSON batch file, passing
+// each through the pre_capture hooks, before storing any, then stores them all in one
+// local transaction. Unless noChain is set, records link to the active project's chain
+// head and to each other in file order.
+func runBatchCapture(cfg config.Config, path string, defaults captureDefaults, noChain, dryRun bool) error {
+	if cfg.Mode != config.ModeLocal && !dryRun {
+		return errors.New("batch capture is only available in local mode")
+	}
+	lines, err := readCaptureBatch(path)
+	if err != nil {
+		return err
+	}
+	if defaults.project != "" && !dryRun {
+		pause, paused, err := loadPauseState(defaults.project)
+		if err != nil {
+			return err
+		}
+		if paused {
+			return fmt.Errorf("project %s is %s; resume before a batch capture", defaults.project, describePause(pause))
+		}
+	}
+	redactor, err := newRedactor(cfg)
+	if err != nil {
+		return err
+	}
+
+	inputs := make([]createIntentInput, 0, len(lines))
+	reports := make([][]captureRedaction, 0, len(lines))
+	for _, line := range lines {
+		input, err := defaults.apply(line.Input)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line.Number, err)
+		}
+		if noChain && input.PrevHash != "" {
+			return fmt.Errorf("line %d: --no-chain cannot be combined with a previous hash", line.Number)
+		}
+		input, report, err := redactCapture(redactor, input)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line.Number, err)
+		}
+		if !dryRun {
+			input, err = runCapturePreHooks(cfg, redactor, input)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", line.Number, err)
+			}
+		}
+		inputs = append(inputs, input)
+		reports = append(reports, report)
+	}
+	if dryRun {
+		return printBatchRedactionReport(lines, reports)
+	}
+
+	chainProject := defaults.project
+	if noChain {
+		chainProject = ""
+	}
+
+	signer, err := loadSigner(cfg)
+	if err != nil {
+		return err
+	}
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	records, err := storeLocalIntents(context.Background(), db, inputs, chainProject, signer)
+	if err != nil {
+		return err
+	}
+
+	captures := make([]batchCapture, 0, len(records))
+	for i, record := range records {
+		captures = append(captures, batchCapture{Line: lines[i].Number, Intent: record})
+	}
+	if err := printList("captures", captures, func() {
+		fmt.Println("Line\tID\tHash")
+		for _, capture := range captures {
+			fmt.Printf("%d\t%s\t%s\n", capture.Line, capture.Intent.ID, capture.Intent.Hash)
+		}
+	}); err != nil {
+		return err
+	}
+	for _, record := range records {
+		runPostHooks(hookPostCapture, cfg.Hooks.PostCapture, record)
+	}
+	return nil
+}
+
+// readCaptureBatch decodes one capture object per non-blank line of a file or stdin.
+func readCaptureBatch(path string) ([]batchLine, error) {
+	var data []byte
+	var err error
+	if path == stdinPath {
+		data, err = readStdin()
+	} else {
+		data, err = os.ReadFile(path)
+		if err != nil {
+			err = fmt.Errorf("read batch file: %w", err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []batchLine
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		input, err := decodeCaptureJSON(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+		lines = append(lines, batchLine{Number: number, Input: input})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read batch file: %w", err)
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("batch file contains no captures")
+	}
+	return lines, nil
+}
+
+// printBatchRedactionReport lists what each batch line would have redacted, without the values.
+func printBatchRedactionReport(lines []batchLine, reports [][]captureRedaction) error {
+	var entries []redactionEntry
+	for i, report := range reports {
+		entries = append(entries, redactionEntries(lines[i].Number, report)...)
+	}
+	return printList("redactions", entries, func() {
+		fmt.Println("Line\tField\tRule\tCount")
+		for _, entry := range entries {
+			fmt.Printf("%d\t%s\t%s\t%d\n", entry.Line, entry.Field, entry.Rule, entry.Count)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+)
+
+func TestRunCaptureBatchChainsRecords(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	path := filepath.Join(home, "records.ndjson")
+	writeBatchFile(t, path,
+		`{"prompt":"one","response":"r1"}`,
+		``,
+		`{"author":"Bob","prompt":"two","response":"r2","meta":{"step":2}}`,
+		`{"prompt":"three","response":"r3"}`,
+	)
+
+	output, err := captureStdout(func() error {
+		return RunCapture([]string{"--author", "Ada", "--no-git", "--batch", path})
+	})
+	if err != nil {
+		t.Fatalf("RunCapture --batch: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 4 || lines[0] != "Line\tID\tHash" {
+		t.Fatalf("unexpected output %q", output)
+	}
+	for i, want := range []string{"1\t", "3\t", "4\t"} {
+		if !strings.HasPrefix(lines[i+1], want) {
+			t.Fatalf("expected row for line %s, got %q", want, lines[i+1])
+		}
+	}
+	if prompts := loadImportedChain(t); strings.Join(prompts, ",") != "one,two,three" {
+		t.Fatalf("expected chained records in file order, got %v", prompts)
+	}
+}
+
+func TestRunCaptureBatchIsAllOrNothing(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+
+	path := filepath.Join(home, "records.ndjson")
+	writeBatchFile(t, path,
+		`{"author":"Ada","prompt":"one","response":"r1"}`,
+		`{"author":"Ada","prompt":"two","response":""}`,
+	)
+
+	err := RunCapture([]string{"--no-git", "--batch", path})
+	if err == nil || !strings.Contains(err.Error(), "line 2: response must not be empty") {
+		t.Fatalf("expected line 2 validation error, got %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+	intents, err := listLocalIntentsFromDB(context.Background(), db, 10)
+	if err != nil {
+		t.Fatalf("list intents: %v", err)
+	}
+	if len(intents) != 0 {
+		t.Fatalf("expected nothing stored, got %d intents", len(intents))
+	}
+}
+
+func writeBatchFile(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatalf("write batch file: %v", err)
+	}
+}
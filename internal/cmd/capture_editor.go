@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// errEmptyCaptureTemplate aborts an editor capture when every section was left blank.
+var errEmptyCaptureTemplate = errors.New("aborting capture due to empty template")
+
+const captureTemplateHeader = `# Yanzi capture. Fill in the sections below, then save and close the editor.
+# Lines starting with '#' above the first section and in the meta section are ignored.
+# Leave every section empty to abort the capture.
+`
+
+// captureTemplateSections lists the editor template sections in the order they are rendered.
+var captureTemplateSections = []string{"title", "prompt", "response", "meta"}
+
+// readCaptureFromEditor opens $EDITOR on a capture template seeded from initial and
+// parses the saved result. The returned path is the template file, which callers
+// remove once the capture is stored. Parse failures keep the file and report its path;
+// an empty template removes it and returns errEmptyCaptureTemplate.
+func readCaptureFromEditor(initial createIntentInput) (createIntentInput, string, error) {
+	editor := strings.TrimSpace(os.Getenv("EDITOR"))
+	if editor == "" {
+		return createIntentInput{}, "", errors.New("$EDITOR is not set")
+	}
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return createIntentInput{}, "", errors.New("invalid $EDITOR value")
+	}
+
+	template, err := renderCaptureTemplate(initial)
+	if err != nil {
+		return createIntentInput{}, "", err
+	}
+
+	tmp, err := os.CreateTemp("", "yanzi-capture-*.txt")
+	if err != nil {
+		return createIntentInput{}, "", fmt.Errorf("create temp file: %w", err)
+	}
+	path := tmp.Name()
+	if _, err := tmp.WriteString(template); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(path)
+		return createIntentInput{}, "", fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(path)
+		return createIntentInput{}, "", fmt.Errorf("close temp file: %w", err)
+	}
+
+	cmd := exec.Command(fields[0], append(fields[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return createIntentInput{}, "", fmt.Errorf("editor failed: %w (template kept at %s)", err, path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return createIntentInput{}, "", fmt.Errorf("read temp file: %w", err)
+	}
+
+	input, err := parseCaptureTemplate(string(content))
+	if errors.Is(err, errEmptyCaptureTemplate) {
+		_ = os.Remove(path)
+		return createIntentInput{}, "", err
+	}
+	if err != nil {
+		return createIntentInput{}, "", fmt.Errorf("%w (template kept at %s)", err, path)
+	}
+	return input, path, nil
+}
+
+// renderCaptureTemplate renders the editor template with any values already known.
+func renderCaptureTemplate(initial createIntentInput) (string, error) {
+	var metaLines []string
+	if len(initial.Meta) > 0 {
+		var meta map[string]json.RawMessage
+		if err := json.Unmarshal(initial.Meta, &meta); err != nil {
+			return "", fmt.Errorf("decode meta: %w", err)
+		}
+		for key, raw := range meta {
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				value = string(raw)
+			}
+			metaLines = append(metaLines, key+"="+value)
+		}
+		sort.Strings(metaLines)
+	}
+
+	values := map[string]string{
+		"title":    initial.Title,
+		"prompt":   initial.Prompt,
+		"response": initial.Response,
+		"meta":     "# One key=value pair per line.\n" + strings.Join(metaLines, "\n"),
+	}
+
+	var b strings.Builder
+	b.WriteString(captureTemplateHeader)
+	for _, name := range captureTemplateSections {
+		b.WriteString(captureSectionMarker(name))
+		b.WriteByte('\n')
+		if value := strings.TrimRight(values[name], "\n"); value != "" {
+			b.WriteString(value)
+			b.WriteByte('\n')
+		}
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+// parseCaptureTemplate reads the sections of an edited capture template back into an input.
+func parseCaptureTemplate(content string) (createIntentInput, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	sections := map[string][]string{}
+	current := ""
+	for _, line := range strings.Split(content, "\n") {
+		if name, ok := parseCaptureSectionMarker(line); ok {
+			if !isCaptureTemplateSection(name) {
+				return createIntentInput{}, fmt.Errorf("unknown template section %q", name)
+			}
+			if _, seen := sections[name]; seen {
+				return createIntentInput{}, fmt.Errorf("duplicate template section %q", name)
+			}
+			sections[name] = []string{}
+			current = name
+			continue
+		}
+		if current == "" || (current == "meta" && strings.HasPrefix(line, "#")) {
+			continue
+		}
+		sections[current] = append(sections[current], line)
+	}
+
+	text := func(name string) string {
+		return trimCaptureText(strings.TrimLeft(strings.Join(sections[name], "\n"), "\n"))
+	}
+	input := createIntentInput{
+		Title:    strings.TrimSpace(text("title")),
+		Prompt:   text("prompt"),
+		Response: text("response"),
+	}
+	metaText := text("meta")
+	if input.Title == "" && input.Prompt == "" && input.Response == "" && strings.TrimSpace(metaText) == "" {
+		return createIntentInput{}, errEmptyCaptureTemplate
+	}
+
+	var pairs kvPairs
+	for _, line := range strings.Split(metaText, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			pairs = append(pairs, line)
+		}
+	}
+	meta, err := pairs.ToJSON()
+	if err != nil {
+		return createIntentInput{}, err
+	}
+	input.Meta = meta
+
+	if input.Prompt == "" {
+		return createIntentInput{}, errors.New("prompt section is empty")
+	}
+	if input.Response == "" {
+		return createIntentInput{}, errors.New("response section is empty")
+	}
+	return input, nil
+}
+
+func captureSectionMarker(name string) string {
+	return "==== " + name + " ===="
+}
+
+func parseCaptureSectionMarker(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "==== ") || !strings.HasSuffix(line, " ====") || len(line) <= len("==== ====") {
+		return "", false
+	}
+	return strings.TrimSpace(line[len("==== ") : len(line)-len(" ====")]), true
+}
+
+func isCaptureTemplateSection(name string) bool {
+	for _, section := range captureTemplateSections {
+		if section == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCaptureTemplateRoundTrip(t *testing.T) {
+	template, err := renderCaptureTemplate(createIntentInput{
+		Title: "Auth refactor",
+		Meta:  json.RawMessage(`{"area":"auth"}`),
+	})
+	if err != nil {
+		t.Fatalf("renderCaptureTemplate: %v", err)
+	}
+	edited := strings.Replace(template, "==== prompt ====\n", "==== prompt ====\n# Heading kept\nSplit the module.\n", 1)
+	edited = strings.Replace(edited, "==== response ====\n", "==== response ====\nDone.  \n\n", 1)
+
+	input, err := parseCaptureTemplate(edited)
+	if err != nil {
+		t.Fatalf("parseCaptureTemplate: %v", err)
+	}
+	if input.Title != "Auth refactor" {
+		t.Fatalf("unexpected title: %q", input.Title)
+	}
+	if input.Prompt != "# Heading kept\nSplit the module." {
+		t.Fatalf("unexpected prompt: %q", input.Prompt)
+	}
+	if input.Response != "Done." {
+		t.Fatalf("unexpected response: %q", input.Response)
+	}
+	if string(input.Meta) != `{"area":"auth"}` {
+		t.Fatalf("unexpected meta: %s", input.Meta)
+	}
+}
+
+func TestParseCaptureTemplateEmptyAborts(t *testing.T) {
+	template, err := renderCaptureTemplate(createIntentInput{})
+	if err != nil {
+		t.Fatalf("renderCaptureTemplate: %v", err)
+	}
+	if _, err := parseCaptureTemplate(template); !errors.Is(err, errEmptyCaptureTemplate) {
+		t.Fatalf("expected empty template error, got %v", err)
+	}
+}
+
+func TestParseCaptureTemplateUnknownSection(t *testing.T) {
+	_, err := parseCaptureTemplate("==== notes ====\nhello\n")
+	if err == nil || !strings.Contains(err.Error(), "unknown template section") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRunCaptureEditStoresCapture(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	pathLog := writeTestEditor(t, home, "==== prompt ====\nHello\n==== response ====\nWorld\n")
+
+	output, err := captureStdout(func() error {
+		return RunCapture([]string{"--author", "Ada", "--edit"})
+	})
+	if err != nil {
+		t.Fatalf("RunCapture: %v", err)
+	}
+
+	record := loadCapturedIntent(t, output)
+	if record.Prompt != "Hello" || record.Response != "World" {
+		t.Fatalf("unexpected record: %+v", record)
+	}
+	if _, err := os.Stat(readEditedPath(t, pathLog)); !os.IsNotExist(err) {
+		t.Fatalf("expected template removed after capture, stat err=%v", err)
+	}
+}
+
+func TestRunCaptureEditKeepsTemplateOnValidationFailure(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	pathLog := writeTestEditor(t, home, "==== prompt ====\nA long handwritten prompt\n")
+
+	err := RunCapture([]string{"--author", "Ada", "--edit"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "response section is empty") || !strings.Contains(err.Error(), "template kept at") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	path := readEditedPath(t, pathLog)
+	t.Cleanup(func() { _ = os.Remove(path) })
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected template kept: %v", err)
+	}
+	if !strings.Contains(string(data), "A long handwritten prompt") {
+		t.Fatalf("unexpected template content: %q", data)
+	}
+}
+
+// writeTestEditor installs an $EDITOR script that replaces the template with content
+// and records the template path it was given.
+func writeTestEditor(t *testing.T, dir, content string) string {
+	t.Helper()
+
+	contentPath := filepath.Join(dir, "editor-content.txt")
+	if err := os.WriteFile(contentPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("write editor content: %v", err)
+	}
+	pathLog := filepath.Join(dir, "editor-path.txt")
+	script := filepath.Join(dir, "editor.sh")
+	body := "#!/bin/sh\nprintf '%s' \"$1\" > '" + pathLog + "'\ncat '" + contentPath + "' > \"$1\"\n"
+	if err := os.WriteFile(script, []byte(body), 0o700); err != nil {
+		t.Fatalf("write editor script: %v", err)
+	}
+	t.Setenv("EDITOR", script)
+	return pathLog
+}
+
+func readEditedPath(t *testing.T, pathLog string) string {
+	t.Helper()
+	data, err := os.ReadFile(pathLog)
+	if err != nil {
+		t.Fatalf("read editor path log: %v", err)
+	}
+	return string(data)
+}
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
 )
 
 func TestKVPairsToJSONLastValueWins(t *testing.T) {
@@ -37,3 +41,151 @@ func TestKVPairsToJSONMalformedArgument(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestRunCaptureResponseFromStdin(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	withStdin(t, "streamed response\n\n")
+
+	output, err := captureStdout(func() error {
+		return RunCapture([]string{"--author", "Ada", "--prompt", "hello  \n", "--response-file", "-"})
+	})
+	if err != nil {
+		t.Fatalf("RunCapture: %v", err)
+	}
+
+	record := loadCapturedIntent(t, output)
+	if record.Prompt != "hello" {
+		t.Fatalf("expected trimmed prompt, got %q", record.Prompt)
+	}
+	if record.Response != "streamed response" {
+		t.Fatalf("expected trimmed stdin response, got %q", record.Response)
+	}
+}
+
+func TestRunCaptureJSONFromStdin(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	withStdin(t, `{"author":"Ada","title":"piped","prompt":"p\n","response":"r\t\n","meta":{"lang":"go"}}`)
+
+	output, err := captureStdout(func() error {
+		return RunCapture([]string{"--json", "-", "--meta", "area=cli"})
+	})
+	if err != nil {
+		t.Fatalf("RunCapture: %v", err)
+	}
+
+	record := loadCapturedIntent(t, output)
+	if record.Author != "Ada" || record.Title != "piped" || record.SourceType != "cli" {
+		t.Fatalf("unexpected record: %+v", record)
+	}
+	if record.Prompt != "p" || record.Response != "r" {
+		t.Fatalf("expected trimmed prompt/response, got %q/%q", record.Prompt, record.Response)
+	}
+	var meta map[string]string
+	if err := json.Unmarshal(record.Meta, &meta); err != nil {
+		t.Fatalf("decode meta: %v", err)
+	}
+	if meta["lang"] != "go" || meta["area"] != "cli" {
+		t.Fatalf("expected merged meta, got %v", meta)
+	}
+}
+
+func TestRunCaptureTypedMeta(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	output, err := captureStdout(func() error {
+		return RunCapture([]string{
+			"--author", "Ada", "--prompt", "p", "--response", "r", "--no-git",
+			"--meta", "area=cli",
+			"--meta-json", "tokens=1200",
+			"--meta-json", `review={"score":4.5,"tags":["a","b"]}`,
+		})
+	})
+	if err != nil {
+		t.Fatalf("RunCapture: %v", err)
+	}
+
+	record := loadCapturedIntent(t, output)
+	want := `{"area":"cli","project":"alpha","review":{"score":4.5,"tags":["a","b"]},"tokens":1200}`
+	if string(record.Meta) != want {
+		t.Fatalf("expected typed meta %s, got %s", want, record.Meta)
+	}
+
+	err = RunCapture([]string{"--author", "Ada", "--prompt", "p", "--response", "r", "--meta-json", "tokens=twelve"})
+	if err == nil || !strings.Contains(err.Error(), "expected key=<json>") {
+		t.Fatalf("expected invalid --meta-json error, got %v", err)
+	}
+}
+
+func TestRunCaptureRejectsDoubleStdin(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+
+	err := RunCapture([]string{"--author", "Ada", "--prompt-file", "-", "--response-file", "-"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "--json -") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDecodeCaptureJSONRejectsUnknownFields(t *testing.T) {
+	_, err := decodeCaptureJSON([]byte(`{"prompt":"p","response":"r","extra":1}`))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "unknown field") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestTrimCaptureTextIsDeterministic(t *testing.T) {
+	inputs := []string{"answer", "answer\n", "answer \r\n\t", "answer\n\n\n"}
+	for _, input := range inputs {
+		if got := trimCaptureText(input); got != "answer" {
+			t.Fatalf("trimCaptureText(%q) = %q", input, got)
+		}
+	}
+	if got := trimCaptureText("  leading kept"); got != "  leading kept" {
+		t.Fatalf("expected leading whitespace preserved, got %q", got)
+	}
+}
+
+func loadCapturedIntent(t *testing.T, output string) model.IntentRecord {
+	t.Helper()
+
+	var id string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "id: ") {
+			id = strings.TrimPrefix(line, "id: ")
+		}
+	}
+	if id == "" {
+		t.Fatalf("missing id in output %q", output)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	record, err := getLocalIntent(context.Background(), db, id)
+	if err != nil {
+		t.Fatalf("get intent: %v", err)
+	}
+	return record
+}
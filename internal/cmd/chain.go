@@ -2,24 +2,36 @@ package cmd
 
 import (
 	"context"
+	"database/sql"
 	"errors"
-	"flag"
 	"fmt"
-	"os"
+	"strings"
 
 	"github.com/chuxorg/chux-yanzi-cli/internal/client"
 	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	yanzilibrary "github.com/chuxorg/chux-yanzi-cli/internal/library"
 )
 
-// RunChain prints the intent chain from oldest to newest.
+// RunChain prints the intent chain from oldest to newest. Without an id it
+// starts from the head of the active project's chain (local mode only). With
+// --graph it prints the project's whole chain graph, including forks and merges.
 func RunChain(args []string) error {
-	fs := flag.NewFlagSet("chain", flag.ContinueOnError)
-	fs.SetOutput(os.Stderr)
+	fs := newFlagSet("chain")
+	noAbbrev := fs.Bool("no-abbrev", false, "print full intent ids and hashes in text output")
+	format := fs.String("format", "", formatUsage)
+	graph := fs.Bool("graph", false, "print the project's chain graph with forks, heads, and merges")
+	project := fs.String("project", "", "project for --graph (default: active project)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
-	if fs.NArg() != 1 {
-		return errors.New("usage: yanzi chain <intent-id>")
+	if *graph {
+		if fs.NArg() != 0 || *format != "" {
+			return errors.New("usage: yanzi chain --graph [--project <name>] [--no-abbrev]")
+		}
+		return runChainGraph(*project, *noAbbrev)
+	}
+	if fs.NArg() > 1 || *project != "" {
+		return errors.New("usage: yanzi chain [intent-id] | --graph [--project <name>]")
 	}
 
 	id := fs.Arg(0)
@@ -27,20 +39,23 @@ func RunChain(args []string) error {
 	if err != nil {
 		return err
 	}
+	tmpl, err := loadFormatTemplate(cfg, *format)
+	if err != nil {
+		return err
+	}
 	var resp chainResult
+	var abbrev idAbbreviator
 	switch cfg.Mode {
 	case config.ModeHTTP:
+		if id == "" {
+			return errors.New("usage: yanzi chain <intent-id> (http mode has no project chain head)")
+		}
 		cli := client.New(cfg.BaseURL)
 		httpResp, err := cli.ChainIntent(context.Background(), id)
 		if err != nil {
 			return fmt.Errorf("http request to %s failed: %w", cfg.BaseURL, err)
 		}
-		resp = chainResult{
-			HeadID:       httpResp.HeadID,
-			Length:       httpResp.Length,
-			Intents:      httpResp.Intents,
-			MissingLinks: httpResp.MissingLinks,
-		}
+		resp = httpResp
 	case config.ModeLocal:
 		ctx := context.Background()
 		db, err := openLocalDB(cfg)
@@ -49,24 +64,141 @@ func RunChain(args []string) error {
 		}
 		defer db.Close()
 
+		if id == "" {
+			id, err = activeChainHeadID(ctx, db)
+			if err != nil {
+				return err
+			}
+		}
 		localResp, err := chainLocalIntent(ctx, db, id)
 		if err != nil {
 			return err
 		}
 		resp = localResp
+		if !*noAbbrev {
+			abbrev = idAbbreviator{ctx: ctx, db: db}
+		}
 	default:
 		return fmt.Errorf("invalid mode: %s", cfg.Mode)
 	}
 
-	fmt.Printf("chain head: %s\n", resp.HeadID)
-	for i, intent := range resp.Intents {
-		fmt.Printf("%d\t%s\t%s\t%s\t%s\n", i+1, intent.CreatedAt, intent.Title, intent.Author, intent.Hash)
+	if tmpl != nil {
+		for _, intent := range resp.Intents {
+			if err := renderTemplate(tmpl, intent); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return printResult(resp, func() {
+		fmt.Printf("chain head: %s\n", abbrev.short(resp.HeadID))
+		for i, intent := range resp.Intents {
+			fmt.Printf("%d\t%s\t%s\t%s\t%s\n", i+1, intent.CreatedAt, intent.Title, intent.Author, abbrev.short(intent.Hash))
+		}
+		if len(resp.MissingLinks) > 0 {
+			fmt.Printf("missing_links: %s\n", joinComma(resp.MissingLinks))
+		}
+	})
+}
+
+// runChainGraph prints the chain graph of a project, the active one by default.
+func runChainGraph(project string, noAbbrev bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Mode != config.ModeLocal {
+		return errors.New("chain --graph is only available in local mode")
+	}
+	if project == "" {
+		project, err = loadActiveProject()
+		if err != nil {
+			return err
+		}
+		if project == "" {
+			return errors.New("no active project set; pass --project")
+		}
+	}
+	ctx := context.Background()
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	graph, err := yanzilibrary.BuildChainGraph(ctx, db, project)
+	if err != nil {
+		return err
+	}
+	var abbrev idAbbreviator
+	if !noAbbrev {
+		abbrev = idAbbreviator{ctx: ctx, db: db}
 	}
-	if len(resp.MissingLinks) > 0 {
-		fmt.Printf("missing_links: %s\n", joinComma(resp.MissingLinks))
+	short := func(hashes []string) string {
+		out := make([]string, len(hashes))
+		for i, hash := range hashes {
+			out[i] = abbrev.short(hash)
+		}
+		return joinComma(out)
 	}
+	return printResult(graph, func() {
+		fmt.Printf("project: %s\n", graph.Project)
+		fmt.Printf("recorded_head: %s\n", abbrev.short(graph.RecordedHead))
+		fmt.Printf("heads: %s\n", short(graph.Heads))
+		if graph.CommonAncestor != "" {
+			fmt.Printf("common_ancestor: %s\n", abbrev.short(graph.CommonAncestor))
+		}
+		for _, fork := range graph.Forks {
+			fmt.Printf("fork: %s -> %s\n", abbrev.short(fork.Hash), short(fork.Children))
+		}
+		if len(graph.MissingParents) > 0 {
+			fmt.Printf("missing_parents: %s\n", short(graph.MissingParents))
+		}
+		heads := make(map[string]bool, len(graph.Heads))
+		for _, head := range graph.Heads {
+			heads[head] = true
+		}
+		for i, node := range graph.Nodes {
+			var marks []string
+			if heads[node.Hash] {
+				marks = append(marks, "head")
+			}
+			if len(node.Children) > 1 {
+				marks = append(marks, "fork")
+			}
+			if len(node.Parents) > 1 {
+				marks = append(marks, "merge")
+			}
+			fmt.Printf("%d\t%s\t%s\t%s\t%s\t%s\t%s\n", i+1, node.CreatedAt, node.Title, node.Author,
+				abbrev.short(node.Hash), short(node.Parents), strings.Join(marks, ","))
+		}
+	})
+}
 
-	return nil
+// activeChainHeadID resolves the intent id at the head of the active project's chain.
+func activeChainHeadID(ctx context.Context, db *sql.DB) (string, error) {
+	project, err := loadActiveProject()
+	if err != nil {
+		return "", err
+	}
+	if project == "" {
+		return "", errors.New("no active project set; pass an intent id")
+	}
+	head, err := loadChainHead(ctx, db, project)
+	if err != nil {
+		return "", err
+	}
+	if head == "" {
+		return "", fmt.Errorf("no captures chained for project %s", project)
+	}
+	record, err := dbGetIntentByHash(ctx, db, head)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("chain head not found: %s", head)
+		}
+		return "", err
+	}
+	return record.ID, nil
 }
 
 func joinComma(values []string) string {
@@ -1,6 +1,13 @@
 package cmd
 
-import "testing"
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
+	yanzilibrary "github.com/chuxorg/chux-yanzi-cli/internal/library"
+)
 
 func TestJoinComma(t *testing.T) {
 	if got := joinComma(nil); got != "" {
@@ -13,3 +20,177 @@ func TestJoinComma(t *testing.T) {
 		t.Fatalf("expected joined values, got %q", got)
 	}
 }
+
+func TestCaptureChainsToProjectHead(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	first := runTestCapture(t, "first")
+	if first.PrevHash != "" {
+		t.Fatalf("expected first capture to start the chain, got prev_hash %q", first.PrevHash)
+	}
+	second := runTestCapture(t, "second")
+	if second.PrevHash != first.Hash {
+		t.Fatalf("expected second capture to link to %s, got %q", first.Hash, second.PrevHash)
+	}
+
+	unchained := runTestCapture(t, "aside", "--no-chain")
+	if unchained.PrevHash != "" {
+		t.Fatalf("expected --no-chain capture to have no prev_hash, got %q", unchained.PrevHash)
+	}
+	third := runTestCapture(t, "third")
+	if third.PrevHash != second.Hash {
+		t.Fatalf("expected --no-chain to leave the head alone, got prev_hash %q", third.PrevHash)
+	}
+
+	override := runTestCapture(t, "override", "--prev-hash", first.Hash)
+	if override.PrevHash != first.Hash {
+		t.Fatalf("expected explicit prev_hash to win, got %q", override.PrevHash)
+	}
+	fourth := runTestCapture(t, "fourth")
+	if fourth.PrevHash != override.Hash {
+		t.Fatalf("expected override capture to become the head, got prev_hash %q", fourth.PrevHash)
+	}
+
+	output, err := captureStdout(func() error {
+		return RunChain(nil)
+	})
+	if err != nil {
+		t.Fatalf("RunChain: %v", err)
+	}
+	if !strings.HasPrefix(output, "chain head: "+fourth.ID[:defaultAbbrev]+"\n") {
+		t.Fatalf("expected chain to start at the project head, got %q", output)
+	}
+	if lines := strings.Count(output, "\n"); lines != 4 {
+		t.Fatalf("expected head line plus 3 links, got %q", output)
+	}
+}
+
+func TestCaptureNoChainRejectsPrevHash(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+
+	err := RunCapture([]string{"--author", "Ada", "--prompt", "p", "--response", "r", "--no-chain", "--prev-hash", "abc"})
+	if err == nil || !strings.Contains(err.Error(), "--no-chain") {
+		t.Fatalf("expected --no-chain conflict, got %v", err)
+	}
+}
+
+func TestChainHeadSeededFromExistingCaptures(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	withCwd(t, home)
+	writeTestConfig(t, home)
+	writeStateFile(t, home, "alpha")
+
+	db := openConfiguredDBForExportTest(t)
+	seedProject(t, db, "alpha")
+	seedIntentWithSource(t, db, "old-1", "2025-01-01T00:00:01Z", "alpha", "engineer", "cli", "p1", "r1")
+	seedIntentWithSource(t, db, "old-2", "2025-01-01T00:00:02Z", "alpha", "engineer", "cli", "p2", "r2")
+	seedIntentWithSource(t, db, "beta-1", "2025-01-01T00:00:03Z", "beta", "engineer", "cli", "p3", "r3")
+	var latest string
+	if err := db.QueryRow(`SELECT hash FROM intents WHERE id = 'old-2'`).Scan(&latest); err != nil {
+		t.Fatalf("read seeded hash: %v", err)
+	}
+	db.Close()
+
+	record := runTestCapture(t, "new")
+	if record.PrevHash != latest {
+		t.Fatalf("expected capture to chain to the latest existing alpha capture %s, got %q", latest, record.PrevHash)
+	}
+}
+
+func TestChainGraphAndMergeCapture(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	base := runTestCapture(t, "base")
+	branch := runTestCapture(t, "branch")
+	// A concurrent capture that also linked to base leaves two heads.
+	fork := runTestCapture(t, "fork", "--prev-hash", base.Hash)
+
+	graph := loadChainGraph(t)
+	if len(graph.Heads) != 2 || graph.Heads[0] != fork.Hash || graph.Heads[1] != branch.Hash {
+		t.Fatalf("expected two heads, got %+v", graph.Heads)
+	}
+	if len(graph.Forks) != 1 || graph.Forks[0].Hash != base.Hash || graph.CommonAncestor != base.Hash {
+		t.Fatalf("expected a fork at the base capture, got %+v", graph)
+	}
+	// An unmerged fork is reported, but the ledger is still clean.
+	useOutputFormat(t, OutputJSON)
+	output, err := captureStdout(func() error { return RunVerify([]string{"--all"}) })
+	if err != nil {
+		t.Fatalf("expected a fork to leave the ledger clean, got %v", err)
+	}
+	var audit ledgerAudit
+	if err := json.Unmarshal([]byte(output), &audit); err != nil {
+		t.Fatalf("decode audit %q: %v", output, err)
+	}
+	if audit.Status != ledgerClean || audit.Forks != 1 || audit.Orphans != 0 || len(audit.Issues) != 1 || audit.Issues[0].ID != branch.ID {
+		t.Fatalf("expected the branch to be reported as an unmerged fork, got %+v", audit)
+	}
+	SetOutputFormat(OutputText)
+
+	merge := runTestCapture(t, "merge", "--merge", branch.ID[:8])
+	if merge.PrevHash != fork.Hash {
+		t.Fatalf("expected the merge to follow the recorded head, got prev_hash %q", merge.PrevHash)
+	}
+	graph = loadChainGraph(t)
+	if len(graph.Heads) != 1 || graph.Heads[0] != merge.Hash || len(graph.Merges) != 1 || graph.CommonAncestor != "" {
+		t.Fatalf("expected the merge to reconcile the heads, got %+v", graph)
+	}
+	if parents := graph.Nodes[len(graph.Nodes)-1].Parents; len(parents) != 2 || parents[1] != branch.Hash {
+		t.Fatalf("expected the branch as second parent, got %v", parents)
+	}
+	if _, err := captureStdout(func() error { return RunVerify([]string{"--all"}) }); err != nil {
+		t.Fatalf("expected a clean ledger after the merge, got %v", err)
+	}
+
+	output, err = captureStdout(func() error { return RunChain([]string{"--graph"}) })
+	if err != nil {
+		t.Fatalf("RunChain --graph: %v", err)
+	}
+	if !strings.Contains(output, "fork: "+base.Hash[:defaultAbbrev]) || !strings.Contains(output, "\thead,merge\n") {
+		t.Fatalf("expected fork and merge markers, got %q", output)
+	}
+
+	err = RunCapture([]string{"--author", "Ada", "--prompt", "p", "--response", "r", "--merge", "ffffffffff"})
+	if err == nil || !strings.Contains(err.Error(), "--merge: intent not found") {
+		t.Fatalf("expected unknown merge parent error, got %v", err)
+	}
+}
+
+func loadChainGraph(t *testing.T) yanzilibrary.ChainGraph {
+	t.Helper()
+	useOutputFormat(t, OutputJSON)
+	output, err := captureStdout(func() error { return RunChain([]string{"--graph", "--project", "alpha"}) })
+	SetOutputFormat(OutputText)
+	if err != nil {
+		t.Fatalf("RunChain --graph: %v", err)
+	}
+	var graph yanzilibrary.ChainGraph
+	if err := json.Unmarshal([]byte(output), &graph); err != nil {
+		t.Fatalf("decode graph %q: %v", output, err)
+	}
+	return graph
+}
+
+func runTestCapture(t *testing.T, prompt string, extra ...string) model.IntentRecord {
+	t.Helper()
+
+	args := append([]string{"--author", "Ada", "--prompt", prompt, "--response", "ok"}, extra...)
+	output, err := captureStdout(func() error {
+		return RunCapture(args)
+	})
+	if err != nil {
+		t.Fatalf("RunCapture %s: %v", prompt, err)
+	}
+	return loadCapturedIntent(t, output)
+}
@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
-	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/chuxorg/chux-yanzi-cli/internal/config"
 	yanzilibrary "github.com/chuxorg/chux-yanzi-cli/internal/library"
@@ -22,20 +25,24 @@ func RunCheckpoint(args []string) error {
 		return runCheckpointCreate(args[1:])
 	case "list":
 		return runCheckpointList(args[1:])
+	case "verify":
+		return runCheckpointVerify(args[1:])
+	case "log":
+		return runCheckpointLog(args[1:])
 	default:
 		return checkpointUsageError()
 	}
 }
 
 func runCheckpointCreate(args []string) error {
-	fs := flag.NewFlagSet("checkpoint create", flag.ContinueOnError)
-	fs.SetOutput(os.Stderr)
+	fs := newFlagSet("checkpoint create")
 	summary := fs.String("summary", "", "checkpoint summary")
+	noGit := fs.Bool("no-git", false, "do not record git work tree context in meta")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 	if len(fs.Args()) != 0 {
-		return errors.New("usage: yanzi checkpoint create --summary \"...\"")
+		return errors.New("usage: yanzi checkpoint create --summary \"...\" [--no-git]")
 	}
 	if *summary == "" {
 		return errors.New("summary is required")
@@ -49,6 +56,14 @@ func runCheckpointCreate(args []string) error {
 		return errors.New("no active project set")
 	}
 
+	var meta json.RawMessage
+	if !*noGit {
+		meta, err = gitContextMeta()
+		if err != nil {
+			return err
+		}
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		return err
@@ -56,6 +71,23 @@ func runCheckpointCreate(args []string) error {
 
 	switch cfg.Mode {
 	case config.ModeLocal:
+		redactor, err := newRedactor(cfg)
+		if err != nil {
+			return err
+		}
+		pending, err := redactCheckpoint(redactor, pendingCheckpoint{Project: project, Summary: *summary, Meta: meta})
+		if err != nil {
+			return err
+		}
+		pending, err = runCheckpointPreHooks(cfg, redactor, pending)
+		if err != nil {
+			return err
+		}
+
+		signer, err := loadSigner(cfg)
+		if err != nil {
+			return err
+		}
 		ctx := context.Background()
 		db, err := openLocalDB(cfg)
 		if err != nil {
@@ -63,15 +95,23 @@ func runCheckpointCreate(args []string) error {
 		}
 		defer db.Close()
 
-		checkpoint, err := yanzilibrary.CreateCheckpoint(ctx, db, project, *summary, []string{})
+		checkpoint, err := checkpointStore(db, signer).CreateCheckpointWithMeta(ctx, project, pending.Summary, []string{}, pending.Meta)
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("id: %s\n", checkpoint.Hash)
-		fmt.Printf("summary: %s\n", checkpoint.Summary)
+		if err := printResult(checkpoint, func() {
+			fmt.Printf("id: %s\n", checkpoint.Hash)
+			fmt.Printf("summary: %s\n", checkpoint.Summary)
+		}); err != nil {
+			return err
+		}
+		runPostHooks(hookPostCheckpoint, cfg.Hooks.PostCheckpoint, checkpoint)
 		return nil
 	case config.ModeHTTP:
+		if cfg.Signing.Key != "" {
+			return errSigningLocalOnly
+		}
 		return errors.New("checkpoint commands are not available in http mode")
 	default:
 		return fmt.Errorf("invalid mode: %s", cfg.Mode)
@@ -79,13 +119,15 @@ func runCheckpointCreate(args []string) error {
 }
 
 func runCheckpointList(args []string) error {
-	fs := flag.NewFlagSet("checkpoint list", flag.ContinueOnError)
-	fs.SetOutput(os.Stderr)
+	fs := newFlagSet("checkpoint list")
+	since := fs.String("since", "", "only checkpoints at or after this "+timeRangeUsage)
+	until := fs.String("until", "", "only checkpoints at or before this "+timeRangeUsage)
+	format := fs.String("format", "", formatUsage)
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 	if len(fs.Args()) != 0 {
-		return errors.New("usage: yanzi checkpoint list")
+		return errors.New("usage: yanzi checkpoint list [--since <time>] [--until <time>] [--format <template>]")
 	}
 
 	project, err := loadActiveProject()
@@ -103,6 +145,10 @@ func runCheckpointList(args []string) error {
 
 	switch cfg.Mode {
 	case config.ModeLocal:
+		tmpl, err := loadFormatTemplate(cfg, *format)
+		if err != nil {
+			return err
+		}
 		ctx := context.Background()
 		db, err := openLocalDB(cfg)
 		if err != nil {
@@ -110,16 +156,35 @@ func runCheckpointList(args []string) error {
 		}
 		defer db.Close()
 
+		window, err := parseTimeRange(*since, *until, time.Now(), localCheckpointTime(ctx, db))
+		if err != nil {
+			return err
+		}
 		checkpoints, err := yanzilibrary.ListCheckpoints(ctx, db, project)
 		if err != nil {
 			return err
 		}
 
-		fmt.Println("Index\tCreatedAt\tSummary")
-		for i, checkpoint := range checkpoints {
-			fmt.Printf("%d\t%s\t%s\n", i+1, checkpoint.CreatedAt, checkpoint.Summary)
+		var matched []yanzilibrary.Checkpoint
+		for _, checkpoint := range checkpoints {
+			if window.containsTimestamp(checkpoint.CreatedAt) {
+				matched = append(matched, checkpoint)
+			}
 		}
-		return nil
+		if tmpl != nil {
+			for _, checkpoint := range matched {
+				if err := renderTemplate(tmpl, checkpoint); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return printList("checkpoints", matched, func() {
+			fmt.Println("Index\tCreatedAt\tSummary")
+			for i, checkpoint := range matched {
+				fmt.Printf("%d\t%s\t%s\n", i+1, checkpoint.CreatedAt, checkpoint.Summary)
+			}
+		})
 	case config.ModeHTTP:
 		return errors.New("checkpoint commands are not available in http mode")
 	default:
@@ -127,6 +192,211 @@ func runCheckpointList(args []string) error {
 	}
 }
 
+// runCheckpointVerify recomputes the hash of one checkpoint, or with --all of every
+// checkpoint, and resolves its previous checkpoint and artifacts.
+func runCheckpointVerify(args []string) error {
+	fs := newFlagSet("checkpoint verify")
+	all := fs.Bool("all", false, "verify every checkpoint")
+	project := fs.String("project", "", "limit --all to one project")
+	requireSigned := fs.Bool("require-signed", false, "fail checkpoints that carry no signature")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *all == (fs.NArg() == 1) || fs.NArg() > 1 || (*project != "" && !*all) {
+		return errors.New("usage: yanzi checkpoint verify <checkpoint-id> | --all [--project <name>] [--require-signed]")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Mode != config.ModeLocal {
+		return errors.New("checkpoint commands are not available in http mode")
+	}
+	sigs, err := loadSignatureCheck(cfg, *requireSigned)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		return &ExitError{Code: ExitLedgerUnreadable, Err: err}
+	}
+	defer db.Close()
+
+	if !*all {
+		checkpoint, err := resolveCheckpoint(ctx, db, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		result, err := yanzilibrary.VerifyCheckpoint(ctx, db, checkpoint)
+		if err != nil {
+			return &ExitError{Code: ExitLedgerUnreadable, Err: err}
+		}
+		sigs.checkCheckpointSignature(checkpoint, &result)
+		if err := printResult(result, func() { printCheckpointVerification(result) }); err != nil {
+			return err
+		}
+		return checkpointVerificationError([]yanzilibrary.CheckpointVerification{result})
+	}
+
+	checkpoints, unreadable, err := loadCheckpoints(ctx, db, strings.TrimSpace(*project))
+	if err != nil {
+		return &ExitError{Code: ExitLedgerUnreadable, Err: fmt.Errorf("read checkpoints: %w", err)}
+	}
+	results, err := verifyCheckpoints(ctx, db, checkpoints, sigs)
+	if err != nil {
+		return err
+	}
+	if err := printList("checkpoints", results, func() {
+		for _, result := range results {
+			printCheckpointVerification(result)
+		}
+	}); err != nil {
+		return err
+	}
+	if len(unreadable) > 0 {
+		for _, issue := range unreadable {
+			fmt.Fprintf(os.Stderr, "unreadable checkpoint: %s\n", issue.Detail)
+		}
+		return &ExitError{Code: ExitLedgerUnreadable, Err: fmt.Errorf("%d checkpoints could not be read", len(unreadable))}
+	}
+	return checkpointVerificationError(results)
+}
+
+// runCheckpointLog prints a checkpoint lineage from the newest checkpoint, or the one
+// given, back to the genesis checkpoint, verifying each entry.
+func runCheckpointLog(args []string) error {
+	fs := newFlagSet("checkpoint log")
+	project := fs.String("project", "", "project whose newest checkpoint starts the log (default: active project)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 || (fs.NArg() == 1 && *project != "") {
+		return errors.New("usage: yanzi checkpoint log [checkpoint-id | --project <name>]")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Mode != config.ModeLocal {
+		return errors.New("checkpoint commands are not available in http mode")
+	}
+	sigs, err := loadSignatureCheck(cfg, false)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		return &ExitError{Code: ExitLedgerUnreadable, Err: err}
+	}
+	defer db.Close()
+
+	var start string
+	if fs.NArg() == 1 {
+		checkpoint, err := resolveCheckpoint(ctx, db, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		start = checkpoint.Hash
+	} else {
+		name := strings.TrimSpace(*project)
+		if name == "" {
+			name, err = loadActiveProject()
+			if err != nil {
+				return err
+			}
+			if name == "" {
+				return errors.New("no active project set")
+			}
+		}
+		start, err = yanzilibrary.LatestCheckpointHash(ctx, db, name)
+		if err != nil {
+			return err
+		}
+		if start == "" {
+			return fmt.Errorf("no checkpoints for project %s", name)
+		}
+	}
+
+	lineage, err := yanzilibrary.CheckpointLineage(ctx, db, start)
+	if err != nil {
+		return &ExitError{Code: ExitLedgerUnreadable, Err: fmt.Errorf("read checkpoint lineage: %w", err)}
+	}
+	results, err := verifyCheckpoints(ctx, db, lineage, sigs)
+	if err != nil {
+		return err
+	}
+	if err := printList("checkpoints", results, func() {
+		for _, result := range results {
+			printCheckpointVerification(result)
+		}
+	}); err != nil {
+		return err
+	}
+	return checkpointVerificationError(results)
+}
+
+// resolveCheckpoint loads the checkpoint whose hash is or uniquely starts with ref.
+func resolveCheckpoint(ctx context.Context, db *sql.DB, ref string) (yanzilibrary.Checkpoint, error) {
+	hash, err := dbResolveCheckpointHash(ctx, db, ref)
+	if err == nil {
+		var checkpoint yanzilibrary.Checkpoint
+		checkpoint, err = yanzilibrary.GetCheckpoint(ctx, db, hash)
+		if err == nil {
+			return checkpoint, nil
+		}
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return yanzilibrary.Checkpoint{}, fmt.Errorf("checkpoint not found: %s", ref)
+	}
+	return yanzilibrary.Checkpoint{}, err
+}
+
+// verifyCheckpoints verifies each checkpoint, and its signature, in order.
+func verifyCheckpoints(ctx context.Context, db *sql.DB, checkpoints []yanzilibrary.Checkpoint, sigs signatureCheck) ([]yanzilibrary.CheckpointVerification, error) {
+	results := make([]yanzilibrary.CheckpointVerification, 0, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		result, err := yanzilibrary.VerifyCheckpoint(ctx, db, checkpoint)
+		if err != nil {
+			return nil, &ExitError{Code: ExitLedgerUnreadable, Err: fmt.Errorf("verify checkpoint %s: %w", checkpoint.Hash, err)}
+		}
+		sigs.checkCheckpointSignature(checkpoint, &result)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// printCheckpointVerification prints one checkpoint with its verification mark and
+// any problems found.
+func printCheckpointVerification(result yanzilibrary.CheckpointVerification) {
+	mark := "✖"
+	if result.Valid {
+		mark = "✔"
+	}
+	fmt.Printf("%s\t%s\t%s\t%s\n", mark, result.Hash, result.CreatedAt, result.Summary)
+	for _, problem := range result.Problems {
+		fmt.Printf("\t%s: %s\n", problem.Kind, problem.Detail)
+	}
+}
+
+// checkpointVerificationError returns an ExitLedgerTampered error when any checkpoint
+// failed verification.
+func checkpointVerificationError(results []yanzilibrary.CheckpointVerification) error {
+	invalid := 0
+	for _, result := range results {
+		if !result.Valid {
+			invalid++
+		}
+	}
+	if invalid == 0 {
+		return nil
+	}
+	return &ExitError{Code: ExitLedgerTampered, Err: fmt.Errorf("%d of %d checkpoints failed verification", invalid, len(results))}
+}
+
 func checkpointUsageError() error {
-	return errors.New("usage: yanzi checkpoint <create|list>")
+	return errors.New("usage: yanzi checkpoint <create|list|verify|log>")
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 
@@ -105,3 +106,73 @@ func createTestCheckpoint(t *testing.T, project, summary string) {
 		t.Fatalf("create checkpoint: %v", err)
 	}
 }
+
+func TestCheckpointVerifyAndLog(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	createTestProject(t, "beta")
+	writeStateFile(t, home, "alpha")
+	for _, summary := range []string{"genesis", "middle", "newest"} {
+		createTestCheckpoint(t, "alpha", summary)
+	}
+	createTestCheckpoint(t, "beta", "elsewhere")
+
+	output, err := captureStdout(func() error { return RunCheckpoint([]string{"verify", "--all"}) })
+	if err != nil {
+		t.Fatalf("checkpoint verify --all: %v", err)
+	}
+	if strings.Count(output, "✔\t") != 4 {
+		t.Fatalf("expected four verified checkpoints, got %q", output)
+	}
+
+	output, err = captureStdout(func() error { return RunCheckpoint([]string{"log"}) })
+	if err != nil {
+		t.Fatalf("checkpoint log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], "\tnewest") || !strings.HasSuffix(lines[2], "\tgenesis") {
+		t.Fatalf("expected the lineage newest to genesis, got %q", output)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+	var middle, elsewhere string
+	if err := db.QueryRow(`SELECT hash FROM checkpoints WHERE summary = 'middle'`).Scan(&middle); err != nil {
+		t.Fatalf("load middle: %v", err)
+	}
+	if err := db.QueryRow(`SELECT hash FROM checkpoints WHERE summary = 'elsewhere'`).Scan(&elsewhere); err != nil {
+		t.Fatalf("load elsewhere: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE checkpoints SET summary = 'edited' WHERE hash = ?`, middle); err != nil {
+		t.Fatalf("tamper: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE checkpoints SET previous_checkpoint_id = ? WHERE project = 'alpha' AND summary = 'genesis'`, elsewhere); err != nil {
+		t.Fatalf("relink: %v", err)
+	}
+
+	var exitErr *ExitError
+	output, err = captureStdout(func() error { return RunCheckpoint([]string{"verify", middle[:8]}) })
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitLedgerTampered || !strings.Contains(output, "mismatch: stored hash "+middle) {
+		t.Fatalf("expected a hash mismatch, got %q, %v", output, err)
+	}
+
+	output, err = captureStdout(func() error { return RunCheckpoint([]string{"log"}) })
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitLedgerTampered {
+		t.Fatalf("expected the log to fail verification, got %v", err)
+	}
+	if !strings.Contains(output, "✖\t"+middle+"\t") || !strings.Contains(output, "belongs to project beta") {
+		t.Fatalf("expected marked entries for the edited and relinked checkpoints, got %q", output)
+	}
+	if strings.Contains(output, "elsewhere") {
+		t.Fatalf("expected the log to stay within the lineage's recorded links, got %q", output)
+	}
+}
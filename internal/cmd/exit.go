@@ -0,0 +1,33 @@
+package cmd
+
+// Process exit statuses for outcomes that callers and scripts need to tell apart
+// from a generic failure (status 1).
+const (
+	// ExitCaptureSkipped reports a capture dropped because the project is paused.
+	ExitCaptureSkipped = 3
+	// ExitCaptureQueued reports a capture queued until the project is resumed.
+	ExitCaptureQueued = 4
+	// ExitLedgerTampered reports a verify that found a hash mismatch, missing link, or orphan.
+	ExitLedgerTampered = 5
+	// ExitLedgerUnreadable reports a verify that could not read every ledger record.
+	ExitLedgerUnreadable = 6
+)
+
+// ExitError carries a specific process exit status alongside the error message.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error returns the wrapped error message.
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return ""
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
-	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,6 +16,7 @@ import (
 	"time"
 
 	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
 )
 
 type exportItemType string
@@ -37,7 +40,9 @@ type exportItem struct {
 	Hash      string
 	Prompt    string
 	Response  string
-	Metadata  map[string]string
+	Metadata  map[string]json.RawMessage
+	// Attachments lists rendered attachment references.
+	Attachments []model.Attachment
 
 	Command string
 	Value   string
@@ -45,9 +50,10 @@ type exportItem struct {
 
 // RunExport writes deterministic project history logs.
 func RunExport(args []string, cliVersion string) error {
-	fs := flag.NewFlagSet("export", flag.ContinueOnError)
-	fs.SetOutput(os.Stderr)
+	fs := newFlagSet("export")
 	format := fs.String("format", "", "export format (required: markdown)")
+	since := fs.String("since", "", "only history at or after this "+timeRangeUsage)
+	until := fs.String("until", "", "only history at or before this "+timeRangeUsage)
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -73,7 +79,6 @@ func RunExport(args []string, cliVersion string) error {
 	if cfg.Mode != config.ModeLocal {
 		return errors.New("export is only available in local mode")
 	}
-
 	db, err := openLocalDB(cfg)
 	if err != nil {
 		return err
@@ -81,26 +86,74 @@ func RunExport(args []string, cliVersion string) error {
 	defer db.Close()
 
 	ctx := context.Background()
-	items, captureCount, err := loadExportItems(ctx, db, project)
+	window, err := parseTimeRange(*since, *until, time.Now(), localCheckpointTime(ctx, db))
 	if err != nil {
 		return err
 	}
-
-	content := renderMarkdownLog(project, cliVersion, time.Now().UTC(), items, captureCount)
-	path := filepath.Join(".", "YANZI_LOG.md")
-	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
-		return fmt.Errorf("write export file: %w", err)
+	pending, err := runExportPreHooks(cfg, pendingExport{Project: project, Format: "markdown", Path: defaultExportPath})
+	if err != nil {
+		return err
+	}
+	record, err := writeMarkdownExport(ctx, db, project, cliVersion, pending.Path, window)
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("Exported %s\n", path)
+	if err := printResult(record, func() {
+		fmt.Printf("Exported %s\n", record.Path)
+	}); err != nil {
+		return err
+	}
+	runPostHooks(hookPostExport, cfg.Hooks.PostExport, record)
 	return nil
 }
 
-func loadExportItems(ctx context.Context, db *sql.DB, project string) ([]exportItem, int, error) {
+// defaultExportPath is where markdown exports are written unless a pre_export hook moves them.
+var defaultExportPath = filepath.Join(".", "YANZI_LOG.md")
+
+// exportMarkdown writes the project history to ./YANZI_LOG.md and returns the path written.
+func exportMarkdown(project, cliVersion string) (string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", err
+	}
+	if cfg.Mode != config.ModeLocal {
+		return "", errors.New("export is only available in local mode")
+	}
+
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+
+	record, err := writeMarkdownExport(context.Background(), db, project, cliVersion, defaultExportPath, timeRange{})
+	if err != nil {
+		return "", err
+	}
+	return record.Path, nil
+}
+
+// writeMarkdownExport writes the project history within window to path and describes the file written.
+func writeMarkdownExport(ctx context.Context, db *sql.DB, project, cliVersion, path string, window timeRange) (exportRecord, error) {
+	items, captureCount, err := loadExportItems(ctx, db, project, window)
+	if err != nil {
+		return exportRecord{}, err
+	}
+
+	content := renderMarkdownLog(project, cliVersion, time.Now().UTC(), window, items, captureCount)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return exportRecord{}, fmt.Errorf("write export file: %w", err)
+	}
+	sum := sha256.Sum256([]byte(content))
+	return exportRecord{Project: project, Format: "markdown", Path: path, Hash: hex.EncodeToString(sum[:])}, nil
+}
+
+func loadExportItems(ctx context.Context, db *sql.DB, project string, window timeRange) ([]exportItem, int, error) {
 	intents := make([]exportItem, 0)
 	captureCount := 0
 
-	intentRows, err := db.QueryContext(ctx, `SELECT rowid, id, created_at, author, source_type, prompt, response, hash, meta
+	intentRows, err := db.QueryContext(ctx, `SELECT rowid, id, created_at, author, source_type, prompt, response, hash, meta, attachments
 		FROM intents
 		ORDER BY created_at ASC, rowid ASC`)
 	if err != nil {
@@ -112,16 +165,19 @@ func loadExportItems(ctx context.Context, db *sql.DB, project string) ([]exportI
 		var (
 			rowID                                                          int64
 			id, createdAt, author, sourceType, prompt, response, hashValue string
-			metaText                                                       sql.NullString
+			metaText, attachmentsText                                      sql.NullString
 		)
-		if err := intentRows.Scan(&rowID, &id, &createdAt, &author, &sourceType, &prompt, &response, &hashValue, &metaText); err != nil {
+		if err := intentRows.Scan(&rowID, &id, &createdAt, &author, &sourceType, &prompt, &response, &hashValue, &metaText, &attachmentsText); err != nil {
 			return nil, 0, err
 		}
-		meta, err := decodeStringMeta(metaText.String)
+		meta, err := decodeMeta(metaText.String)
 		if err != nil {
 			continue
 		}
-		if strings.TrimSpace(meta["project"]) != project {
+		if strings.TrimSpace(metaString(meta, "project")) != project {
+			continue
+		}
+		if !window.containsTimestamp(createdAt) {
 			continue
 		}
 
@@ -136,17 +192,25 @@ func loadExportItems(ctx context.Context, db *sql.DB, project string) ([]exportI
 			continue
 		}
 
+		var attachments []model.Attachment
+		if attachmentsText.String != "" {
+			if err := json.Unmarshal([]byte(attachmentsText.String), &attachments); err != nil {
+				return nil, 0, fmt.Errorf("decode attachments for %s: %w", id, err)
+			}
+		}
+
 		captureCount++
 		intents = append(intents, exportItem{
-			Kind:      exportItemCapture,
-			Timestamp: createdAt,
-			CaptureID: id,
-			Role:      author,
-			Hash:      hashValue,
-			Prompt:    prompt,
-			Response:  response,
-			Metadata:  meta,
-			RowID:     rowID,
+			Kind:        exportItemCapture,
+			Timestamp:   createdAt,
+			CaptureID:   id,
+			Role:        author,
+			Hash:        hashValue,
+			Prompt:      prompt,
+			Response:    response,
+			Metadata:    meta,
+			Attachments: attachments,
+			RowID:       rowID,
 		})
 	}
 	if err := intentRows.Err(); err != nil {
@@ -169,6 +233,9 @@ func loadExportItems(ctx context.Context, db *sql.DB, project string) ([]exportI
 		if err := checkpointRows.Scan(&rowID, &id, &summary, &createdAt); err != nil {
 			return nil, 0, err
 		}
+		if !window.containsTimestamp(createdAt) {
+			continue
+		}
 		checkpoints = append(checkpoints, exportItem{
 			Kind:         exportItemCheckpoint,
 			Timestamp:    createdAt,
@@ -184,19 +251,52 @@ func loadExportItems(ctx context.Context, db *sql.DB, project string) ([]exportI
 	return mergeChronological(intents, checkpoints), captureCount, nil
 }
 
-func decodeStringMeta(metaText string) (map[string]string, error) {
+// decodeMeta decodes a stored meta object, keeping each value as raw JSON so that
+// numbers, booleans, arrays, and nested objects survive alongside strings.
+func decodeMeta(metaText string) (map[string]json.RawMessage, error) {
 	if strings.TrimSpace(metaText) == "" {
 		return nil, nil
 	}
 
-	var meta map[string]string
+	var meta map[string]json.RawMessage
 	if err := json.Unmarshal([]byte(metaText), &meta); err != nil {
 		return nil, err
 	}
 	return meta, nil
 }
 
-func sortedMetaPairs(meta map[string]string) []string {
+// metaString returns the meta value for key when it is a JSON string, else "".
+func metaString(meta map[string]json.RawMessage, key string) string {
+	var value string
+	if err := json.Unmarshal(meta[key], &value); err != nil {
+		return ""
+	}
+	return value
+}
+
+// formatMetaValue renders a meta value for display: strings as-is, anything else
+// as compact JSON with object keys sorted.
+func formatMetaValue(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return string(raw)
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return string(raw)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func sortedMetaPairs(meta map[string]json.RawMessage) []string {
 	if len(meta) == 0 {
 		return nil
 	}
@@ -208,7 +308,7 @@ func sortedMetaPairs(meta map[string]string) []string {
 
 	lines := make([]string, 0, len(keys))
 	for _, key := range keys {
-		lines = append(lines, fmt.Sprintf("  %s: %s", key, meta[key]))
+		lines = append(lines, fmt.Sprintf("  %s: %s", key, formatMetaValue(meta[key])))
 	}
 	return lines
 }
@@ -253,12 +353,18 @@ func mergeChronological(intents, checkpoints []exportItem) []exportItem {
 	return merged
 }
 
-func renderMarkdownLog(project, cliVersion string, now time.Time, items []exportItem, captureCount int) string {
+func renderMarkdownLog(project, cliVersion string, now time.Time, window timeRange, items []exportItem, captureCount int) string {
 	var b strings.Builder
 
 	b.WriteString("# Yanzi Agent Log\n\n")
 	b.WriteString(fmt.Sprintf("Project: %s\n", project))
 	b.WriteString(fmt.Sprintf("Exported: %s\n", now.Format(time.RFC3339)))
+	if !window.since.IsZero() {
+		b.WriteString(fmt.Sprintf("Since: %s\n", window.since.Format(time.RFC3339Nano)))
+	}
+	if !window.until.IsZero() {
+		b.WriteString(fmt.Sprintf("Until: %s\n", window.until.Format(time.RFC3339Nano)))
+	}
 	b.WriteString(fmt.Sprintf("Version: %s\n\n", cliVersion))
 	b.WriteString("---\n\n")
 
@@ -292,6 +398,13 @@ func renderMarkdownLog(project, cliVersion string, now time.Time, items []export
 				b.WriteString(strings.Join(metaLines, "\n"))
 				b.WriteString("\n\n")
 			}
+			if len(item.Attachments) > 0 {
+				b.WriteString("Attachments:\n")
+				for _, attachment := range item.Attachments {
+					b.WriteString(fmt.Sprintf("  %s\n", formatAttachment(attachment)))
+				}
+				b.WriteString("\n")
+			}
 			b.WriteString("**Prompt**\n")
 			b.WriteString("```text\n")
 			b.WriteString(item.Prompt)
@@ -96,7 +96,7 @@ func TestExportMarkdownRendersSortedMetadata(t *testing.T) {
 	db := openConfiguredDBForExportTest(t)
 	defer db.Close()
 	seedProject(t, db, "alpha")
-	seedIntentWithMeta(t, db, "cap-meta", "2025-01-01T00:00:01Z", "engineer", "cli", "prompt", "response", map[string]string{
+	seedIntentWithMeta(t, db, "cap-meta", "2025-01-01T00:00:01Z", "engineer", "cli", "prompt", "response", map[string]any{
 		"project":       "alpha",
 		"decision_type": "refactor",
 		"area":          "auth",
@@ -118,6 +118,38 @@ func TestExportMarkdownRendersSortedMetadata(t *testing.T) {
 	}
 }
 
+func TestExportMarkdownRendersTypedMetadata(t *testing.T) {
+	workdir := t.TempDir()
+	t.Setenv("HOME", workdir)
+	withCwd(t, workdir)
+	writeTestConfig(t, workdir)
+	writeStateFile(t, workdir, "alpha")
+
+	db := openConfiguredDBForExportTest(t)
+	defer db.Close()
+	seedProject(t, db, "alpha")
+	seedIntentWithMeta(t, db, "cap-typed", "2025-01-01T00:00:01Z", "engineer", "cli", "prompt", "response", map[string]any{
+		"project": "alpha",
+		"tokens":  1200,
+		"dirty":   true,
+		"review":  map[string]any{"score": 4.5, "by": []string{"ada", "bob"}},
+	})
+
+	if err := RunExport([]string{"--format", "markdown"}, "v1.0.0"); err != nil {
+		t.Fatalf("RunExport: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(workdir, "YANZI_LOG.md"))
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	output := string(data)
+	metaBlock := "Metadata:\n  dirty: true\n  project: alpha\n  review: {\"by\":[\"ada\",\"bob\"],\"score\":4.5}\n  tokens: 1200\n"
+	if !strings.Contains(output, "### Capture: cap-typed") || !strings.Contains(output, metaBlock) {
+		t.Fatalf("expected typed metadata block, got: %q", output)
+	}
+}
+
 func TestExportMarkdownNoCapturesRecorded(t *testing.T) {
 	workdir := t.TempDir()
 	t.Setenv("HOME", workdir)
@@ -196,10 +228,10 @@ func openConfiguredDBForExportTest(t *testing.T) *sql.DB {
 
 func seedIntentWithSource(t *testing.T, db *sql.DB, id, createdAt, project, author, sourceType, prompt, response string) {
 	t.Helper()
-	seedIntentWithMeta(t, db, id, createdAt, author, sourceType, prompt, response, map[string]string{"project": project})
+	seedIntentWithMeta(t, db, id, createdAt, author, sourceType, prompt, response, map[string]any{"project": project})
 }
 
-func seedIntentWithMeta(t *testing.T, db *sql.DB, id, createdAt, author, sourceType, prompt, response string, metaPayload map[string]string) {
+func seedIntentWithMeta(t *testing.T, db *sql.DB, id, createdAt, author, sourceType, prompt, response string, metaPayload map[string]any) {
 	t.Helper()
 	meta, err := json.Marshal(metaPayload)
 	if err != nil {
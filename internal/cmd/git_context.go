@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Meta keys recorded from the git work tree the command runs in.
+const (
+	gitMetaRoot     = "git_root"
+	gitMetaBranch   = "git_branch"
+	gitMetaCommit   = "git_commit"
+	gitMetaDirty    = "git_dirty"
+	gitMetaDiffHash = "git_diff_hash"
+)
+
+// gitContextMeta describes the git work tree containing the working directory as meta.
+// It returns nil when git is unavailable or the directory is not inside a work tree.
+// The branch is omitted on a detached HEAD, the commit before the first commit, and
+// the diff hash when there are no staged or unstaged changes to tracked files.
+func gitContextMeta() (json.RawMessage, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, nil
+	}
+	root, err := runGit("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, nil
+	}
+
+	meta := map[string]string{gitMetaRoot: strings.TrimSpace(string(root))}
+	if branch, err := runGit("symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+		meta[gitMetaBranch] = strings.TrimSpace(string(branch))
+	}
+	if commit, err := runGit("rev-parse", "--verify", "--quiet", "HEAD"); err == nil {
+		meta[gitMetaCommit] = strings.TrimSpace(string(commit))
+	}
+
+	status, err := runGit("status", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("git status: %w", err)
+	}
+	meta[gitMetaDirty] = strconv.FormatBool(len(bytes.TrimSpace(status)) > 0)
+
+	staged, err := runGit("diff", "--cached", "--binary", "--no-color", "--no-ext-diff")
+	if err != nil {
+		return nil, fmt.Errorf("git diff: %w", err)
+	}
+	unstaged, err := runGit("diff", "--binary", "--no-color", "--no-ext-diff")
+	if err != nil {
+		return nil, fmt.Errorf("git diff: %w", err)
+	}
+	if len(staged) > 0 || len(unstaged) > 0 {
+		sum := sha256.New()
+		sum.Write(staged)
+		sum.Write(unstaged)
+		meta[gitMetaDiffHash] = hex.EncodeToString(sum.Sum(nil))
+	}
+
+	encoded, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("encode meta: %w", err)
+	}
+	return json.RawMessage(encoded), nil
+}
+
+// runGit runs a git command in the working directory and returns its stdout.
+func runGit(args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	yanzilibrary "github.com/chuxorg/chux-yanzi-cli/internal/library"
+)
+
+func TestGitContextMetaCleanAndDirty(t *testing.T) {
+	repo := initTestRepo(t)
+
+	meta := decodeTestMeta(t, mustGitContextMeta(t))
+	root, _ := filepath.EvalSymlinks(repo)
+	if got, _ := filepath.EvalSymlinks(meta[gitMetaRoot]); got != root {
+		t.Fatalf("expected root %q, got %q", root, meta[gitMetaRoot])
+	}
+	if meta[gitMetaBranch] != "main" {
+		t.Fatalf("expected branch main, got %q", meta[gitMetaBranch])
+	}
+	if len(meta[gitMetaCommit]) != 40 {
+		t.Fatalf("expected commit sha, got %q", meta[gitMetaCommit])
+	}
+	if meta[gitMetaDirty] != "false" {
+		t.Fatalf("expected clean tree, got %q", meta[gitMetaDirty])
+	}
+	if _, ok := meta[gitMetaDiffHash]; ok {
+		t.Fatalf("expected no diff hash on a clean tree, got %v", meta)
+	}
+
+	writeRepoFile(t, repo, "README", "changed\n")
+	dirty := decodeTestMeta(t, mustGitContextMeta(t))
+	if dirty[gitMetaDirty] != "true" || dirty[gitMetaDiffHash] == "" {
+		t.Fatalf("expected dirty tree with diff hash, got %v", dirty)
+	}
+
+	runTestGit(t, repo, "add", "README")
+	staged := decodeTestMeta(t, mustGitContextMeta(t))
+	if staged[gitMetaDiffHash] != dirty[gitMetaDiffHash] {
+		t.Fatalf("expected staging to keep the diff hash, got %q and %q", dirty[gitMetaDiffHash], staged[gitMetaDiffHash])
+	}
+}
+
+func TestGitContextMetaOutsideWorkTree(t *testing.T) {
+	requireGit(t)
+	dir := t.TempDir()
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+	t.Chdir(dir)
+
+	meta, err := gitContextMeta()
+	if err != nil {
+		t.Fatalf("gitContextMeta: %v", err)
+	}
+	if meta != nil {
+		t.Fatalf("expected no git meta, got %s", meta)
+	}
+}
+
+func TestRunCaptureRecordsGitContext(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	initTestRepo(t)
+
+	output, err := captureStdout(func() error {
+		return RunCapture([]string{"--author", "Ada", "--prompt", "p", "--response", "r", "--meta", "git_branch=override"})
+	})
+	if err != nil {
+		t.Fatalf("RunCapture: %v", err)
+	}
+	meta := decodeTestMeta(t, loadCapturedIntent(t, output).Meta)
+	if meta[gitMetaCommit] == "" || meta[gitMetaDirty] != "false" {
+		t.Fatalf("expected git context in meta, got %v", meta)
+	}
+	if meta[gitMetaBranch] != "override" {
+		t.Fatalf("expected explicit meta to win, got %q", meta[gitMetaBranch])
+	}
+
+	output, err = captureStdout(func() error {
+		return RunCapture([]string{"--author", "Ada", "--prompt", "p", "--response", "r", "--no-git"})
+	})
+	if err != nil {
+		t.Fatalf("RunCapture --no-git: %v", err)
+	}
+	if record := loadCapturedIntent(t, output); record.Meta != nil {
+		t.Fatalf("expected no meta with --no-git, got %s", record.Meta)
+	}
+}
+
+func TestCheckpointCreateRecordsGitContext(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+	initTestRepo(t)
+
+	if _, err := captureStdout(func() error {
+		return RunCheckpoint([]string{"create", "--summary", "with git"})
+	}); err != nil {
+		t.Fatalf("RunCheckpoint create: %v", err)
+	}
+	if _, err := captureStdout(func() error {
+		return RunCheckpoint([]string{"create", "--summary", "without git", "--no-git"})
+	}); err != nil {
+		t.Fatalf("RunCheckpoint create --no-git: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+	checkpoints, err := yanzilibrary.ListCheckpoints(context.Background(), db, "alpha")
+	if err != nil {
+		t.Fatalf("list checkpoints: %v", err)
+	}
+	for _, checkpoint := range checkpoints {
+		switch checkpoint.Summary {
+		case "with git":
+			if meta := decodeTestMeta(t, checkpoint.Meta); meta[gitMetaCommit] == "" {
+				t.Fatalf("expected git commit in checkpoint meta, got %v", meta)
+			}
+		case "without git":
+			if checkpoint.Meta != nil {
+				t.Fatalf("expected no meta with --no-git, got %s", checkpoint.Meta)
+			}
+		}
+	}
+}
+
+// initTestRepo creates a git repository with one commit on main and makes it the working directory.
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+	requireGit(t)
+
+	repo := t.TempDir()
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	runTestGit(t, repo, "init", "--quiet", "--initial-branch=main")
+	writeRepoFile(t, repo, "README", "hello\n")
+	runTestGit(t, repo, "add", "README")
+	runTestGit(t, repo, "-c", "user.name=Ada", "-c", "user.email=ada@example.com", "commit", "--quiet", "-m", "initial")
+	t.Chdir(repo)
+	return repo
+}
+
+func requireGit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+}
+
+func runTestGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func writeRepoFile(t *testing.T, repo, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
+
+func mustGitContextMeta(t *testing.T) json.RawMessage {
+	t.Helper()
+	meta, err := gitContextMeta()
+	if err != nil {
+		t.Fatalf("gitContextMeta: %v", err)
+	}
+	return meta
+}
+
+func decodeTestMeta(t *testing.T, raw json.RawMessage) map[string]string {
+	t.Helper()
+	meta := map[string]string{}
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		t.Fatalf("decode meta %s: %v", raw, err)
+	}
+	return meta
+}
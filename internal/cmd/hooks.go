@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/redact"
+)
+
+// Hook stage names match their config.yaml keys and are exported to hooks as YANZI_HOOK.
+const (
+	hookPreCapture     = "pre_capture"
+	hookPostCapture    = "post_capture"
+	hookPreCheckpoint  = "pre_checkpoint"
+	hookPostCheckpoint = "post_checkpoint"
+	hookPreExport      = "pre_export"
+	hookPostExport     = "post_export"
+)
+
+// pendingCheckpoint is the record pre_checkpoint hooks receive and may modify.
+type pendingCheckpoint struct {
+	Project string          `json:"project"`
+	Summary string          `json:"summary"`
+	Meta    json.RawMessage `json:"meta,omitempty"`
+}
+
+// pendingExport is the record pre_export hooks receive and may modify.
+type pendingExport struct {
+	Project string `json:"project"`
+	Format  string `json:"format"`
+	Path    string `json:"path"`
+}
+
+// exportRecord describes a written export for post_export hooks. Hash is the
+// SHA-256 of the file contents.
+type exportRecord struct {
+	Project string `json:"project"`
+	Format  string `json:"format"`
+	Path    string `json:"path"`
+	Hash    string `json:"hash"`
+}
+
+// runPreHooks pipes record through each command in order and returns the final record as JSON.
+// A command that exits non-zero vetoes the record; one that prints JSON replaces the record
+// passed to the next command, and one that prints nothing leaves it unchanged.
+func runPreHooks(stage string, commands []string, record any) ([]byte, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s record: %w", stage, err)
+	}
+	for _, command := range commands {
+		var stdout bytes.Buffer
+		if err := runHook(stage, command, data, &stdout); err != nil {
+			return nil, fmt.Errorf("%s hook %q rejected the record: %w", stage, command, err)
+		}
+		out := bytes.TrimSpace(stdout.Bytes())
+		if len(out) == 0 {
+			continue
+		}
+		if !json.Valid(out) {
+			return nil, fmt.Errorf("%s hook %q printed invalid JSON", stage, command)
+		}
+		data = out
+	}
+	return data, nil
+}
+
+// runPostHooks hands the stored record to each command. The record is already stored, so
+// a failing hook is reported on stderr instead of failing the command. Hook output goes
+// to stderr to keep yanzi's own output parseable.
+func runPostHooks(stage string, commands []string, record any) {
+	if len(commands) == 0 {
+		return
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: encode %s record: %v\n", stage, err)
+		return
+	}
+	for _, command := range commands {
+		if err := runHook(stage, command, data, os.Stderr); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %s hook %q failed: %v\n", stage, command, err)
+		}
+	}
+}
+
+// runHook runs command through sh with record on stdin and YANZI_HOOK set to stage.
+func runHook(stage, command string, record []byte, stdout io.Writer) error {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(record)
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "YANZI_HOOK="+stage)
+	return cmd.Run()
+}
+
+// runCapturePreHooks passes a prepared, already redacted capture through the
+// pre_capture hooks, validates whatever they return, and redacts it again. Hooks may
+// not change the project.
+func runCapturePreHooks(cfg config.Config, redactor *redact.Redactor, input createIntentInput) (createIntentInput, error) {
+	if len(cfg.Hooks.PreCapture) == 0 {
+		return input, nil
+	}
+	data, err := runPreHooks(hookPreCapture, cfg.Hooks.PreCapture, input)
+	if err != nil {
+		return createIntentInput{}, err
+	}
+	modified, err := decodeCaptureJSON(data)
+	if err != nil {
+		return createIntentInput{}, fmt.Errorf("%s hook output: %w", hookPreCapture, err)
+	}
+	if err := validateCaptureInput(modified); err != nil {
+		return createIntentInput{}, fmt.Errorf("%s hook output: %w", hookPreCapture, err)
+	}
+	if metaProject(modified.Meta) != metaProject(input.Meta) {
+		return createIntentInput{}, fmt.Errorf("%s hook output: project cannot be changed", hookPreCapture)
+	}
+	modified, _, err = redactCapture(redactor, modified)
+	return modified, err
+}
+
+// runCheckpointPreHooks passes a pending, already redacted checkpoint through the
+// pre_checkpoint hooks and redacts what they return again. Hooks may change the summary
+// and meta but not the project.
+func runCheckpointPreHooks(cfg config.Config, redactor *redact.Redactor, pending pendingCheckpoint) (pendingCheckpoint, error) {
+	if len(cfg.Hooks.PreCheckpoint) == 0 {
+		return pending, nil
+	}
+	data, err := runPreHooks(hookPreCheckpoint, cfg.Hooks.PreCheckpoint, pending)
+	if err != nil {
+		return pendingCheckpoint{}, err
+	}
+	var modified pendingCheckpoint
+	if err := decodeHookOutput(data, &modified); err != nil {
+		return pendingCheckpoint{}, fmt.Errorf("%s hook output: %w", hookPreCheckpoint, err)
+	}
+	if modified.Project != pending.Project {
+		return pendingCheckpoint{}, fmt.Errorf("%s hook output: project cannot be changed", hookPreCheckpoint)
+	}
+	if strings.TrimSpace(modified.Summary) == "" {
+		return pendingCheckpoint{}, fmt.Errorf("%s hook output: summary is required", hookPreCheckpoint)
+	}
+	if string(modified.Meta) == "null" {
+		modified.Meta = nil
+	}
+	return redactCheckpoint(redactor, modified)
+}
+
+// metaProject returns the project named in a capture's meta, or "" when it has none.
+func metaProject(meta json.RawMessage) string {
+	var fields struct {
+		Project string `json:"project"`
+	}
+	if len(meta) == 0 || json.Unmarshal(meta, &fields) != nil {
+		return ""
+	}
+	return fields.Project
+}
+
+// runExportPreHooks passes a pending export through the pre_export hooks.
+// Hooks may change the output path but not the project or format.
+func runExportPreHooks(cfg config.Config, pending pendingExport) (pendingExport, error) {
+	if len(cfg.Hooks.PreExport) == 0 {
+		return pending, nil
+	}
+	data, err := runPreHooks(hookPreExport, cfg.Hooks.PreExport, pending)
+	if err != nil {
+		return pendingExport{}, err
+	}
+	var modified pendingExport
+	if err := decodeHookOutput(data, &modified); err != nil {
+		return pendingExport{}, fmt.Errorf("%s hook output: %w", hookPreExport, err)
+	}
+	if modified.Project != pending.Project || modified.Format != pending.Format {
+		return pendingExport{}, fmt.Errorf("%s hook output: project and format cannot be changed", hookPreExport)
+	}
+	if strings.TrimSpace(modified.Path) == "" {
+		return pendingExport{}, fmt.Errorf("%s hook output: path is required", hookPreExport)
+	}
+	return modified, nil
+}
+
+// decodeHookOutput strictly decodes a single JSON object returned by a pre-hook.
+func decodeHookOutput(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("expected a single JSON object")
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	yanzilibrary "github.com/chuxorg/chux-yanzi-cli/internal/library"
+)
+
+func TestRunCapturePreHookModifiesRecord(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	postFile := filepath.Join(home, "post.json")
+	appendTestConfig(t, home, "hooks:\n  pre_capture:\n    - 'sed \"s/hello/goodbye/g\"'\n    - 'true'\n  post_capture:\n    - 'cat > "+postFile+"'\n")
+
+	output, err := captureStdout(func() error {
+		return RunCapture([]string{"--author", "Ada", "--no-git", "--prompt", "hello", "--response", "hello back"})
+	})
+	if err != nil {
+		t.Fatalf("RunCapture: %v", err)
+	}
+
+	record := loadCapturedIntent(t, output)
+	if record.Prompt != "goodbye" || record.Response != "goodbye back" {
+		t.Fatalf("expected hook changes stored, got %q / %q", record.Prompt, record.Response)
+	}
+	verifyOutput, err := captureStdout(func() error {
+		return RunVerify([]string{record.ID})
+	})
+	if err != nil || !strings.Contains(verifyOutput, "✔ VALID") {
+		t.Fatalf("expected hooked capture to verify, got %q (%v)", verifyOutput, err)
+	}
+
+	data, err := os.ReadFile(postFile)
+	if err != nil {
+		t.Fatalf("read post hook output: %v", err)
+	}
+	var posted struct {
+		ID   string `json:"id"`
+		Hash string `json:"hash"`
+	}
+	if err := json.Unmarshal(data, &posted); err != nil {
+		t.Fatalf("decode post hook record: %v", err)
+	}
+	if posted.ID != record.ID || posted.Hash != record.Hash {
+		t.Fatalf("expected stored record in post hook, got %+v", posted)
+	}
+}
+
+func TestRunCapturePreHookVeto(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	postFile := filepath.Join(home, "post.json")
+	appendTestConfig(t, home, "hooks:\n  pre_capture:\n    - 'echo no TODOs allowed >&2; exit 1'\n  post_capture:\n    - 'cat > "+postFile+"'\n")
+
+	output, err := captureStdout(func() error {
+		return RunCapture([]string{"--author", "Ada", "--no-git", "--prompt", "TODO", "--response", "ok"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "pre_capture hook") || !strings.Contains(err.Error(), "rejected") {
+		t.Fatalf("expected veto error, got %v", err)
+	}
+	if strings.Contains(output, "id: ") {
+		t.Fatalf("expected nothing stored, got %q", output)
+	}
+	if _, err := os.Stat(postFile); !os.IsNotExist(err) {
+		t.Fatalf("expected post hook not to run, got %v", err)
+	}
+}
+
+func TestRunCapturePreHookOutputValidated(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	appendTestConfig(t, home, "hooks:\n  pre_capture:\n    - 'echo \"{\\\"author\\\":\\\"Ada\\\",\\\"prompt\\\":\\\"p\\\",\\\"response\\\":\\\"\\\"}\"'\n")
+
+	err := RunCapture([]string{"--author", "Ada", "--no-git", "--prompt", "p", "--response", "r"})
+	if err == nil || !strings.Contains(err.Error(), "pre_capture hook output: response must not be empty") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestRunCapturePreHooksSeeRedactedStoredCaptures(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+	seenFile := filepath.Join(home, "seen.ndjson")
+	appendTestConfig(t, home, "hooks:\n  pre_capture:\n    - 'tee -a "+seenFile+" | sed \"s/LATER/"+testGitHubToken+"/\"'\n")
+
+	if _, err := captureStdout(func() error { return RunPause(nil) }); err != nil {
+		t.Fatalf("RunPause: %v", err)
+	}
+	var exitErr *ExitError
+	err := RunCapture([]string{"--author", "Ada", "--no-git", "--prompt", "p", "--response", "r", "--when-paused", "queue"})
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCaptureQueued {
+		t.Fatalf("expected queued exit error, got %v", err)
+	}
+	if _, err := os.Stat(seenFile); !os.IsNotExist(err) {
+		t.Fatalf("expected pre-hooks not to run for a queued capture, got %v", err)
+	}
+	if _, err := captureStdout(func() error { return RunResume(nil) }); err != nil {
+		t.Fatalf("RunResume: %v", err)
+	}
+	if data, err := os.ReadFile(seenFile); err != nil || strings.Count(string(data), `"author"`) != 1 {
+		t.Fatalf("expected pre-hooks to run once when the queue drains, got %q (%v)", data, err)
+	}
+
+	output, err := captureStdout(func() error {
+		return RunCapture([]string{"--author", "Ada", "--no-git", "--prompt", "use " + testGitHubToken, "--response", "LATER"})
+	})
+	if err != nil {
+		t.Fatalf("RunCapture: %v", err)
+	}
+	data, err := os.ReadFile(seenFile)
+	if err != nil {
+		t.Fatalf("read hook input: %v", err)
+	}
+	if strings.Contains(string(data), testGitHubToken) {
+		t.Fatalf("expected pre-hooks to receive redacted text, got %q", data)
+	}
+	record := loadCapturedIntent(t, output)
+	if record.Response != "[REDACTED:github-token]" || !strings.Contains(string(record.Meta), `"redactions":"github-token:2"`) {
+		t.Fatalf("expected hook output redacted and counted with the first pass, got %q %s", record.Response, record.Meta)
+	}
+}
+
+func TestRunCapturePreHookCannotChangeProject(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+	appendTestConfig(t, home, "hooks:\n  pre_capture:\n    - 'sed \"s/\\\"alpha\\\"/\\\"beta\\\"/\"'\n")
+
+	err := RunCapture([]string{"--author", "Ada", "--no-git", "--prompt", "p", "--response", "r"})
+	if err == nil || !strings.Contains(err.Error(), "project cannot be changed") {
+		t.Fatalf("expected project change error, got %v", err)
+	}
+}
+
+func TestCheckpointCreateHooks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+	postFile := filepath.Join(home, "post.json")
+	appendTestConfig(t, home, "hooks:\n  pre_checkpoint:\n    - 'sed \"s/draft/final/\"'\n  post_checkpoint:\n    - 'cat > "+postFile+"'\n")
+
+	output, err := captureStdout(func() error {
+		return RunCheckpoint([]string{"create", "--summary", "draft release", "--no-git"})
+	})
+	if err != nil {
+		t.Fatalf("RunCheckpoint create: %v", err)
+	}
+	if !strings.Contains(output, "summary: final release") {
+		t.Fatalf("expected hooked summary, got %q", output)
+	}
+
+	data, err := os.ReadFile(postFile)
+	if err != nil {
+		t.Fatalf("read post hook output: %v", err)
+	}
+	var posted yanzilibrary.Checkpoint
+	if err := json.Unmarshal(data, &posted); err != nil {
+		t.Fatalf("decode post hook record: %v", err)
+	}
+	if posted.Summary != "final release" || !strings.Contains(output, "id: "+posted.Hash) {
+		t.Fatalf("expected stored checkpoint in post hook, got %+v", posted)
+	}
+}
+
+func TestCheckpointCreatePreHookCannotChangeProject(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+	appendTestConfig(t, home, "hooks:\n  pre_checkpoint:\n    - 'sed \"s/alpha/beta/\"'\n")
+
+	err := RunCheckpoint([]string{"create", "--summary", "first", "--no-git"})
+	if err == nil || !strings.Contains(err.Error(), "project cannot be changed") {
+		t.Fatalf("expected project change error, got %v", err)
+	}
+}
+
+func TestRunExportHooks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+	workDir := t.TempDir()
+	withCwd(t, workDir)
+	postFile := filepath.Join(home, "post.json")
+	appendTestConfig(t, home, "hooks:\n  pre_export:\n    - 'sed \"s/YANZI_LOG/ALPHA_LOG/\"'\n  post_export:\n    - 'cat > "+postFile+"'\n")
+
+	output, err := captureStdout(func() error {
+		return RunExport([]string{"--format", "markdown"}, "test")
+	})
+	if err != nil {
+		t.Fatalf("RunExport: %v", err)
+	}
+	if !strings.Contains(output, "Exported ALPHA_LOG.md") {
+		t.Fatalf("expected hooked path, got %q", output)
+	}
+	content, err := os.ReadFile(filepath.Join(workDir, "ALPHA_LOG.md"))
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+
+	data, err := os.ReadFile(postFile)
+	if err != nil {
+		t.Fatalf("read post hook output: %v", err)
+	}
+	var posted exportRecord
+	if err := json.Unmarshal(data, &posted); err != nil {
+		t.Fatalf("decode post hook record: %v", err)
+	}
+	sum := sha256.Sum256(content)
+	if posted.Project != "alpha" || posted.Path != "ALPHA_LOG.md" || posted.Hash != hex.EncodeToString(sum[:]) {
+		t.Fatalf("unexpected post hook record %+v", posted)
+	}
+}
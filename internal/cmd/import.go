@@ -0,0 +1,411 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/redact"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/signing"
+	"github.com/chuxorg/chux-yanzi-cli/internal/importer"
+	yanzilibrary "github.com/chuxorg/chux-yanzi-cli/internal/library"
+)
+
+// importParsers maps each import kind, which is also the source type of the
+// intents it creates, to the parser for its export format.
+var importParsers = map[string]func(io.Reader) ([]importer.Conversation, error){
+	"transcript": parseTranscriptConversation,
+	"chatgpt":    importer.ParseChatGPT,
+	"claude":     importer.ParseClaude,
+	"aider":      importer.ParseAider,
+}
+
+// RunImport handles import subcommands.
+func RunImport(args []string) error {
+	if len(args) == 0 {
+		return importUsageError()
+	}
+	if _, ok := importParsers[args[0]]; !ok {
+		return importUsageError()
+	}
+	return runImportKind(args[0], args[1:])
+}
+
+func runImportKind(kind string, args []string) error {
+	fs := newFlagSet("import " + kind)
+	author := fs.String("author", "", "author for imported turns (required unless every route names one)")
+	project := fs.String("project", "", "project for imported turns (default: active project)")
+	list := fs.Bool("list", false, "list conversations in the export without importing")
+	routeFlags := &kvPairs{}
+	fs.Var(routeFlags, "route", "conversation-id=project[:author] (optional, repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: yanzi import %s [--author <name>] [--project <name>] [--route id=project[:author]] [--list] <file|->", kind)
+	}
+
+	data, err := readImportFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	conversations, err := importParsers[kind](bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(conversations, func(i, j int) bool {
+		return conversations[i].CreatedAt < conversations[j].CreatedAt
+	})
+	if *list {
+		return printImportConversations(conversations)
+	}
+
+	routes, err := parseImportRoutes(*routeFlags, conversations)
+	if err != nil {
+		return err
+	}
+	defaults := importTarget{Project: strings.TrimSpace(*project), Author: strings.TrimSpace(*author)}
+	if defaults.Project == "" {
+		defaults.Project, err = loadActiveProject()
+		if err != nil {
+			return err
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Mode != config.ModeLocal {
+		return errors.New("import is only available in local mode")
+	}
+	redactor, err := newRedactor(cfg)
+	if err != nil {
+		return err
+	}
+	signer, err := loadSigner(cfg)
+	if err != nil {
+		return err
+	}
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	targets, unrouted, err := resolveImportTargets(ctx, db, conversations, routes, defaults)
+	if err != nil {
+		return err
+	}
+
+	total := importResult{Unrouted: unrouted}
+	digests := map[string]map[string]struct{}{}
+	for i, conversation := range conversations {
+		target, ok := targets[i]
+		if !ok {
+			continue
+		}
+		existing, ok := digests[target.Project]
+		if !ok {
+			existing, err = loadImportDigests(ctx, db, target.Project)
+			if err != nil {
+				return err
+			}
+			digests[target.Project] = existing
+		}
+		result, err := importConversation(ctx, db, redactor, signer, target, kind, conversation, existing)
+		if err != nil {
+			if conversation.ID != "" {
+				return fmt.Errorf("import conversation %s: %w", conversation.ID, err)
+			}
+			return err
+		}
+		total.Conversations++
+		total.Imported += result.Imported
+		total.Existing += result.Existing
+		total.Unpaired += result.Unpaired
+	}
+	return printImportResult(total)
+}
+
+// importTarget is the project and author a conversation is imported into.
+type importTarget struct {
+	Project string
+	Author  string
+}
+
+// importResult summarizes one import run.
+type importResult struct {
+	Conversations int `json:"conversations"`
+	Imported      int `json:"imported"`
+	Existing      int `json:"already_imported"`
+	Unpaired      int `json:"unpaired_messages"`
+	Unrouted      int `json:"unrouted_conversations"`
+}
+
+// importConversationSummary describes one conversation in an export for --list.
+type importConversationSummary struct {
+	ID        string `json:"id"`
+	CreatedAt string `json:"created_at"`
+	Turns     int    `json:"turns"`
+	Title     string `json:"title"`
+}
+
+// parseImportRoutes parses --route values of the form id=project[:author] and
+// rejects routes for conversations the export does not contain.
+func parseImportRoutes(values []string, conversations []importer.Conversation) (map[string]importTarget, error) {
+	known := make(map[string]bool, len(conversations))
+	for _, conversation := range conversations {
+		known[conversation.ID] = true
+	}
+
+	routes := make(map[string]importTarget, len(values))
+	for _, value := range values {
+		id, target, ok := strings.Cut(value, "=")
+		id = strings.TrimSpace(id)
+		if !ok || id == "" {
+			return nil, fmt.Errorf("invalid --route %q: expected conversation-id=project[:author]", value)
+		}
+		projectName, authorName, _ := strings.Cut(target, ":")
+		route := importTarget{Project: strings.TrimSpace(projectName), Author: strings.TrimSpace(authorName)}
+		if route.Project == "" {
+			return nil, fmt.Errorf("invalid --route %q: project is required", value)
+		}
+		if !known[id] {
+			return nil, fmt.Errorf("invalid --route %q: conversation not found in export: %s", value, id)
+		}
+		routes[id] = route
+	}
+	return routes, nil
+}
+
+// resolveImportTargets assigns each conversation, by index, a project and author from
+// its route or the defaults. Conversations with no project are left out and counted.
+// Every target project must exist and must not be paused.
+func resolveImportTargets(ctx context.Context, db *sql.DB, conversations []importer.Conversation, routes map[string]importTarget, defaults importTarget) (map[int]importTarget, int, error) {
+	targets := make(map[int]importTarget, len(conversations))
+	checked := map[string]bool{}
+	unrouted := 0
+	for i, conversation := range conversations {
+		target, ok := routes[conversation.ID]
+		if !ok {
+			target = importTarget{Project: defaults.Project}
+		}
+		if target.Author == "" {
+			target.Author = defaults.Author
+		}
+		if target.Project == "" {
+			unrouted++
+			continue
+		}
+		if target.Author == "" {
+			return nil, 0, errors.New("--author is required (or name one in each --route)")
+		}
+		if !checked[target.Project] {
+			if err := checkImportProject(ctx, db, target.Project); err != nil {
+				return nil, 0, err
+			}
+			checked[target.Project] = true
+		}
+		targets[i] = target
+	}
+	if len(targets) == 0 && unrouted > 0 {
+		return nil, 0, errors.New("no active project set; pass --project or --route")
+	}
+	return targets, unrouted, nil
+}
+
+// checkImportProject refuses to import into a project that does not exist or is paused.
+func checkImportProject(ctx context.Context, db *sql.DB, project string) error {
+	exists, err := yanzilibrary.ProjectExists(ctx, db, project)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return yanzilibrary.ProjectNotFoundError{Name: project}
+	}
+	pause, paused, err := loadPauseState(project)
+	if err != nil {
+		return err
+	}
+	if paused {
+		return fmt.Errorf("project %s is %s; resume before importing", project, describePause(pause))
+	}
+	return nil
+}
+
+// importConversation stores the turns of one conversation as chained intents for a
+// project. Turns whose digest is in existing, the digests already recorded for the
+// project, are skipped, so importing the same conversation again, or a longer version
+// of it, only adds new turns. Stored digests are added to existing. Digests are computed
+// before redaction, so redaction rules do not affect idempotency.
+func importConversation(ctx context.Context, db *sql.DB, redactor *redact.Redactor, signer *signing.Key, target importTarget, source string, conversation importer.Conversation, existing map[string]struct{}) (importResult, error) {
+	turns, unpaired, err := conversation.Turns()
+	if err != nil {
+		return importResult{}, err
+	}
+
+	result := importResult{Unpaired: unpaired}
+	inputs := make([]createIntentInput, 0, len(turns))
+	added := make([]string, 0, len(turns))
+	for _, turn := range turns {
+		if _, ok := existing[turn.Digest]; ok {
+			result.Existing++
+			continue
+		}
+		added = append(added, turn.Digest)
+		meta, err := importTurnMeta(target.Project, source, conversation, turn)
+		if err != nil {
+			return importResult{}, err
+		}
+		input, _, err := redactCapture(redactor, createIntentInput{
+			Author:     target.Author,
+			SourceType: source,
+			Title:      conversation.Title,
+			Prompt:     turn.Prompt,
+			Response:   turn.Response,
+			Meta:       meta,
+		})
+		if err != nil {
+			return importResult{}, err
+		}
+		inputs = append(inputs, input)
+	}
+	if len(inputs) == 0 {
+		return result, nil
+	}
+
+	records, err := storeLocalIntents(ctx, db, inputs, target.Project, signer)
+	if err != nil {
+		return importResult{}, err
+	}
+	for _, digest := range added {
+		existing[digest] = struct{}{}
+	}
+	result.Imported = len(records)
+	return result, nil
+}
+
+// importTurnMeta records where a turn came from: the import digest used for
+// idempotency plus the conversation id, title, model, and original timestamps.
+func importTurnMeta(project, source string, conversation importer.Conversation, turn importer.Turn) (json.RawMessage, error) {
+	meta := map[string]string{
+		"import_source": source,
+		"import_digest": turn.Digest,
+		"import_turn":   strconv.Itoa(turn.Index),
+	}
+	optional := map[string]string{
+		"import_conversation_id":         conversation.ID,
+		"import_conversation_title":      conversation.Title,
+		"import_conversation_created_at": conversation.CreatedAt,
+		"import_model":                   turn.Model,
+		"import_prompted_at":             turn.PromptedAt,
+		"import_responded_at":            turn.RespondedAt,
+	}
+	for key, value := range optional {
+		if value != "" {
+			meta[key] = value
+		}
+	}
+	encoded, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("encode meta: %w", err)
+	}
+	return attachProjectMeta(encoded, project)
+}
+
+// loadImportDigests returns the import digests already recorded for a project. It
+// filters on the indexed project column, which is only set for valid meta.
+func loadImportDigests(ctx context.Context, db *sql.DB, project string) (map[string]struct{}, error) {
+	rows, err := db.QueryContext(ctx, `SELECT json_extract(meta, '$.import_digest')
+		FROM intents
+		WHERE project = ?
+			AND json_extract(meta, '$.import_digest') IS NOT NULL`, project)
+	if err != nil {
+		return nil, fmt.Errorf("read import digests: %w", err)
+	}
+	defer rows.Close()
+
+	digests := map[string]struct{}{}
+	for rows.Next() {
+		var digest string
+		if err := rows.Scan(&digest); err != nil {
+			return nil, fmt.Errorf("read import digests: %w", err)
+		}
+		digests[digest] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read import digests: %w", err)
+	}
+	return digests, nil
+}
+
+// parseTranscriptConversation wraps a plain transcript as a single conversation.
+func parseTranscriptConversation(r io.Reader) ([]importer.Conversation, error) {
+	messages, err := importer.ParseTranscript(r)
+	if err != nil {
+		return nil, err
+	}
+	return []importer.Conversation{{Messages: messages}}, nil
+}
+
+// readImportFile reads an export file, or stdin when path is "-".
+func readImportFile(path string) ([]byte, error) {
+	if path == stdinPath {
+		return readStdin()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read import file: %w", err)
+	}
+	return data, nil
+}
+
+func printImportConversations(conversations []importer.Conversation) error {
+	summaries := make([]importConversationSummary, 0, len(conversations))
+	for _, conversation := range conversations {
+		turns, _, err := conversation.Turns()
+		if err != nil {
+			return fmt.Errorf("conversation %s: %w", conversation.ID, err)
+		}
+		summaries = append(summaries, importConversationSummary{
+			ID:        conversation.ID,
+			CreatedAt: conversation.CreatedAt,
+			Turns:     len(turns),
+			Title:     conversation.Title,
+		})
+	}
+	return printList("conversations", summaries, func() {
+		for _, summary := range summaries {
+			fmt.Printf("%s\t%s\t%d turns\t%s\n", summary.ID, summary.CreatedAt, summary.Turns, summary.Title)
+		}
+	})
+}
+
+func printImportResult(result importResult) error {
+	return printResult(result, func() {
+		fmt.Printf("conversations: %d\n", result.Conversations)
+		fmt.Printf("imported: %d\n", result.Imported)
+		fmt.Printf("already_imported: %d\n", result.Existing)
+		if result.Unpaired > 0 {
+			fmt.Printf("unpaired_messages: %d\n", result.Unpaired)
+		}
+		if result.Unrouted > 0 {
+			fmt.Printf("unrouted_conversations: %d\n", result.Unrouted)
+		}
+	})
+}
+
+func importUsageError() error {
+	return errors.New("usage: yanzi import <transcript|chatgpt|claude|aider> [--author <name>] [--project <name>] [--route id=project[:author]] [--list] <file|->")
+}
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+)
+
+func TestImportTranscriptIsIdempotentAndChained(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	path := filepath.Join(home, "session.ndjson")
+	writeTranscript(t, path,
+		`{"role":"user","content":"one"}`,
+		`{"role":"assistant","content":"first"}`,
+		`{"role":"user","content":"two"}`,
+		`{"role":"assistant","content":"second"}`,
+	)
+
+	output, err := captureStdout(func() error {
+		return RunImport([]string{"transcript", "--author", "Ada", path})
+	})
+	if err != nil {
+		t.Fatalf("RunImport: %v", err)
+	}
+	if !strings.Contains(output, "imported: 2\n") || !strings.Contains(output, "already_imported: 0\n") {
+		t.Fatalf("unexpected first import output: %q", output)
+	}
+
+	output, err = captureStdout(func() error {
+		return RunImport([]string{"transcript", "--author", "Ada", path})
+	})
+	if err != nil {
+		t.Fatalf("RunImport again: %v", err)
+	}
+	if !strings.Contains(output, "imported: 0\n") || !strings.Contains(output, "already_imported: 2\n") {
+		t.Fatalf("expected re-import to add nothing, got %q", output)
+	}
+
+	writeTranscript(t, path,
+		`{"role":"user","content":"one"}`,
+		`{"role":"assistant","content":"first"}`,
+		`{"role":"user","content":"two"}`,
+		`{"role":"assistant","content":"second"}`,
+		`{"role":"user","content":"three"}`,
+		`{"role":"assistant","content":"third"}`,
+	)
+	output, err = captureStdout(func() error {
+		return RunImport([]string{"transcript", "--author", "Ada", path})
+	})
+	if err != nil {
+		t.Fatalf("RunImport extended: %v", err)
+	}
+	if !strings.Contains(output, "imported: 1\n") || !strings.Contains(output, "already_imported: 2\n") {
+		t.Fatalf("expected only the new turn, got %q", output)
+	}
+
+	prompts := loadImportedChain(t)
+	if strings.Join(prompts, ",") != "one,two,three" {
+		t.Fatalf("expected chained turns in order, got %v", prompts)
+	}
+}
+
+func TestImportTranscriptDedupesByContent(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	lines := []string{
+		`{"role":"user","content":"status?"}`,
+		`{"role":"assistant","content":"all green"}`,
+		`{"role":"user","content":"deploy?"}`,
+		`{"role":"assistant","content":"shipped"}`,
+	}
+	original := filepath.Join(home, "session.ndjson")
+	copied := filepath.Join(home, "archive", "session-copy.ndjson")
+	if err := os.MkdirAll(filepath.Dir(copied), 0o700); err != nil {
+		t.Fatalf("create archive dir: %v", err)
+	}
+	writeTranscript(t, original, lines...)
+	writeTranscript(t, copied, lines...)
+
+	runImport := func(source string) string {
+		t.Helper()
+		output, err := captureStdout(func() error {
+			return RunImport([]string{"transcript", "--author", "Ada", source})
+		})
+		if err != nil {
+			t.Fatalf("RunImport %s: %v", source, err)
+		}
+		return output
+	}
+	if output := runImport(original); !strings.Contains(output, "imported: 2\n") {
+		t.Fatalf("expected two turns imported, got %q", output)
+	}
+	// The same turns from another path or from stdin are already stored.
+	if output := runImport(copied); !strings.Contains(output, "imported: 0\n") {
+		t.Fatalf("expected the copy to import nothing, got %q", output)
+	}
+	withStdin(t, strings.Join(lines, "\n")+"\n")
+	if output := runImport(stdinPath); !strings.Contains(output, "imported: 0\n") {
+		t.Fatalf("expected stdin to import nothing, got %q", output)
+	}
+	if prompts := loadImportedChain(t); len(prompts) != 2 {
+		t.Fatalf("expected two stored turns, got %q", prompts)
+	}
+
+	// A longer version on stdin adds only its new turn.
+	withStdin(t, strings.Join(append(lines, `{"role":"user","content":"rollback?"}`, `{"role":"assistant","content":"not needed"}`), "\n")+"\n")
+	if output := runImport(stdinPath); !strings.Contains(output, "imported: 1\n") {
+		t.Fatalf("expected only the new turn from stdin, got %q", output)
+	}
+	if prompts := loadImportedChain(t); len(prompts) != 3 {
+		t.Fatalf("expected three stored turns, got %q", prompts)
+	}
+}
+
+func writeTranscript(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatalf("write transcript: %v", err)
+	}
+}
+
+// loadImportedChain walks the active project's chain and returns prompts oldest first.
+func loadImportedChain(t *testing.T) []string {
+	t.Helper()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	headID, err := activeChainHeadID(ctx, db)
+	if err != nil {
+		t.Fatalf("chain head: %v", err)
+	}
+	chain, err := chainLocalIntent(ctx, db, headID)
+	if err != nil {
+		t.Fatalf("chain: %v", err)
+	}
+	prompts := make([]string, 0, len(chain.Intents))
+	for _, intent := range chain.Intents {
+		prompts = append(prompts, intent.Prompt)
+	}
+	return prompts
+}
+
+func TestImportClaudeRoutesConversations(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	createTestProject(t, "beta")
+	writeStateFile(t, home, "alpha")
+
+	path := filepath.Join(home, "conversations.json")
+	export := `[
+		{"uuid": "c-1", "name": "Auth", "created_at": "2024-05-01T09:00:00Z", "model": "claude-3-opus", "chat_messages": [
+			{"sender": "human", "text": "q1", "created_at": "2024-05-01T09:00:01Z"},
+			{"sender": "assistant", "text": "a1", "created_at": "2024-05-01T09:00:02Z"}
+		]},
+		{"uuid": "c-2", "name": "Billing", "created_at": "2024-05-02T09:00:00Z", "chat_messages": [
+			{"sender": "human", "text": "q2"},
+			{"sender": "assistant", "text": "a2"}
+		]}
+	]`
+	if err := os.WriteFile(path, []byte(export), 0o600); err != nil {
+		t.Fatalf("write export: %v", err)
+	}
+
+	listing, err := captureStdout(func() error {
+		return RunImport([]string{"claude", "--list", path})
+	})
+	if err != nil {
+		t.Fatalf("RunImport --list: %v", err)
+	}
+	if listing != "c-1\t2024-05-01T09:00:00Z\t1 turns\tAuth\nc-2\t2024-05-02T09:00:00Z\t1 turns\tBilling\n" {
+		t.Fatalf("unexpected listing: %q", listing)
+	}
+
+	output, err := captureStdout(func() error {
+		return RunImport([]string{"claude", "--author", "Ada", "--route", "c-2=beta:Bob", path})
+	})
+	if err != nil {
+		t.Fatalf("RunImport: %v", err)
+	}
+	if !strings.Contains(output, "conversations: 2\n") || !strings.Contains(output, "imported: 2\n") {
+		t.Fatalf("unexpected output: %q", output)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	var author, title, metaText string
+	err = db.QueryRow(`SELECT author, title, meta FROM intents WHERE json_extract(meta, '$.import_conversation_id') = 'c-1'`).Scan(&author, &title, &metaText)
+	if err != nil {
+		t.Fatalf("load c-1 intent: %v", err)
+	}
+	if author != "Ada" || title != "Auth" {
+		t.Fatalf("unexpected c-1 author/title: %s/%s", author, title)
+	}
+	for _, want := range []string{`"project":"alpha"`, `"import_model":"claude-3-opus"`, `"import_prompted_at":"2024-05-01T09:00:01Z"`, `"import_source":"claude"`} {
+		if !strings.Contains(metaText, want) {
+			t.Fatalf("expected %s in meta %s", want, metaText)
+		}
+	}
+
+	if err := db.QueryRow(`SELECT author, meta FROM intents WHERE json_extract(meta, '$.import_conversation_id') = 'c-2'`).Scan(&author, &metaText); err != nil {
+		t.Fatalf("load c-2 intent: %v", err)
+	}
+	if author != "Bob" || !strings.Contains(metaText, `"project":"beta"`) {
+		t.Fatalf("expected c-2 routed to beta as Bob, got %s %s", author, metaText)
+	}
+}
+
+func TestImportRejectsUnknownRoute(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+
+	path := filepath.Join(home, "conversations.json")
+	if err := os.WriteFile(path, []byte(`[{"uuid": "c-1", "chat_messages": []}]`), 0o600); err != nil {
+		t.Fatalf("write export: %v", err)
+	}
+	err := RunImport([]string{"claude", "--author", "Ada", "--route", "missing=beta", path})
+	if err == nil || !strings.Contains(err.Error(), "conversation not found") {
+		t.Fatalf("expected unknown route error, got %v", err)
+	}
+}
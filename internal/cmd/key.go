@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/signing"
+)
+
+// keyView is the structured output of the key commands.
+type keyView struct {
+	ID        string `json:"id"`
+	Owner     string `json:"owner,omitempty"`
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"`
+	Path      string `json:"path,omitempty"`
+	Active    bool   `json:"active"`
+	Trusted   bool   `json:"trusted"`
+}
+
+// RunKey executes the key command.
+func RunKey(args []string) error {
+	if len(args) == 0 {
+		return keyUsageError()
+	}
+
+	switch args[0] {
+	case "generate":
+		return runKeyGenerate(args[1:])
+	case "list":
+		return runKeyList(args[1:])
+	case "export":
+		return runKeyExport(args[1:])
+	default:
+		return keyUsageError()
+	}
+}
+
+func runKeyGenerate(args []string) error {
+	fs := newFlagSet("key generate")
+	owner := fs.String("owner", "", "name recorded with the key and shown as the signer")
+	trust := fs.Bool("trust", false, "add the public key to the trusted-keys file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errors.New("usage: yanzi key generate [--owner <name>] [--trust]")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	dir, err := config.KeysDir()
+	if err != nil {
+		return err
+	}
+	key, err := signing.Generate(dir, *owner)
+	if err != nil {
+		return err
+	}
+	if *trust {
+		if err := appendTrustedKey(cfg.Signing.TrustedKeys, key); err != nil {
+			return err
+		}
+	}
+
+	view := newKeyView(key, cfg, *trust)
+	return printResult(view, func() {
+		fmt.Printf("key: %s\n", key.ID)
+		fmt.Printf("path: %s\n", key.Path)
+		fmt.Printf("public_key: %s\n", key.TrustedLine())
+		if *trust {
+			fmt.Printf("trusted: %s\n", cfg.Signing.TrustedKeys)
+		}
+		fmt.Printf("set signing.key: %s in %s to sign records\n", key.ID, configPathOrDefault())
+	})
+}
+
+func runKeyList(args []string) error {
+	args, err := parseArgs("key list", args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return errors.New("usage: yanzi key list")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	dir, err := config.KeysDir()
+	if err != nil {
+		return err
+	}
+	keys, err := signing.LoadKeys(dir)
+	if err != nil {
+		return err
+	}
+	trusted, err := signing.LoadTrustedKeys(cfg.Signing.TrustedKeys)
+	if err != nil {
+		return err
+	}
+
+	views := make([]keyView, 0, len(keys))
+	for _, key := range keys {
+		_, ok := trusted[key.ID]
+		views = append(views, newKeyView(key, cfg, ok))
+	}
+	return printList("keys", views, func() {
+		fmt.Println("ID\tOwner\tActive\tTrusted")
+		for _, view := range views {
+			fmt.Printf("%s\t%s\t%t\t%t\n", view.ID, view.Owner, view.Active, view.Trusted)
+		}
+	})
+}
+
+func runKeyExport(args []string) error {
+	args, err := parseArgs("key export", args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		return errors.New("usage: yanzi key export [key-id]")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	id := cfg.Signing.Key
+	if len(args) == 1 {
+		id = args[0]
+	}
+	if strings.TrimSpace(id) == "" {
+		return errors.New("no key id given and signing.key is not set")
+	}
+	dir, err := config.KeysDir()
+	if err != nil {
+		return err
+	}
+	key, err := signing.LoadKey(dir, id)
+	if err != nil {
+		return err
+	}
+	trusted, err := signing.LoadTrustedKeys(cfg.Signing.TrustedKeys)
+	if err != nil {
+		return err
+	}
+	_, ok := trusted[key.ID]
+
+	view := newKeyView(key, cfg, ok)
+	view.Path = ""
+	return printResult(view, func() {
+		fmt.Println(key.TrustedLine())
+	})
+}
+
+// appendTrustedKey adds a key's trusted-keys line to path unless the key is already
+// trusted.
+func appendTrustedKey(path string, key signing.Key) error {
+	trusted, err := signing.LoadTrustedKeys(path)
+	if err != nil {
+		return err
+	}
+	if _, ok := trusted[key.ID]; ok {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create trusted keys dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("write trusted keys: %w", err)
+	}
+	if _, err := fmt.Fprintln(f, key.TrustedLine()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write trusted keys: %w", err)
+	}
+	return f.Close()
+}
+
+func newKeyView(key signing.Key, cfg config.Config, trusted bool) keyView {
+	active := cfg.Signing.Key != "" && strings.HasPrefix(key.ID, cfg.Signing.Key)
+	return keyView{
+		ID:        key.ID,
+		Owner:     key.Owner,
+		Algorithm: signing.Algorithm,
+		PublicKey: key.EncodedPublicKey(),
+		Path:      key.Path,
+		Active:    active,
+		Trusted:   trusted,
+	}
+}
+
+// configPathOrDefault names the config file in hints, falling back to its usual path.
+func configPathOrDefault() string {
+	path, err := config.ConfigPath()
+	if err != nil {
+		return "~/.yanzi/config.yaml"
+	}
+	return path
+}
+
+func keyUsageError() error {
+	return errors.New("usage: yanzi key <generate|list|export>")
+}
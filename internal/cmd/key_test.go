@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/client"
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
+)
+
+func TestSignedRecordsVerify(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	// A record captured before signing is enabled stays valid unless signatures are required.
+	if _, err := captureStdout(func() error {
+		return RunCapture([]string{"--author", "Ada", "--no-git", "--prompt", "unsigned", "--response", "ok"})
+	}); err != nil {
+		t.Fatalf("RunCapture: %v", err)
+	}
+
+	useOutputFormat(t, OutputJSON)
+	output, err := captureStdout(func() error { return RunKey([]string{"generate", "--owner", "Ada", "--trust"}) })
+	if err != nil {
+		t.Fatalf("key generate: %v", err)
+	}
+	var key keyView
+	if err := json.Unmarshal([]byte(output), &key); err != nil {
+		t.Fatalf("decode key %q: %v", output, err)
+	}
+	if !key.Trusted || key.Active || key.Owner != "Ada" {
+		t.Fatalf("expected a trusted, inactive key, got %+v", key)
+	}
+
+	appendTestConfig(t, home, "signing:\n  key: "+key.ID[:8]+"\n")
+
+	output, err = captureStdout(func() error {
+		return RunCapture([]string{"--author", "Ada", "--no-git", "--prompt", "signed", "--response", "ok"})
+	})
+	if err != nil {
+		t.Fatalf("RunCapture: %v", err)
+	}
+	var intent model.IntentRecord
+	if err := json.Unmarshal([]byte(output), &intent); err != nil {
+		t.Fatalf("decode capture: %v", err)
+	}
+	if intent.KeyID != key.ID || intent.Signature == "" {
+		t.Fatalf("expected the capture to be signed by %s, got %+v", key.ID, intent)
+	}
+	if _, err := captureStdout(func() error { return RunCheckpoint([]string{"create", "--summary", "signed", "--no-git"}) }); err != nil {
+		t.Fatalf("checkpoint create: %v", err)
+	}
+
+	output, err = captureStdout(func() error { return RunVerify([]string{intent.ID}) })
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	var verified client.VerifyResponse
+	if err := json.Unmarshal([]byte(output), &verified); err != nil {
+		t.Fatalf("decode verify: %v", err)
+	}
+	if !verified.Valid || verified.SignatureStatus != "verified" || verified.Signer != "Ada" {
+		t.Fatalf("expected a verified signature, got %+v", verified)
+	}
+
+	output, err = captureStdout(func() error { return RunVerify([]string{"--all"}) })
+	if err != nil {
+		t.Fatalf("verify --all: %v", err)
+	}
+	var audit ledgerAudit
+	if err := json.Unmarshal([]byte(output), &audit); err != nil {
+		t.Fatalf("decode audit: %v", err)
+	}
+	if audit.Status != ledgerClean || audit.Signed != 2 || audit.BadSignatures != 0 {
+		t.Fatalf("expected a clean audit with two signed records, got %+v", audit)
+	}
+
+	var exitErr *ExitError
+	if _, err := captureStdout(func() error { return RunVerify([]string{"--all", "--require-signed"}) }); !errors.As(err, &exitErr) || exitErr.Code != ExitLedgerTampered {
+		t.Fatalf("expected --require-signed to fail on the unsigned capture, got %v", err)
+	}
+
+	// Dropping the key from the trusted-keys file leaves its signatures untrusted.
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if err := os.WriteFile(cfg.Signing.TrustedKeys, nil, 0o644); err != nil {
+		t.Fatalf("clear trusted keys: %v", err)
+	}
+	output, err = captureStdout(func() error { return RunVerify([]string{"--all"}) })
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitLedgerTampered {
+		t.Fatalf("expected untrusted signatures to fail, got %v", err)
+	}
+	if err := json.Unmarshal([]byte(output), &audit); err != nil {
+		t.Fatalf("decode audit: %v", err)
+	}
+	if audit.BadSignatures != 2 || !strings.Contains(audit.Issues[0].Detail, "is not trusted") {
+		t.Fatalf("expected two untrusted signatures, got %+v", audit)
+	}
+}
+
+func TestSigningRequiresLocalMode(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	writeStateFile(t, home, "alpha")
+	configPath := filepath.Join(home, ".yanzi", "config.yaml")
+	if err := os.WriteFile(configPath, []byte("mode: http\nbase_url: http://127.0.0.1:1\nsigning:\n  key: 3f9a1c0d\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	// The library signs nothing, so a configured key must not yield unsigned records.
+	for name, run := range map[string]func() error{
+		"capture": func() error {
+			return RunCapture([]string{"--author", "Ada", "--no-git", "--prompt", "p", "--response", "r"})
+		},
+		"checkpoint create": func() error { return RunCheckpoint([]string{"create", "--summary", "s", "--no-git"}) },
+		"meta checkpoint":   func() error { return RunMeta([]string{"checkpoint", "Summary"}, "test") },
+	} {
+		_, err := captureStdout(run)
+		if err == nil || err.Error() != "signing is only available in local mode" {
+			t.Fatalf("%s: expected signing to be rejected in http mode, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,527 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/hash"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
+	yanzilibrary "github.com/chuxorg/chux-yanzi-cli/internal/library"
+)
+
+// Ledger audit statuses, from best to worst.
+const (
+	ledgerClean      = "clean"
+	ledgerTampered   = "tampered"
+	ledgerUnreadable = "unreadable"
+)
+
+// Ledger audit issue kinds.
+const (
+	issueMismatch    = "mismatch"
+	issueMissingLink = "missing_link"
+	issueOrphan      = "orphan"
+	// issueFork marks the head of an unmerged fork. Forks are reported but do not make
+	// the ledger tampered.
+	issueFork       = "fork"
+	issueSignature  = "signature"
+	issueUnreadable = "unreadable"
+)
+
+// ledgerIssue is one problem found by a ledger audit.
+type ledgerIssue struct {
+	Kind   string `json:"kind"`
+	Record string `json:"record"`
+	ID     string `json:"id,omitempty"`
+	Detail string `json:"detail"`
+}
+
+// ledgerAudit summarizes a verify --all run.
+type ledgerAudit struct {
+	Status      string `json:"status"`
+	Project     string `json:"project,omitempty"`
+	Intents     int    `json:"intents"`
+	Checkpoints int    `json:"checkpoints"`
+	Projects    int    `json:"projects"`
+	// ProjectEvents counts the entries of the project ledger.
+	ProjectEvents int `json:"project_events"`
+	Links         int `json:"links"`
+	// Signed counts the intents and checkpoints that carry a signature.
+	Signed        int           `json:"signed"`
+	Mismatches    int           `json:"mismatches"`
+	MissingLinks  int           `json:"missing_links"`
+	Orphans       int           `json:"orphans"`
+	Forks         int           `json:"forks"`
+	BadSignatures int           `json:"bad_signatures"`
+	Unreadable    int           `json:"unreadable"`
+	Issues        []ledgerIssue `json:"issues"`
+}
+
+// add records issues and counts them by kind.
+func (a *ledgerAudit) add(issues ...ledgerIssue) {
+	for _, issue := range issues {
+		switch issue.Kind {
+		case issueMismatch:
+			a.Mismatches++
+		case issueMissingLink:
+			a.MissingLinks++
+		case issueOrphan:
+			a.Orphans++
+		case issueFork:
+			a.Forks++
+		case issueSignature:
+			a.BadSignatures++
+		case issueUnreadable:
+			a.Unreadable++
+		}
+		a.Issues = append(a.Issues, issue)
+	}
+}
+
+// finish sorts the issues and sets the status: unreadable when any row could not be
+// read, tampered when any other issue but a fork was found, and clean otherwise.
+func (a *ledgerAudit) finish() {
+	sort.SliceStable(a.Issues, func(i, j int) bool {
+		left, right := a.Issues[i], a.Issues[j]
+		if left.Record != right.Record {
+			return left.Record < right.Record
+		}
+		if left.ID != right.ID {
+			return left.ID < right.ID
+		}
+		return left.Kind < right.Kind
+	})
+	switch {
+	case a.Unreadable > 0:
+		a.Status = ledgerUnreadable
+	case len(a.Issues) > a.Forks:
+		a.Status = ledgerTampered
+	default:
+		a.Status = ledgerClean
+	}
+}
+
+// exitError returns the error verify --all exits with, or nil for a clean ledger.
+func (a ledgerAudit) exitError() error {
+	code := ExitLedgerTampered
+	switch a.Status {
+	case ledgerClean:
+		return nil
+	case ledgerUnreadable:
+		code = ExitLedgerUnreadable
+	}
+	return &ExitError{Code: code, Err: fmt.Errorf("ledger %s: %d mismatches, %d missing links, %d orphans, %d bad signatures, %d unreadable",
+		a.Status, a.Mismatches, a.MissingLinks, a.Orphans, a.BadSignatures, a.Unreadable)}
+}
+
+// auditPool checks items on a fixed number of goroutines and collects the issues found.
+type auditPool[T any] struct {
+	jobs   chan T
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	issues []ledgerIssue
+}
+
+// newAuditPool starts workers goroutines running check on each submitted item.
+func newAuditPool[T any](workers int, check func(T) []ledgerIssue) *auditPool[T] {
+	if workers < 1 {
+		workers = 1
+	}
+	p := &auditPool[T]{jobs: make(chan T, workers)}
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for item := range p.jobs {
+				if issues := check(item); len(issues) > 0 {
+					p.mu.Lock()
+					p.issues = append(p.issues, issues...)
+					p.mu.Unlock()
+				}
+			}
+		}()
+	}
+	return p
+}
+
+// submit queues one item, blocking while every worker is busy.
+func (p *auditPool[T]) submit(item T) {
+	p.jobs <- item
+}
+
+// wait stops accepting items and returns the issues once every item is checked.
+func (p *auditPool[T]) wait() []ledgerIssue {
+	close(p.jobs)
+	p.wg.Wait()
+	return p.issues
+}
+
+// auditLedger recomputes every intent, checkpoint, and project event hash, optionally
+// limited to one project, and walks every prev_hash, previous checkpoint, artifact, and
+// chain head link, replaying the project ledger against the projects table. Intent and
+// checkpoint signatures are checked with sigs. Hashes are checked concurrently on
+// workers goroutines. Rows that cannot be decoded are reported as unreadable; an error
+// is returned only when a table cannot be read at all.
+func auditLedger(ctx context.Context, db *sql.DB, project string, workers int, sigs signatureCheck) (ledgerAudit, error) {
+	audit := ledgerAudit{Project: project, Issues: []ledgerIssue{}}
+	if err := auditIntents(ctx, db, project, workers, sigs, &audit); err != nil {
+		return audit, fmt.Errorf("read intents: %w", err)
+	}
+	if err := auditCheckpoints(ctx, db, project, workers, sigs, &audit); err != nil {
+		return audit, fmt.Errorf("read checkpoints: %w", err)
+	}
+	if err := auditProjects(ctx, db, project, &audit); err != nil {
+		return audit, fmt.Errorf("read projects: %w", err)
+	}
+	audit.finish()
+	return audit, nil
+}
+
+// intentNode is the part of an intent the link checks need. parents holds the
+// prev_hash followed by any merge parents.
+type intentNode struct {
+	id      string
+	parents []string
+	project string
+}
+
+func auditIntents(ctx context.Context, db *sql.DB, project string, workers int, sigs signatureCheck, audit *ledgerAudit) error {
+	query := `SELECT ` + intentColumns + ` FROM intents`
+	var args []any
+	if project != "" {
+		query += ` WHERE project = ?`
+		args = append(args, project)
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	pool := newAuditPool(workers, func(record model.IntentRecord) []ledgerIssue {
+		return append(checkIntentHash(ctx, db, record), sigs.intentIssues(record)...)
+	})
+	nodes := make(map[string]intentNode)
+	for rows.Next() {
+		record, err := scanIntent(rows)
+		if err != nil {
+			audit.add(ledgerIssue{Kind: issueUnreadable, Record: "intent", Detail: err.Error()})
+			continue
+		}
+		audit.Intents++
+		if record.Signature != "" {
+			audit.Signed++
+		}
+		nodes[record.Hash] = intentNode{id: record.ID, parents: yanzilibrary.IntentParents(record.PrevHash, record.Meta), project: intentProject(record)}
+		pool.submit(record)
+	}
+	audit.add(pool.wait()...)
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	for _, node := range nodes {
+		for i, parent := range node.parents {
+			audit.Links++
+			if _, ok := nodes[parent]; ok {
+				continue
+			}
+			// With --project the parent may belong to another project.
+			found, err := rowExists(ctx, db, `SELECT 1 FROM intents WHERE hash = ?`, parent)
+			if err != nil {
+				return err
+			}
+			if !found {
+				field := "prev_hash"
+				if i > 0 {
+					field = "merge parent"
+				}
+				audit.add(ledgerIssue{Kind: issueMissingLink, Record: "intent", ID: node.id, Detail: field + " " + parent + " not found"})
+			}
+		}
+	}
+	return auditChainHeads(ctx, db, project, nodes, audit)
+}
+
+// checkIntentHash recomputes an intent's hash and checks its attachment blobs.
+func checkIntentHash(ctx context.Context, db *sql.DB, record model.IntentRecord) []ledgerIssue {
+	computed, err := hash.HashIntent(record)
+	switch {
+	case err != nil:
+		return []ledgerIssue{{Kind: issueMismatch, Record: "intent", ID: record.ID, Detail: "compute hash: " + err.Error()}}
+	case computed != record.Hash:
+		return []ledgerIssue{{Kind: issueMismatch, Record: "intent", ID: record.ID, Detail: fmt.Sprintf("stored hash %s, computed %s", record.Hash, computed)}}
+	}
+	if err := verifyAttachmentBlobs(ctx, db, record.Attachments); err != nil {
+		return []ledgerIssue{{Kind: issueMismatch, Record: "intent", ID: record.ID, Detail: err.Error()}}
+	}
+	return nil
+}
+
+// auditChainHeads builds the chain graph of each project and checks it against the
+// recorded chain head. The head must resolve and must be a head of the graph. Every
+// other head is an unmerged fork, which is reported without tampering the ledger. A
+// chained intent whose parent is an intent of another project is an orphan; parents
+// that are not found at all are already missing links.
+func auditChainHeads(ctx context.Context, db *sql.DB, project string, nodes map[string]intentNode, audit *ledgerAudit) error {
+	query := `SELECT project, head_hash FROM chain_heads`
+	var args []any
+	if project != "" {
+		query += ` WHERE project = ?`
+		args = append(args, project)
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	projects := make(map[string]bool)
+	for rows.Next() {
+		var headProject, headHash string
+		if err := rows.Scan(&headProject, &headHash); err != nil {
+			audit.add(ledgerIssue{Kind: issueUnreadable, Record: "chain_head", Detail: err.Error()})
+			continue
+		}
+		projects[headProject] = true
+		if _, ok := nodes[headHash]; ok {
+			continue
+		}
+		found, err := rowExists(ctx, db, `SELECT 1 FROM intents WHERE hash = ?`, headHash)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		if !found {
+			audit.add(ledgerIssue{Kind: issueMissingLink, Record: "chain_head", ID: headProject, Detail: "head_hash " + headHash + " not found"})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+	for _, node := range nodes {
+		if node.project != "" && len(node.parents) > 0 {
+			projects[node.project] = true
+		}
+	}
+
+	names := make([]string, 0, len(projects))
+	for name := range projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		graph, err := yanzilibrary.BuildChainGraph(ctx, db, name)
+		if err != nil {
+			return err
+		}
+		if err := auditChainGraph(ctx, db, graph, nodes, audit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// auditChainGraph reports the orphans and unmerged forks of one project's chain graph,
+// and a recorded head that other intents descend from.
+func auditChainGraph(ctx context.Context, db *sql.DB, graph yanzilibrary.ChainGraph, nodes map[string]intentNode, audit *ledgerAudit) error {
+	inProject := make(map[string]bool, len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		inProject[node.Hash] = true
+	}
+	for _, node := range graph.Nodes {
+		for i, parent := range node.Parents {
+			if inProject[parent] {
+				continue
+			}
+			// A parent found nowhere is already reported as a missing link.
+			if _, ok := nodes[parent]; !ok {
+				found, err := rowExists(ctx, db, `SELECT 1 FROM intents WHERE hash = ?`, parent)
+				if err != nil {
+					return err
+				}
+				if !found {
+					continue
+				}
+			}
+			field := "prev_hash"
+			if i > 0 {
+				field = "merge parent"
+			}
+			audit.add(ledgerIssue{Kind: issueOrphan, Record: "intent", ID: node.ID, Detail: field + " " + parent + " is not an intent of project " + graph.Project})
+		}
+	}
+
+	tip := graph.RecordedHead
+	if !slices.Contains(graph.Heads, tip) {
+		if tip != "" && inProject[tip] {
+			audit.add(ledgerIssue{Kind: issueMismatch, Record: "chain_head", ID: graph.Project, Detail: "head_hash " + tip + " is not a head of the chain; newer intents descend from it"})
+		}
+		if len(graph.Heads) > 0 {
+			tip = graph.Heads[0]
+		}
+	}
+	for _, head := range graph.Heads {
+		if head == tip {
+			continue
+		}
+		id := head
+		if node, ok := nodes[head]; ok {
+			id = node.id
+		}
+		audit.add(ledgerIssue{Kind: issueFork, Record: "intent", ID: id, Detail: "head of an unmerged fork of project " + graph.Project + "; capture with --merge to reconcile"})
+	}
+	return nil
+}
+
+func auditCheckpoints(ctx context.Context, db *sql.DB, project string, workers int, sigs signatureCheck, audit *ledgerAudit) error {
+	checkpoints, unreadable, err := loadCheckpoints(ctx, db, project)
+	if err != nil {
+		return err
+	}
+	audit.add(unreadable...)
+
+	pool := newAuditPool(workers, func(checkpoint yanzilibrary.Checkpoint) []ledgerIssue {
+		return checkCheckpoint(ctx, db, checkpoint, sigs)
+	})
+	for _, checkpoint := range checkpoints {
+		audit.Checkpoints++
+		if checkpoint.Signature != "" {
+			audit.Signed++
+		}
+		if checkpoint.PreviousCheckpointID != "" {
+			audit.Links++
+		}
+		pool.submit(checkpoint)
+	}
+	audit.add(pool.wait()...)
+	return nil
+}
+
+// loadCheckpoints reads every checkpoint, or those of one project, newest first. Rows
+// that cannot be decoded are returned as unreadable issues.
+func loadCheckpoints(ctx context.Context, db *sql.DB, project string) ([]yanzilibrary.Checkpoint, []ledgerIssue, error) {
+	query := `SELECT ` + yanzilibrary.CheckpointColumns + ` FROM checkpoints`
+	var args []any
+	if project != "" {
+		query += ` WHERE project = ?`
+		args = append(args, project)
+	}
+	rows, err := db.QueryContext(ctx, query+` ORDER BY created_at DESC, hash DESC`, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var checkpoints []yanzilibrary.Checkpoint
+	var unreadable []ledgerIssue
+	for rows.Next() {
+		checkpoint, err := yanzilibrary.ScanCheckpoint(rows)
+		if err != nil {
+			unreadable = append(unreadable, ledgerIssue{Kind: issueUnreadable, Record: "checkpoint", Detail: err.Error()})
+			continue
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return checkpoints, unreadable, nil
+}
+
+// checkCheckpoint recomputes a checkpoint's hash, resolves its project, previous
+// checkpoint, and artifacts, and checks its signature. The problem kinds match the
+// audit issue kinds.
+func checkCheckpoint(ctx context.Context, db *sql.DB, checkpoint yanzilibrary.Checkpoint, sigs signatureCheck) []ledgerIssue {
+	result, err := yanzilibrary.VerifyCheckpoint(ctx, db, checkpoint)
+	if err != nil {
+		return []ledgerIssue{{Kind: issueUnreadable, Record: "checkpoint", ID: checkpoint.Hash, Detail: err.Error()}}
+	}
+	sigs.checkCheckpointSignature(checkpoint, &result)
+	issues := make([]ledgerIssue, 0, len(result.Problems))
+	for _, problem := range result.Problems {
+		issues = append(issues, ledgerIssue{Kind: problem.Kind, Record: "checkpoint", ID: checkpoint.Hash, Detail: problem.Detail})
+	}
+	return issues
+}
+
+// auditProjects replays the project ledger against the projects table. With a project,
+// only that project's events, under any of its names, and its row are counted and
+// reported; ledger links are still checked across every project.
+func auditProjects(ctx context.Context, db *sql.DB, project string, audit *ledgerAudit) error {
+	result, err := yanzilibrary.VerifyProjectLedger(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	names := map[string]bool{}
+	if project == "" {
+		audit.Projects += result.Projects
+		audit.ProjectEvents += result.Events
+		audit.Links += result.Links
+	} else {
+		events, err := yanzilibrary.ListProjectEvents(ctx, db)
+		if err != nil {
+			return err
+		}
+		names[project] = true
+		for _, event := range yanzilibrary.ProjectHistory(events, project) {
+			audit.ProjectEvents++
+			if event.PrevHash != "" {
+				audit.Links++
+			}
+			names[event.Project] = true
+			if event.PreviousName != "" {
+				names[event.PreviousName] = true
+			}
+		}
+		found, err := rowExists(ctx, db, `SELECT 1 FROM projects WHERE name = ?`, project)
+		if err != nil {
+			return err
+		}
+		if found {
+			audit.Projects++
+		}
+	}
+
+	for _, problem := range result.Problems {
+		if project != "" && !names[problem.Project] {
+			continue
+		}
+		issue := ledgerIssue{Kind: problem.Kind, Record: "project", ID: problem.Project, Detail: problem.Detail}
+		if problem.Hash != "" {
+			issue.Record = "project_event"
+			issue.ID = problem.Hash
+		}
+		audit.add(issue)
+	}
+	return nil
+}
+
+// intentProject returns the project an intent was captured in, if any.
+func intentProject(record model.IntentRecord) string {
+	var meta struct {
+		Project string `json:"project"`
+	}
+	if err := json.Unmarshal(record.Meta, &meta); err != nil {
+		return ""
+	}
+	return meta.Project
+}
+
+// rowExists reports whether query, selecting at most one row, matches.
+func rowExists(ctx context.Context, db *sql.DB, query string, args ...any) (bool, error) {
+	var one int
+	err := db.QueryRowContext(ctx, query, args...).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
+)
+
+func TestVerifyAllAuditsLedger(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	var intents []model.IntentRecord
+	for _, prompt := range []string{"first", "second", "third"} {
+		useOutputFormat(t, OutputJSON)
+		output, err := captureStdout(func() error {
+			return RunCapture([]string{"--author", "Ada", "--no-git", "--prompt", prompt, "--response", "ok"})
+		})
+		if err != nil {
+			t.Fatalf("RunCapture: %v", err)
+		}
+		var intent model.IntentRecord
+		if err := json.Unmarshal([]byte(output), &intent); err != nil {
+			t.Fatalf("decode capture: %v", err)
+		}
+		intents = append(intents, intent)
+	}
+	if _, err := captureStdout(func() error { return RunCheckpoint([]string{"create", "--summary", "snapshot", "--no-git"}) }); err != nil {
+		t.Fatalf("checkpoint create: %v", err)
+	}
+
+	output, err := captureStdout(func() error { return RunVerify([]string{"--all", "--workers", "2"}) })
+	if err != nil {
+		t.Fatalf("verify --all on a clean ledger: %v", err)
+	}
+	var audit ledgerAudit
+	if err := json.Unmarshal([]byte(output), &audit); err != nil {
+		t.Fatalf("decode audit %q: %v", output, err)
+	}
+	if audit.Status != ledgerClean || audit.Intents != 3 || audit.Checkpoints != 1 || audit.Projects != 1 || audit.Links != 2 || len(audit.Issues) != 0 {
+		t.Fatalf("unexpected clean audit: %+v", audit)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+	for _, stmt := range []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE intents SET response = 'edited' WHERE id = ?`, []any{intents[2].ID}},
+		{`DELETE FROM intents WHERE id = ?`, []any{intents[0].ID}},
+		{`UPDATE chain_heads SET head_hash = ? WHERE project = 'alpha'`, []any{intents[1].Hash}},
+	} {
+		if _, err := db.Exec(stmt.query, stmt.args...); err != nil {
+			t.Fatalf("tamper: %v", err)
+		}
+	}
+
+	audit, err = auditLedger(context.Background(), db, "alpha", 4, signatureCheck{})
+	if err != nil {
+		t.Fatalf("auditLedger: %v", err)
+	}
+	// Rolling the head back leaves it with a descendant, which is not a fork.
+	want := []ledgerIssue{
+		{Kind: issueMismatch, Record: "chain_head", ID: "alpha"},
+		{Kind: issueMissingLink, Record: "intent", ID: intents[1].ID},
+		{Kind: issueMismatch, Record: "intent", ID: intents[2].ID},
+	}
+	if intents[1].ID > intents[2].ID {
+		want = []ledgerIssue{want[0], want[2], want[1]}
+	}
+	if audit.Status != ledgerTampered || len(audit.Issues) != len(want) {
+		t.Fatalf("unexpected tampered audit: %+v", audit)
+	}
+	for i, issue := range audit.Issues {
+		if issue.Kind != want[i].Kind || issue.Record != want[i].Record || issue.ID != want[i].ID {
+			t.Fatalf("issue %d: got %+v, want %+v", i, issue, want[i])
+		}
+	}
+	if !strings.Contains(audit.Issues[0].Detail+audit.Issues[1].Detail+audit.Issues[2].Detail, intents[0].Hash) {
+		t.Fatalf("expected the missing prev_hash in the details, got %+v", audit.Issues)
+	}
+
+	var exitErr *ExitError
+	useOutputFormat(t, OutputText)
+	if _, err := captureStdout(func() error { return RunVerify([]string{"--all"}) }); !errors.As(err, &exitErr) || exitErr.Code != ExitLedgerTampered {
+		t.Fatalf("expected exit %d for a tampered ledger, got %v", ExitLedgerTampered, err)
+	}
+	if _, err := captureStdout(func() error { return RunVerify([]string{intents[2].ID}) }); !errors.As(err, &exitErr) || exitErr.Code != ExitLedgerTampered {
+		t.Fatalf("expected exit %d for an invalid intent, got %v", ExitLedgerTampered, err)
+	}
+
+	if _, err := db.Exec(`UPDATE intents SET attachments = 'not json' WHERE id = ?`, intents[1].ID); err != nil {
+		t.Fatalf("corrupt attachments: %v", err)
+	}
+	if _, err := captureStdout(func() error { return RunVerify([]string{"--all"}) }); !errors.As(err, &exitErr) || exitErr.Code != ExitLedgerUnreadable {
+		t.Fatalf("expected exit %d for an unreadable ledger, got %v", ExitLedgerUnreadable, err)
+	}
+}
+
+func TestVerifyAllReportsCrossProjectOrphans(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	createTestProject(t, "beta")
+	writeStateFile(t, home, "alpha")
+
+	first := runTestCapture(t, "first")
+	writeStateFile(t, home, "beta")
+	stray := runTestCapture(t, "stray", "--prev-hash", first.Hash)
+
+	useOutputFormat(t, OutputJSON)
+	var exitErr *ExitError
+	output, err := captureStdout(func() error { return RunVerify([]string{"--all", "--project", "beta"}) })
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitLedgerTampered {
+		t.Fatalf("expected exit %d for an orphan, got %v", ExitLedgerTampered, err)
+	}
+	var audit ledgerAudit
+	if err := json.Unmarshal([]byte(output), &audit); err != nil {
+		t.Fatalf("decode audit %q: %v", output, err)
+	}
+	if audit.Orphans != 1 || audit.MissingLinks != 0 || len(audit.Issues) != 1 || audit.Issues[0].ID != stray.ID {
+		t.Fatalf("expected the beta capture linked into alpha to be an orphan, got %+v", audit)
+	}
+}
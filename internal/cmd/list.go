@@ -2,67 +2,195 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/chuxorg/chux-yanzi-cli/internal/client"
 	"github.com/chuxorg/chux-yanzi-cli/internal/config"
 )
 
-// RunList lists intent records.
+// listAllPageSize is the page size --all fetches when --limit is not given.
+const listAllPageSize = 500
+
+// RunList lists intent records, one page at a time or, with --all, every page in turn.
 func RunList(args []string) error {
-	fs := flag.NewFlagSet("list", flag.ContinueOnError)
-	fs.SetOutput(os.Stderr)
+	fs := newFlagSet("list")
+	project := fs.String("project", "", "project filter")
 	author := fs.String("author", "", "author filter")
 	source := fs.String("source", "", "source filter")
-	limit := fs.Int("limit", 20, "max records to return")
+	since := fs.String("since", "", "only intents at or after this "+timeRangeUsage)
+	until := fs.String("until", "", "only intents at or before this "+timeRangeUsage)
+	limit := fs.Int("limit", 20, "max records to return (page size with --all)")
+	after := fs.String("after", "", "cursor from a previous page to continue after")
+	all := fs.Bool("all", false, "stream every page")
+	noAbbrev := fs.Bool("no-abbrev", false, "print full intent ids in text output")
+	format := fs.String("format", "", formatUsage)
 	metaFilters := metaPairs{}
 	fs.Var(&metaFilters, "meta", "meta filter key=value (repeatable; exact match; AND)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	pageSize := *limit
+	if *all {
+		pageSize = listAllPageSize
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name == "limit" {
+				pageSize = *limit
+			}
+		})
+	}
 
 	cfg, err := config.Load()
 	if err != nil {
 		return err
 	}
+	tmpl, err := loadFormatTemplate(cfg, *format)
+	if err != nil {
+		return err
+	}
 
-	var intents []client.IntentRecord
+	ctx := context.Background()
+	var abbrev idAbbreviator
+	var listPage func(after string) (client.ListResponse, error)
 	switch cfg.Mode {
 	case config.ModeHTTP:
+		if *since != "" || *until != "" {
+			return errors.New("--since and --until are only available in local mode")
+		}
 		cli := client.New(cfg.BaseURL)
-		resp, err := cli.ListIntents(context.Background(), *author, *source, *limit, map[string]string(metaFilters))
-		if err != nil {
-			return fmt.Errorf("http request to %s failed: %w", cfg.BaseURL, err)
+		filters := map[string]string(metaFilters)
+		if *project != "" {
+			if filters == nil {
+				filters = map[string]string{}
+			}
+			filters["project"] = *project
+		}
+		listPage = func(after string) (client.ListResponse, error) {
+			resp, err := cli.ListIntents(ctx, *author, *source, after, pageSize, filters)
+			if err != nil {
+				return client.ListResponse{}, fmt.Errorf("http request to %s failed: %w", cfg.BaseURL, err)
+			}
+			return resp, nil
 		}
-		intents = resp.Intents
 	case config.ModeLocal:
-		ctx := context.Background()
 		db, err := openLocalDB(cfg)
 		if err != nil {
 			return err
 		}
 		defer db.Close()
 
-		localIntents, err := listLocalIntents(ctx, db, *author, *source, *limit, map[string]string(metaFilters))
+		window, err := parseTimeRange(*since, *until, time.Now(), localCheckpointTime(ctx, db))
 		if err != nil {
 			return err
 		}
-		intents = localIntents
+		filters := intentFilters{
+			project: *project,
+			author:  *author,
+			source:  *source,
+			meta:    metaFilters,
+			window:  window,
+		}
+		if !*noAbbrev {
+			abbrev = idAbbreviator{ctx: ctx, db: db}
+		}
+		listPage = func(after string) (client.ListResponse, error) {
+			intents, next, err := listLocalIntents(ctx, db, filters, after, pageSize)
+			return client.ListResponse{Intents: intents, NextCursor: next}, err
+		}
 	default:
 		return fmt.Errorf("invalid mode: %s", cfg.Mode)
 	}
 
-	fmt.Println("ID\tCreated_At\tAuthor\tSource\tTitle")
-	for _, intent := range intents {
-		fmt.Printf("%s\t%s\t%s\t%s\t%s\n", intent.ID, intent.CreatedAt, intent.Author, intent.SourceType, intent.Title)
+	if !*all {
+		page, err := listPage(*after)
+		if err != nil {
+			return err
+		}
+		if tmpl != nil {
+			return printListTemplate(page, tmpl)
+		}
+		return printListPage(page, abbrev)
+	}
+
+	write := func(intent client.IntentRecord) error { return renderTemplate(tmpl, intent) }
+	w := newListWriter("intents", printListHeader, func(intent client.IntentRecord) {
+		printListRow(intent, abbrev)
+	})
+	if tmpl == nil {
+		write = w.write
+	}
+	cursor := *after
+	for {
+		page, err := listPage(cursor)
+		if err != nil {
+			return err
+		}
+		for _, intent := range page.Intents {
+			if err := write(intent); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" || len(page.Intents) == 0 {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if tmpl != nil {
+		return nil
+	}
+	return w.close()
+}
+
+// printListPage prints one page of intents. JSON and YAML output carry the next page's
+// cursor in next_cursor; text and NDJSON output report it on stderr.
+func printListPage(page client.ListResponse, abbrev idAbbreviator) error {
+	if outputFormat == OutputJSON || outputFormat == OutputYAML {
+		return printResult(page, nil)
 	}
+	if err := printList("intents", page.Intents, func() {
+		printListHeader()
+		for _, intent := range page.Intents {
+			printListRow(intent, abbrev)
+		}
+	}); err != nil {
+		return err
+	}
+	printNextCursor(page.NextCursor)
+	return nil
+}
 
+// printListTemplate prints one page of intents through a --format template, reporting
+// the next page's cursor on stderr.
+func printListTemplate(page client.ListResponse, tmpl *template.Template) error {
+	for _, intent := range page.Intents {
+		if err := renderTemplate(tmpl, intent); err != nil {
+			return err
+		}
+	}
+	printNextCursor(page.NextCursor)
 	return nil
 }
 
+// printNextCursor reports the cursor of the next page on stderr, if there is one.
+func printNextCursor(cursor string) {
+	if cursor != "" {
+		fmt.Fprintf(os.Stderr, "next cursor: %s (pass --after to continue)\n", cursor)
+	}
+}
+
+func printListHeader() {
+	fmt.Println("ID\tCreated_At\tAuthor\tSource\tTitle")
+}
+
+func printListRow(intent client.IntentRecord, abbrev idAbbreviator) {
+	fmt.Printf("%s\t%s\t%s\t%s\t%s\n", abbrev.short(intent.ID), intent.CreatedAt, intent.Author, intent.SourceType, intent.Title)
+}
+
 type metaPairs map[string]string
 
 func (m *metaPairs) String() string {
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/store"
+	yanzilibrary "github.com/chuxorg/chux-yanzi-cli/internal/library"
+)
+
+func TestListLocalIntentsFindsMatchesBeyondNewestRows(t *testing.T) {
+	db := openSearchTestDB(t)
+	seedIntentWithMeta(t, db, "old", "2025-01-01T00:00:00Z", "Ada", "import", "p", "r", map[string]any{"project": "alpha", "ticket": "T-1"})
+	for i := 0; i < 250; i++ {
+		seedIntentWithMeta(t, db, fmt.Sprintf("new-%03d", i), fmt.Sprintf("2026-01-01T00:%02d:%02dZ", i/60, i%60), "Bob", "cli", "p", "r", map[string]any{"project": "beta"})
+	}
+	ctx := context.Background()
+
+	cases := []struct {
+		name    string
+		filters intentFilters
+	}{
+		{"author", intentFilters{author: "Ada"}},
+		{"source", intentFilters{source: "import"}},
+		{"project", intentFilters{project: "alpha"}},
+		{"meta project", intentFilters{meta: map[string]string{"project": "alpha"}}},
+		{"meta", intentFilters{meta: map[string]string{"ticket": "T-1"}}},
+	}
+	for _, tc := range cases {
+		intents, _, err := listLocalIntents(ctx, db, tc.filters, "", 20)
+		if err != nil {
+			t.Fatalf("%s: list: %v", tc.name, err)
+		}
+		if len(intents) != 1 || intents[0].ID != "old" {
+			t.Fatalf("%s: expected the old intent, got %d intents", tc.name, len(intents))
+		}
+	}
+
+	intents, _, err := listLocalIntents(ctx, db, intentFilters{project: "alpha", meta: map[string]string{"project": "beta"}}, "", 20)
+	if err != nil || len(intents) != 0 {
+		t.Fatalf("expected conflicting project filters to match nothing, got %d (%v)", len(intents), err)
+	}
+	intents, _, err = listLocalIntents(ctx, db, intentFilters{author: "Bob"}, "", 5)
+	if err != nil || len(intents) != 5 || intents[0].ID != "new-249" {
+		t.Fatalf("expected the newest 5 of Bob's intents, got %d (%v)", len(intents), err)
+	}
+}
+
+func TestListLocalIntentsMetaFiltersMatchTypedSemantics(t *testing.T) {
+	db := openSearchTestDB(t)
+	metas := []map[string]any{
+		{"v": "2"},
+		{"v": 2},
+		{"v": 2.5},
+		{"v": true},
+		{"v": false},
+		{"v": nil},
+		{"v": []any{1, "a"}},
+		{"v": []any{"a", 1}},
+		{"v": map[string]any{"a": 1, "b": []any{true}}},
+		{"v": "[1,\"a\"]"},
+		{"other": 2},
+	}
+	var all []model.IntentRecord
+	for i, meta := range metas {
+		id := "i" + strconv.Itoa(i)
+		seedIntentWithMeta(t, db, id, fmt.Sprintf("2026-01-01T00:00:%02dZ", i), "Ada", "cli", "p", "r", meta)
+		record, err := dbGetIntent(context.Background(), db, id)
+		if err != nil {
+			t.Fatalf("load %s: %v", id, err)
+		}
+		all = append([]model.IntentRecord{record}, all...)
+	}
+
+	for _, want := range []string{"2", "2.0", "2.5", "true", "false", "null", `[1,"a"]`, `[1, "a"]`, `{"b":[true],"a":1.0}`, `"2"`, "nope"} {
+		filters := map[string]string{"v": want}
+		got, _, err := listLocalIntents(context.Background(), db, intentFilters{meta: filters}, "", 100)
+		if err != nil {
+			t.Fatalf("list v=%s: %v", want, err)
+		}
+		expected, err := store.FilterIntentsByMeta(all, filters)
+		if err != nil {
+			t.Fatalf("filter v=%s: %v", want, err)
+		}
+		if intentIDs(got) != intentIDs(expected) {
+			t.Fatalf("v=%s: SQL matched %q, want %q", want, intentIDs(got), intentIDs(expected))
+		}
+	}
+}
+
+func TestListLocalIntentsPagesWithCursor(t *testing.T) {
+	db := openSearchTestDB(t)
+	for i := 0; i < 7; i++ {
+		// Pairs share a timestamp, so pages must break ties by id.
+		meta := map[string]any{"project": "alpha", "n": i % 2}
+		seedIntentWithMeta(t, db, fmt.Sprintf("i%d", i), fmt.Sprintf("2026-01-01T00:00:%02dZ", i/2), "Ada", "cli", "p", "r", meta)
+	}
+	ctx := context.Background()
+
+	cases := []struct {
+		name    string
+		filters intentFilters
+		want    string
+	}{
+		{"all", intentFilters{}, "i6,i5,i4,i3,i2,i1,i0"},
+		{"indexed", intentFilters{project: "alpha"}, "i6,i5,i4,i3,i2,i1,i0"},
+		{"rechecked", intentFilters{meta: map[string]string{"n": "0"}}, "i6,i4,i2,i0"},
+	}
+	for _, tc := range cases {
+		var got []model.IntentRecord
+		after := ""
+		for pages := 0; ; pages++ {
+			if pages > 10 {
+				t.Fatalf("%s: pagination did not terminate", tc.name)
+			}
+			page, next, err := listLocalIntents(ctx, db, tc.filters, after, 2)
+			if err != nil {
+				t.Fatalf("%s: list: %v", tc.name, err)
+			}
+			got = append(got, page...)
+			if next == "" {
+				if len(page) == 0 {
+					t.Fatalf("%s: expected no empty final page", tc.name)
+				}
+				break
+			}
+			after = next
+		}
+		if intentIDs(got) != tc.want {
+			t.Fatalf("%s: paged %s, want %s", tc.name, intentIDs(got), tc.want)
+		}
+	}
+
+	if _, _, err := listLocalIntents(ctx, db, intentFilters{}, "not-a-cursor", 2); err == nil {
+		t.Fatal("expected error for invalid cursor")
+	}
+}
+
+func intentIDs(intents []model.IntentRecord) string {
+	ids := make([]string, 0, len(intents))
+	for _, intent := range intents {
+		ids = append(ids, intent.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+// BenchmarkListLocalIntents lists filtered pages from a database of
+// YANZI_BENCH_INTENTS intents (default 1,000,000), built once per run.
+func BenchmarkListLocalIntents(b *testing.B) {
+	count := 1_000_000
+	if value := os.Getenv("YANZI_BENCH_INTENTS"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			b.Fatalf("YANZI_BENCH_INTENTS: %v", err)
+		}
+		count = n
+	}
+	b.Setenv("YANZI_DB_PATH", filepath.Join(b.TempDir(), "bench.db"))
+	db, err := yanzilibrary.InitDB()
+	if err != nil {
+		b.Fatalf("init db: %v", err)
+	}
+	defer db.Close()
+	seedBenchmarkIntents(b, db, count)
+
+	ctx := context.Background()
+	benchmarks := []struct {
+		name    string
+		filters intentFilters
+	}{
+		{"newest", intentFilters{}},
+		{"project", intentFilters{project: "project-7"}},
+		{"author", intentFilters{author: "author-3"}},
+		{"source", intentFilters{source: "import"}},
+		{"meta", intentFilters{meta: map[string]string{"tokens": "1200"}}},
+		{"rare-author", intentFilters{author: "author-rare"}},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for b.Loop() {
+				if _, _, err := listLocalIntents(ctx, db, bm.filters, "", 20); err != nil {
+					b.Fatalf("list: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func seedBenchmarkIntents(b *testing.B, db *sql.DB, count int) {
+	b.Helper()
+	tx, err := db.Begin()
+	if err != nil {
+		b.Fatalf("begin: %v", err)
+	}
+	stmt, err := tx.Prepare(`INSERT INTO intents (id, created_at, author, source_type, title, prompt, response, meta, prev_hash, hash)
+		VALUES (?, ?, ?, ?, NULL, ?, ?, ?, NULL, ?)`)
+	if err != nil {
+		b.Fatalf("prepare: %v", err)
+	}
+	defer stmt.Close()
+	for i := 0; i < count; i++ {
+		author := "author-" + strconv.Itoa(i%10)
+		if i == 0 {
+			author = "author-rare"
+		}
+		source := "cli"
+		if i%4 == 0 {
+			source = "import"
+		}
+		meta := fmt.Sprintf(`{"project":"project-%d","tokens":%d}`, i%50, 1000+i%500)
+		createdAt := fmt.Sprintf("2025-01-01T00:00:00.%09dZ", i)
+		if _, err := stmt.Exec(fmt.Sprintf("bench-%08d", i), createdAt, author, source, "prompt", "response", meta, strconv.Itoa(i)); err != nil {
+			b.Fatalf("seed intent %d: %v", i, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		b.Fatalf("commit: %v", err)
+	}
+}
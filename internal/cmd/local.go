@@ -5,14 +5,19 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/chuxorg/chux-yanzi-cli/internal/client"
 	"github.com/chuxorg/chux-yanzi-cli/internal/config"
 	"github.com/chuxorg/chux-yanzi-cli/internal/core/hash"
 	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/signing"
 	"github.com/chuxorg/chux-yanzi-cli/internal/core/store"
 	yanzilibrary "github.com/chuxorg/chux-yanzi-cli/internal/library"
 )
@@ -45,6 +50,9 @@ func buildLocalIntent(req createIntentInput) (model.IntentRecord, error) {
 		PrevHash:   req.PrevHash,
 		Meta:       req.Meta,
 	}
+	for _, attachment := range req.Attachments {
+		record.Attachments = append(record.Attachments, attachment.reference())
+	}
 	sum, err := hash.HashIntent(record)
 	if err != nil {
 		return model.IntentRecord{}, err
@@ -61,7 +69,109 @@ func newIntentID() (string, error) {
 	return hex.EncodeToString(buf[:]), nil
 }
 
-func createLocalIntent(ctx context.Context, db *sql.DB, record model.IntentRecord) error {
+// sqlConn is the subset of *sql.DB and *sql.Tx used by the local intent helpers.
+type sqlConn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// storeLocalIntent builds and inserts an intent. When chainProject is set, the intent
+// links to that project's chain head unless input.PrevHash is already set, and it
+// becomes the new head in the same transaction.
+func storeLocalIntent(ctx context.Context, db *sql.DB, input createIntentInput, chainProject string, signer *signing.Key) (model.IntentRecord, error) {
+	records, err := storeLocalIntents(ctx, db, []createIntentInput{input}, chainProject, signer)
+	if err != nil {
+		return model.IntentRecord{}, err
+	}
+	return records[0], nil
+}
+
+// storeLocalIntents inserts intents in order within a single transaction. With a
+// chainProject each intent links to the one before it, starting from the project's
+// chain head, and the last intent becomes the new head. Each intent is signed with
+// signer when it is set.
+func storeLocalIntents(ctx context.Context, db *sql.DB, inputs []createIntentInput, chainProject string, signer *signing.Key) ([]model.IntentRecord, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var head string
+	if chainProject != "" {
+		head, err = loadChainHead(ctx, tx, chainProject)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	records := make([]model.IntentRecord, 0, len(inputs))
+	for _, input := range inputs {
+		if chainProject != "" && input.PrevHash == "" {
+			input.PrevHash = head
+		}
+		record, err := buildLocalIntent(input)
+		if err != nil {
+			return nil, err
+		}
+		if err := signIntent(signer, &record); err != nil {
+			return nil, err
+		}
+		for _, attachment := range input.Attachments {
+			if err := saveAttachmentBlob(ctx, tx, attachment, record.CreatedAt); err != nil {
+				return nil, err
+			}
+		}
+		if err := createLocalIntent(ctx, tx, record); err != nil {
+			return nil, err
+		}
+		head = record.Hash
+		records = append(records, record)
+	}
+
+	if chainProject != "" && len(records) > 0 {
+		last := records[len(records)-1]
+		if err := saveChainHead(ctx, tx, chainProject, last.Hash, last.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// loadChainHead returns the hash at the head of a project's chain, or "" when the chain is empty.
+func loadChainHead(ctx context.Context, conn sqlConn, project string) (string, error) {
+	var head string
+	err := conn.QueryRowContext(ctx, `SELECT head_hash FROM chain_heads WHERE project = ?`, project).Scan(&head)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read chain head: %w", err)
+	}
+	return head, nil
+}
+
+func saveChainHead(ctx context.Context, conn sqlConn, project, headHash, updatedAt string) error {
+	_, err := conn.ExecContext(
+		ctx,
+		`INSERT INTO chain_heads (project, head_hash, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(project) DO UPDATE SET head_hash = excluded.head_hash, updated_at = excluded.updated_at`,
+		project,
+		headHash,
+		updatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("write chain head: %w", err)
+	}
+	return nil
+}
+
+func createLocalIntent(ctx context.Context, db sqlConn, record model.IntentRecord) error {
 	var title any
 	if record.Title != "" {
 		title = record.Title
@@ -70,15 +180,27 @@ func createLocalIntent(ctx context.Context, db *sql.DB, record model.IntentRecor
 	if len(record.Meta) > 0 {
 		meta = string(record.Meta)
 	}
+	var attachments any
+	if len(record.Attachments) > 0 {
+		encoded, err := json.Marshal(record.Attachments)
+		if err != nil {
+			return fmt.Errorf("encode attachments: %w", err)
+		}
+		attachments = string(encoded)
+	}
 	var prevHash any
 	if record.PrevHash != "" {
 		prevHash = record.PrevHash
 	}
+	var keyID, signature any
+	if record.Signature != "" {
+		keyID, signature = record.KeyID, record.Signature
+	}
 
 	_, err := db.ExecContext(
 		ctx,
-		`INSERT INTO intents (id, created_at, author, source_type, title, prompt, response, meta, prev_hash, hash)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		`INSERT INTO intents (id, created_at, author, source_type, title, prompt, response, meta, attachments, prev_hash, hash, key_id, signature)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		record.ID,
 		record.CreatedAt,
 		record.Author,
@@ -87,14 +209,19 @@ func createLocalIntent(ctx context.Context, db *sql.DB, record model.IntentRecor
 		record.Prompt,
 		record.Response,
 		meta,
+		attachments,
 		prevHash,
 		record.Hash,
+		keyID,
+		signature,
 	)
 	return err
 }
 
-func verifyLocalIntent(ctx context.Context, db *sql.DB, id string) (verifyResult, error) {
-	record, err := dbGetIntent(ctx, db, id)
+// verifyLocalIntent recomputes an intent's hash, checks its attachment blobs, and checks
+// its signature with sigs.
+func verifyLocalIntent(ctx context.Context, db *sql.DB, id string, sigs signatureCheck) (verifyResult, error) {
+	record, err := dbResolveIntent(ctx, db, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return verifyResult{}, fmt.Errorf("intent not found: %s", id)
@@ -103,12 +230,22 @@ func verifyLocalIntent(ctx context.Context, db *sql.DB, id string) (verifyResult
 	}
 
 	computed, err := hash.HashIntent(record)
+	if err == nil && computed == record.Hash {
+		err = verifyAttachmentBlobs(ctx, db, record.Attachments)
+	}
+	status, signer, problem := sigs.check(signing.RecordIntent, record.Hash, record.KeyID, record.Signature)
+	if err == nil && computed == record.Hash && problem != "" {
+		err = errors.New(problem)
+	}
 	result := verifyResult{
-		ID:           record.ID,
-		StoredHash:   record.Hash,
-		ComputedHash: computed,
-		PrevHash:     record.PrevHash,
-		Valid:        err == nil && computed == record.Hash,
+		ID:              record.ID,
+		StoredHash:      record.Hash,
+		ComputedHash:    computed,
+		PrevHash:        record.PrevHash,
+		Valid:           err == nil && computed == record.Hash,
+		KeyID:           record.KeyID,
+		Signer:          signer,
+		SignatureStatus: string(status),
 	}
 	if err != nil {
 		msg := err.Error()
@@ -118,7 +255,7 @@ func verifyLocalIntent(ctx context.Context, db *sql.DB, id string) (verifyResult
 }
 
 func chainLocalIntent(ctx context.Context, db *sql.DB, id string) (chainResult, error) {
-	head, err := dbGetIntent(ctx, db, id)
+	head, err := dbResolveIntent(ctx, db, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return chainResult{}, fmt.Errorf("intent not found: %s", id)
@@ -154,49 +291,134 @@ func chainLocalIntent(ctx context.Context, db *sql.DB, id string) (chainResult,
 	}, nil
 }
 
-func listLocalIntents(ctx context.Context, db *sql.DB, author, source string, limit int, metaFilters map[string]string) ([]model.IntentRecord, error) {
-	fetchLimit := limit
-	if fetchLimit <= 0 {
-		fetchLimit = 20
+// intentFilters narrows an intent listing. Every field is optional.
+type intentFilters struct {
+	project string
+	author  string
+	source  string
+	meta    map[string]string
+	window  timeRange
+}
+
+// sqlConditions returns the WHERE conditions and arguments for the filters. exact is
+// false when rows must also be rechecked with matches. The project
+// meta key is always stored as a string, so it is matched through the indexed project
+// column rather than as a typed meta value.
+func (f intentFilters) sqlConditions() (conditions []string, args []any, exact bool) {
+	exact = true
+	project := f.project
+	if value, ok := f.meta["project"]; ok {
+		if project != "" && project != value {
+			return []string{"0"}, nil, true
+		}
+		project = value
 	}
-	if author != "" || source != "" || len(metaFilters) > 0 {
-		fetchLimit = fetchLimit * 5
-		if fetchLimit < 100 {
-			fetchLimit = 100
+	if project != "" {
+		conditions = append(conditions, "project = ?")
+		args = append(args, project)
+	}
+	if f.author != "" {
+		conditions = append(conditions, "author = ?")
+		args = append(args, f.author)
+	}
+	if f.source != "" {
+		conditions = append(conditions, "source_type = ?")
+		args = append(args, f.source)
+	}
+	keys := make([]string, 0, len(f.meta))
+	for key := range f.meta {
+		if key != "project" {
+			keys = append(keys, key)
 		}
 	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		condition, conditionArgs, conditionExact := store.MetaFilterCondition("intents.meta", key, f.meta[key])
+		conditions = append(conditions, condition)
+		args = append(args, conditionArgs...)
+		exact = exact && conditionExact
+	}
+	windowConditions, windowArgs := f.window.sqlConditions("created_at")
+	conditions = append(conditions, windowConditions...)
+	args = append(args, windowArgs...)
+	exact = exact && f.window.isZero()
+	return conditions, args, exact
+}
 
-	intents, err := listLocalIntentsFromDB(ctx, db, fetchLimit)
-	if err != nil {
-		return nil, err
+// matches rechecks a row selected by sqlConditions against the filters SQL can only narrow.
+func (f intentFilters) matches(record model.IntentRecord) (bool, error) {
+	if !f.window.containsTimestamp(record.CreatedAt) {
+		return false, nil
 	}
+	return store.MatchesMetaFilters(record.Meta, f.meta)
+}
 
-	filtered := make([]model.IntentRecord, 0, len(intents))
-	for _, intent := range intents {
-		if author != "" && intent.Author != author {
-			continue
-		}
-		if source != "" && intent.SourceType != source {
-			continue
+// listLocalIntents returns up to limit of the newest intents matching filters, starting
+// after the after cursor when one is given, and the cursor for the next page, empty when
+// no more intents match. Filters run in SQL, so results are exact for any history size;
+// when a meta filter or time window can only be narrowed in SQL, matching rows are
+// rechecked as they stream until the page is full.
+func listLocalIntents(ctx context.Context, db *sql.DB, filters intentFilters, after string, limit int) ([]model.IntentRecord, string, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	conditions, args, exact := filters.sqlConditions()
+	if after != "" {
+		cursor, err := store.DecodeCursor(after)
+		if err != nil {
+			return nil, "", fmt.Errorf("--after: %w", err)
 		}
-		filtered = append(filtered, intent)
+		condition, cursorArgs := cursor.Condition("created_at", "id")
+		conditions = append(conditions, condition)
+		args = append(args, cursorArgs...)
+	}
+	query := `SELECT ` + intentColumns + ` FROM intents`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+	query += ` ORDER BY created_at DESC, id DESC`
+	if exact {
+		// One extra row tells whether another page follows.
+		query += ` LIMIT ?`
+		args = append(args, limit+1)
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
 	}
+	defer rows.Close()
 
-	if len(metaFilters) > 0 {
-		filtered, err = store.FilterIntentsByMeta(filtered, metaFilters)
+	intents := make([]model.IntentRecord, 0)
+	next := ""
+	for rows.Next() {
+		record, err := scanIntent(rows)
 		if err != nil {
-			return nil, err
+			return nil, "", err
+		}
+		if !exact {
+			match, err := filters.matches(record)
+			if err != nil {
+				return nil, "", err
+			}
+			if !match {
+				continue
+			}
+		}
+		if len(intents) == limit {
+			next = store.CursorAfter(intents[len(intents)-1]).Encode()
+			break
 		}
+		intents = append(intents, record)
 	}
-
-	if limit > 0 && len(filtered) > limit {
-		filtered = filtered[:limit]
+	if err := rows.Err(); err != nil {
+		return nil, "", err
 	}
-	return filtered, nil
+	return intents, next, nil
 }
 
 func getLocalIntent(ctx context.Context, db *sql.DB, id string) (model.IntentRecord, error) {
-	record, err := dbGetIntent(ctx, db, id)
+	record, err := dbResolveIntent(ctx, db, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return model.IntentRecord{}, fmt.Errorf("intent not found for ID %s", id)
@@ -206,12 +428,22 @@ func getLocalIntent(ctx context.Context, db *sql.DB, id string) (model.IntentRec
 	return record, nil
 }
 
-func dbGetIntent(ctx context.Context, db *sql.DB, id string) (model.IntentRecord, error) {
+// intentColumns is the column list read by scanIntent.
+const intentColumns = `id, created_at, author, source_type, title, prompt, response, meta, attachments, prev_hash, hash, key_id, signature`
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanIntent reads one intent selected with intentColumns.
+func scanIntent(row rowScanner) (model.IntentRecord, error) {
 	var record model.IntentRecord
 	var title sql.NullString
 	var meta sql.NullString
+	var attachments sql.NullString
 	var prevHash sql.NullString
-	row := db.QueryRowContext(ctx, `SELECT id, created_at, author, source_type, title, prompt, response, meta, prev_hash, hash FROM intents WHERE id = ?`, id)
+	var keyID, signature sql.NullString
 	if err := row.Scan(
 		&record.ID,
 		&record.CreatedAt,
@@ -221,8 +453,11 @@ func dbGetIntent(ctx context.Context, db *sql.DB, id string) (model.IntentRecord
 		&record.Prompt,
 		&record.Response,
 		&meta,
+		&attachments,
 		&prevHash,
 		&record.Hash,
+		&keyID,
+		&signature,
 	); err != nil {
 		return model.IntentRecord{}, err
 	}
@@ -232,42 +467,25 @@ func dbGetIntent(ctx context.Context, db *sql.DB, id string) (model.IntentRecord
 	if meta.Valid && meta.String != "" {
 		record.Meta = []byte(meta.String)
 	}
+	if attachments.Valid && attachments.String != "" {
+		if err := json.Unmarshal([]byte(attachments.String), &record.Attachments); err != nil {
+			return model.IntentRecord{}, fmt.Errorf("decode attachments for %s: %w", record.ID, err)
+		}
+	}
 	if prevHash.Valid {
 		record.PrevHash = prevHash.String
 	}
+	record.KeyID = keyID.String
+	record.Signature = signature.String
 	return record, nil
 }
 
+func dbGetIntent(ctx context.Context, db *sql.DB, id string) (model.IntentRecord, error) {
+	return scanIntent(db.QueryRowContext(ctx, `SELECT `+intentColumns+` FROM intents WHERE id = ?`, id))
+}
+
 func dbGetIntentByHash(ctx context.Context, db *sql.DB, intentHash string) (model.IntentRecord, error) {
-	var record model.IntentRecord
-	var title sql.NullString
-	var meta sql.NullString
-	var prevHash sql.NullString
-	row := db.QueryRowContext(ctx, `SELECT id, created_at, author, source_type, title, prompt, response, meta, prev_hash, hash FROM intents WHERE hash = ?`, intentHash)
-	if err := row.Scan(
-		&record.ID,
-		&record.CreatedAt,
-		&record.Author,
-		&record.SourceType,
-		&title,
-		&record.Prompt,
-		&record.Response,
-		&meta,
-		&prevHash,
-		&record.Hash,
-	); err != nil {
-		return model.IntentRecord{}, err
-	}
-	if title.Valid {
-		record.Title = title.String
-	}
-	if meta.Valid && meta.String != "" {
-		record.Meta = []byte(meta.String)
-	}
-	if prevHash.Valid {
-		record.PrevHash = prevHash.String
-	}
-	return record, nil
+	return scanIntent(db.QueryRowContext(ctx, `SELECT `+intentColumns+` FROM intents WHERE hash = ?`, intentHash))
 }
 
 func listLocalIntentsFromDB(ctx context.Context, db *sql.DB, limit int) ([]model.IntentRecord, error) {
@@ -275,7 +493,7 @@ func listLocalIntentsFromDB(ctx context.Context, db *sql.DB, limit int) ([]model
 		limit = 100
 	}
 
-	rows, err := db.QueryContext(ctx, `SELECT id, created_at, author, source_type, title, prompt, response, meta, prev_hash, hash FROM intents ORDER BY created_at DESC LIMIT ?`, limit)
+	rows, err := db.QueryContext(ctx, `SELECT `+intentColumns+` FROM intents ORDER BY created_at DESC LIMIT ?`, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -283,33 +501,10 @@ func listLocalIntentsFromDB(ctx context.Context, db *sql.DB, limit int) ([]model
 
 	var intents []model.IntentRecord
 	for rows.Next() {
-		var record model.IntentRecord
-		var title sql.NullString
-		var meta sql.NullString
-		var prevHash sql.NullString
-		if err := rows.Scan(
-			&record.ID,
-			&record.CreatedAt,
-			&record.Author,
-			&record.SourceType,
-			&title,
-			&record.Prompt,
-			&record.Response,
-			&meta,
-			&prevHash,
-			&record.Hash,
-		); err != nil {
+		record, err := scanIntent(rows)
+		if err != nil {
 			return nil, err
 		}
-		if title.Valid {
-			record.Title = title.String
-		}
-		if meta.Valid && meta.String != "" {
-			record.Meta = []byte(meta.String)
-		}
-		if prevHash.Valid {
-			record.PrevHash = prevHash.String
-		}
 		intents = append(intents, record)
 	}
 	if err := rows.Err(); err != nil {
@@ -318,28 +513,23 @@ func listLocalIntentsFromDB(ctx context.Context, db *sql.DB, limit int) ([]model
 	return intents, nil
 }
 
+// createIntentInput is the pending intent before id, timestamp, and hash are assigned.
+// Its JSON form is the capture object accepted by "capture --json".
 type createIntentInput struct {
-	Author     string
-	SourceType string
-	Title      string
-	Prompt     string
-	Response   string
-	Meta       []byte
-	PrevHash   string
+	Author     string          `json:"author,omitempty"`
+	SourceType string          `json:"source_type,omitempty"`
+	Title      string          `json:"title,omitempty"`
+	Prompt     string          `json:"prompt"`
+	Response   string          `json:"response"`
+	Meta       json.RawMessage `json:"meta,omitempty"`
+	// Attachments carry their content until the intent is stored, so queued
+	// captures keep their artifacts.
+	Attachments []attachmentInput `json:"attachments,omitempty"`
+	PrevHash    string            `json:"prev_hash,omitempty"`
 }
 
-type verifyResult struct {
-	ID           string
-	Valid        bool
-	StoredHash   string
-	ComputedHash string
-	PrevHash     string
-	Error        *string
-}
+// verifyResult and chainResult share the HTTP response types so both modes
+// print the same structured output.
+type verifyResult = client.VerifyResponse
 
-type chainResult struct {
-	HeadID       string
-	Length       int
-	Intents      []model.IntentRecord
-	MissingLinks []string
-}
+type chainResult = client.ChainResponse
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	yanzilibrary "github.com/chuxorg/chux-yanzi-cli/internal/library"
+)
+
+// metaCommandSource is the source type recorded for meta-command events.
+const metaCommandSource = "meta-command"
+
+// RunMeta executes @yanzi meta-commands against the active project.
+func RunMeta(args []string, cliVersion string) error {
+	fs := newFlagSet("meta")
+	author := fs.String("author", "", "event author (default: active role)")
+	file := fs.String("file", "", "scan agent text from a file, or - for stdin")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	commands, err := metaCommandsFromArgs(fs.Args(), *file)
+	if err != nil {
+		return err
+	}
+	if len(commands) == 0 {
+		if *file != "" {
+			return nil
+		}
+		return metaUsageError()
+	}
+
+	project, err := loadActiveProject()
+	if err != nil {
+		return err
+	}
+	if project == "" {
+		return errors.New("no active project set")
+	}
+
+	var results []metaResult
+	for _, command := range commands {
+		eventAuthor := strings.TrimSpace(*author)
+		if eventAuthor == "" {
+			role, err := loadActiveRole()
+			if err != nil {
+				return err
+			}
+			eventAuthor = role
+		}
+		result, err := runMetaCommand(project, eventAuthor, cliVersion, command)
+		if err != nil {
+			// Report the commands that already ran before the failure.
+			if StructuredOutput() && len(results) > 0 {
+				_ = printList("commands", results, func() {})
+			}
+			return err
+		}
+		if !StructuredOutput() {
+			fmt.Printf("Yanzi: %s\n", result.Message)
+		}
+		results = append(results, result)
+	}
+	return printList("commands", results, func() {})
+}
+
+// metaResult is one executed meta-command and its acknowledgement. Value is the
+// checkpoint id, export path, or role it produced.
+type metaResult struct {
+	Command  yanzilibrary.MetaCommandName `json:"command"`
+	Argument string                       `json:"argument,omitempty"`
+	Value    string                       `json:"value,omitempty"`
+	Message  string                       `json:"message"`
+}
+
+// metaCommandsFromArgs parses commands from --file text or positional arguments.
+// Positional arguments may be a full "@yanzi ..." line or a bare command such as
+// `checkpoint "Summary"`, where the shell has already removed the quotes.
+func metaCommandsFromArgs(args []string, file string) ([]yanzilibrary.MetaCommand, error) {
+	if file != "" {
+		if len(args) != 0 {
+			return nil, errors.New("--file cannot be combined with a command argument")
+		}
+		var data []byte
+		var err error
+		if file == stdinPath {
+			data, err = readStdin()
+		} else {
+			data, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read meta-command text: %w", err)
+		}
+		return yanzilibrary.ScanMetaCommands(string(data))
+	}
+
+	if len(args) == 0 {
+		return nil, nil
+	}
+	if strings.HasPrefix(args[0], yanzilibrary.MetaCommandPrefix) {
+		command, err := yanzilibrary.ParseMetaCommand(strings.Join(args, " "))
+		if err != nil {
+			return nil, err
+		}
+		return []yanzilibrary.MetaCommand{command}, nil
+	}
+	command, err := yanzilibrary.NewMetaCommand(args[0], strings.Join(args[1:], " "))
+	if err != nil {
+		return nil, err
+	}
+	return []yanzilibrary.MetaCommand{command}, nil
+}
+
+// runMetaCommand executes a single command, records it as an intent event, and returns
+// the acknowledgement required by the agent bootstrap rules.
+func runMetaCommand(project, author, cliVersion string, command yanzilibrary.MetaCommand) (metaResult, error) {
+	result := metaResult{Command: command.Name, Argument: command.Argument}
+	cfg, err := config.Load()
+	if err != nil {
+		return metaResult{}, err
+	}
+
+	var value, ack string
+	switch command.Name {
+	case yanzilibrary.MetaCommandPause:
+		// Pause and resume record their own events, only when the state changes.
+		paused, err := pauseCapture(cfg, project, author, "")
+		if err != nil {
+			return metaResult{}, err
+		}
+		result.Message = pauseAck(project, paused)
+		return result, nil
+	case yanzilibrary.MetaCommandResume:
+		resumed, stored, err := resumeCapture(cfg, project, author)
+		if err != nil {
+			return metaResult{}, err
+		}
+		result.Message = resumeAck(project, resumed, stored)
+		return result, nil
+	case yanzilibrary.MetaCommandRole:
+		if err := saveActiveRole(command.Argument); err != nil {
+			return metaResult{}, err
+		}
+		value = command.Argument
+		ack = fmt.Sprintf("role set to %s.", command.Argument)
+	case yanzilibrary.MetaCommandCheckpoint:
+		if cfg.Mode != config.ModeLocal {
+			if cfg.Signing.Key != "" {
+				return metaResult{}, errSigningLocalOnly
+			}
+			return metaResult{}, errors.New("checkpoint commands are not available in http mode")
+		}
+		redactor, err := newRedactor(cfg)
+		if err != nil {
+			return metaResult{}, err
+		}
+		pending, err := redactCheckpoint(redactor, pendingCheckpoint{Project: project, Summary: command.Argument})
+		if err != nil {
+			return metaResult{}, err
+		}
+		signer, err := loadSigner(cfg)
+		if err != nil {
+			return metaResult{}, err
+		}
+		db, err := openLocalDB(cfg)
+		if err != nil {
+			return metaResult{}, err
+		}
+		checkpoint, err := checkpointStore(db, signer).CreateCheckpointWithMeta(context.Background(), project, pending.Summary, []string{}, pending.Meta)
+		_ = db.Close()
+		if err != nil {
+			return metaResult{}, err
+		}
+		value = checkpoint.Hash
+		ack = fmt.Sprintf("checkpoint %s created.", checkpoint.Hash)
+	case yanzilibrary.MetaCommandExport:
+		path, err := exportMarkdown(project, cliVersion)
+		if err != nil {
+			return metaResult{}, err
+		}
+		value = path
+		ack = fmt.Sprintf("exported %s.", path)
+	default:
+		return metaResult{}, fmt.Errorf("unsupported meta-command: %s", command.Name)
+	}
+
+	if err := recordMetaEvent(cfg, project, author, command, value); err != nil {
+		return metaResult{}, err
+	}
+	result.Value = value
+	result.Message = ack
+	return result, nil
+}
+
+// recordMetaEvent stores an executed meta-command as an intent event for the project log.
+func recordMetaEvent(cfg config.Config, project, author string, command yanzilibrary.MetaCommand, value string) error {
+	meta, err := attachProjectMeta(nil, project)
+	if err != nil {
+		return err
+	}
+	redactor, err := newRedactor(cfg)
+	if err != nil {
+		return err
+	}
+	input, _, err := redactCapture(redactor, createIntentInput{
+		Author:     author,
+		SourceType: metaCommandSource,
+		Prompt:     command.String(),
+		Response:   value,
+		Meta:       meta,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = storeIntent(cfg, input, project)
+	return err
+}
+
+func metaUsageError() error {
+	return errors.New(`usage: yanzi meta [--author <name>] <pause|resume|export|checkpoint "Summary"|role <Name>> | --file <path|->`)
+}
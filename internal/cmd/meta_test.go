@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+)
+
+func TestRunMetaCheckpointRecordsEvent(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	output, err := captureStdout(func() error {
+		return RunMeta([]string{"checkpoint", "Layout complete"}, "v1.0.0")
+	})
+	if err != nil {
+		t.Fatalf("RunMeta: %v", err)
+	}
+	if !strings.HasPrefix(output, "Yanzi: checkpoint ") {
+		t.Fatalf("expected acknowledgement, got %q", output)
+	}
+
+	events := loadMetaEvents(t)
+	if len(events) != 1 {
+		t.Fatalf("expected one event, got %v", events)
+	}
+	if events[0] != `Engineer @yanzi checkpoint "Layout complete"` {
+		t.Fatalf("unexpected event: %q", events[0])
+	}
+}
+
+func TestRunMetaRoleUpdatesEventAuthor(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	output, err := captureStdout(func() error {
+		if err := RunMeta([]string{"@yanzi", "role", "Reviewer"}, "v1.0.0"); err != nil {
+			return err
+		}
+		return RunMeta([]string{"pause"}, "v1.0.0")
+	})
+	if err != nil {
+		t.Fatalf("RunMeta: %v", err)
+	}
+	if !strings.Contains(output, "Yanzi: role set to Reviewer.") {
+		t.Fatalf("expected role acknowledgement, got %q", output)
+	}
+
+	role, err := loadActiveRole()
+	if err != nil {
+		t.Fatalf("loadActiveRole: %v", err)
+	}
+	if role != "Reviewer" {
+		t.Fatalf("expected persisted role, got %q", role)
+	}
+	events := loadMetaEvents(t)
+	if len(events) != 2 || events[1] != "Reviewer @yanzi pause" {
+		t.Fatalf("unexpected events: %v", events)
+	}
+}
+
+func TestRunMetaFileRejectsInvalidLinesBeforeRunning(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	path := filepath.Join(home, "agent.txt")
+	text := "Working on it.\n@yanzi checkpoint \"Auth done\"\n@yanzi deploy\n"
+	if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+		t.Fatalf("write agent text: %v", err)
+	}
+
+	err := RunMeta([]string{"--file", path}, "v1.0.0")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if events := loadMetaEvents(t); len(events) != 0 {
+		t.Fatalf("expected no events recorded, got %v", events)
+	}
+}
+
+// loadMetaEvents returns "<author> <command>" for each recorded meta-command event.
+func loadMetaEvents(t *testing.T) []string {
+	t.Helper()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(context.Background(), `SELECT author, prompt FROM intents WHERE source_type = ? ORDER BY created_at ASC, rowid ASC`, metaCommandSource)
+	if err != nil {
+		t.Fatalf("query events: %v", err)
+	}
+	defer rows.Close()
+
+	var events []string
+	for rows.Next() {
+		var author, prompt string
+		if err := rows.Scan(&author, &prompt); err != nil {
+			t.Fatalf("scan event: %v", err)
+		}
+		events = append(events, author+" "+prompt)
+	}
+	return events
+}
@@ -10,13 +10,18 @@ import (
 
 // RunMode shows or sets the runtime mode.
 func RunMode(args []string) error {
+	args, err := parseArgs("mode", args)
+	if err != nil {
+		return err
+	}
 	if len(args) == 0 {
 		cfg, err := config.Load()
 		if err != nil {
 			return err
 		}
-		fmt.Printf("Current mode: %s\n", formatMode(cfg))
-		return nil
+		return printResult(newModeStatus(cfg), func() {
+			fmt.Printf("Current mode: %s\n", formatMode(cfg))
+		})
 	}
 
 	if len(args) > 1 {
@@ -50,8 +55,9 @@ func setModeLocal() error {
 	if err := writeConfig(content); err != nil {
 		return err
 	}
-	fmt.Println("Mode set to local.")
-	return nil
+	return printResult(modeStatus{Mode: config.ModeLocal}, func() {
+		fmt.Println("Mode set to local.")
+	})
 }
 
 func setModeHTTP() error {
@@ -68,9 +74,10 @@ func setModeHTTP() error {
 	if err := writeConfig(content); err != nil {
 		return err
 	}
-	fmt.Printf("Mode set to http (%s).\n", baseURL)
-	fmt.Println("Run 'libraryd' to start the server.")
-	return nil
+	return printResult(modeStatus{Mode: config.ModeHTTP, BaseURL: baseURL}, func() {
+		fmt.Printf("Mode set to http (%s).\n", baseURL)
+		fmt.Println("Run 'libraryd' to start the server.")
+	})
 }
 
 func writeConfig(content string) error {
@@ -88,6 +95,19 @@ func modeUsageError() error {
 	return errors.New("usage: yanzi mode [local|http]")
 }
 
+// modeStatus is the runtime mode in structured output; BaseURL is set in http mode.
+type modeStatus struct {
+	Mode    config.Mode `json:"mode"`
+	BaseURL string      `json:"base_url,omitempty"`
+}
+
+func newModeStatus(cfg config.Config) modeStatus {
+	if cfg.Mode == config.ModeHTTP {
+		return modeStatus{Mode: cfg.Mode, BaseURL: cfg.BaseURL}
+	}
+	return modeStatus{Mode: config.ModeLocal}
+}
+
 func formatMode(cfg config.Config) string {
 	switch cfg.Mode {
 	case config.ModeHTTP:
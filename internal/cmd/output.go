@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OutputFormat selects how commands print their results.
+type OutputFormat string
+
+const (
+	// OutputText is the human-readable default.
+	OutputText OutputFormat = "text"
+	// OutputJSON prints one indented JSON document per command.
+	OutputJSON OutputFormat = "json"
+	// OutputNDJSON prints one compact JSON object per line; collections print one item per line.
+	OutputNDJSON OutputFormat = "ndjson"
+	// OutputYAML prints the JSON document as YAML, keeping its field names and order.
+	OutputYAML OutputFormat = "yaml"
+)
+
+// outputFormat is the format selected by the global --output flag.
+var outputFormat = OutputText
+
+// ParseOutputFormat validates an --output value.
+func ParseOutputFormat(value string) (OutputFormat, error) {
+	switch format := OutputFormat(value); format {
+	case OutputText, OutputJSON, OutputNDJSON, OutputYAML:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid --output %q (expected text, json, ndjson, or yaml)", value)
+	}
+}
+
+// SetOutputFormat selects the output format for every command.
+func SetOutputFormat(format OutputFormat) {
+	outputFormat = format
+}
+
+// outputFlag is the --output flag accepted among every command's own flags.
+type outputFlag struct{}
+
+func (outputFlag) String() string { return string(outputFormat) }
+
+func (outputFlag) Set(value string) error {
+	format, err := ParseOutputFormat(value)
+	if err != nil {
+		return err
+	}
+	outputFormat = format
+	return nil
+}
+
+// newFlagSet returns the flag set for a command: parse errors go to stderr, and
+// --output may be given among the command's flags as well as before its name.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Var(outputFlag{}, "output", "output format: text, json, ndjson, or yaml")
+	return fs
+}
+
+// parseArgs parses a command that takes only positional args, so --output may still
+// be given before them, and returns the positional args.
+func parseArgs(name string, args []string) ([]string, error) {
+	fs := newFlagSet(name)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return fs.Args(), nil
+}
+
+// ParseArgs exposes parseArgs to the main package.
+func ParseArgs(name string, args []string) ([]string, error) {
+	return parseArgs(name, args)
+}
+
+// StructuredOutput reports whether a machine-readable format is selected.
+func StructuredOutput() bool {
+	return outputFormat != OutputText
+}
+
+// printResult prints a single result: text output runs text, structured output encodes v.
+func printResult(v any, text func()) error {
+	if !StructuredOutput() {
+		text()
+		return nil
+	}
+	return encodeOutput(os.Stdout, v)
+}
+
+// PrintResult prints a single result for callers outside this package.
+func PrintResult(v any, text func()) error {
+	return printResult(v, text)
+}
+
+// printList prints a collection: text output runs text, ndjson prints one item per line,
+// and json and yaml print an object holding the items under key.
+func printList[T any](key string, items []T, text func()) error {
+	if items == nil {
+		items = []T{}
+	}
+	switch outputFormat {
+	case OutputText:
+		text()
+		return nil
+	case OutputNDJSON:
+		for _, item := range items {
+			if err := encodeOutput(os.Stdout, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return encodeOutput(os.Stdout, map[string]any{key: items})
+	}
+}
+
+// listWriter prints a collection one item at a time, producing the same document as
+// printList without holding every item in memory.
+type listWriter[T any] struct {
+	key     string
+	header  func()
+	text    func(T)
+	started bool
+	count   int
+}
+
+// newListWriter returns a writer for the collection under key; text output prints
+// header once and then each item with text.
+func newListWriter[T any](key string, header func(), text func(T)) *listWriter[T] {
+	return &listWriter[T]{key: key, header: header, text: text}
+}
+
+// start prints the opening of the document.
+func (l *listWriter[T]) start() error {
+	if l.started {
+		return nil
+	}
+	l.started = true
+	var err error
+	switch outputFormat {
+	case OutputText:
+		l.header()
+	case OutputJSON:
+		_, err = fmt.Fprintf(os.Stdout, "{\n  %q: [", l.key)
+	case OutputYAML:
+		_, err = fmt.Fprintf(os.Stdout, "%s:", l.key)
+	}
+	return err
+}
+
+// write prints one item.
+func (l *listWriter[T]) write(item T) error {
+	if err := l.start(); err != nil {
+		return err
+	}
+	defer func() { l.count++ }()
+	switch outputFormat {
+	case OutputText:
+		l.text(item)
+		return nil
+	case OutputNDJSON:
+		return encodeOutput(os.Stdout, item)
+	case OutputJSON:
+		data, err := marshalJSON(item, true)
+		if err != nil {
+			return fmt.Errorf("encode %s output: %w", outputFormat, err)
+		}
+		separator := "\n"
+		if l.count > 0 {
+			separator = ",\n"
+		}
+		_, err = fmt.Fprint(os.Stdout, separator+indentLines(data, "    ", "    "))
+		return err
+	default:
+		data, err := marshalYAML(item)
+		if err != nil {
+			return fmt.Errorf("encode %s output: %w", outputFormat, err)
+		}
+		_, err = fmt.Fprint(os.Stdout, "\n"+indentLines(data, "  - ", "    "))
+		return err
+	}
+}
+
+// close prints the end of the document.
+func (l *listWriter[T]) close() error {
+	if err := l.start(); err != nil {
+		return err
+	}
+	var err error
+	switch outputFormat {
+	case OutputJSON:
+		if l.count == 0 {
+			_, err = fmt.Fprint(os.Stdout, "]\n}\n")
+		} else {
+			_, err = fmt.Fprint(os.Stdout, "\n  ]\n}\n")
+		}
+	case OutputYAML:
+		if l.count == 0 {
+			_, err = fmt.Fprint(os.Stdout, " []\n")
+		} else {
+			_, err = fmt.Fprint(os.Stdout, "\n")
+		}
+	}
+	return err
+}
+
+// indentLines prefixes the first line of data with first and later lines with rest,
+// dropping the trailing newline.
+func indentLines(data []byte, first, rest string) string {
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	for i, line := range lines {
+		if i == 0 {
+			lines[i] = first + line
+		} else {
+			lines[i] = rest + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// errorOutput is the structured form of a command failure.
+type errorOutput struct {
+	Error    string `json:"error"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// WriteError prints a command failure to w: the message in text output, otherwise a
+// single-line JSON object, which is also valid YAML.
+func WriteError(w io.Writer, err error, exitCode int) {
+	if !StructuredOutput() {
+		fmt.Fprintln(w, err)
+		return
+	}
+	data, marshalErr := marshalJSON(errorOutput{Error: err.Error(), ExitCode: exitCode}, false)
+	if marshalErr != nil {
+		fmt.Fprintln(w, err)
+		return
+	}
+	fmt.Fprint(w, string(data))
+}
+
+// encodeOutput writes v to w in the selected structured format.
+func encodeOutput(w io.Writer, v any) error {
+	var data []byte
+	var err error
+	switch outputFormat {
+	case OutputJSON:
+		data, err = marshalJSON(v, true)
+	case OutputNDJSON:
+		data, err = marshalJSON(v, false)
+	case OutputYAML:
+		data, err = marshalYAML(v)
+	default:
+		return fmt.Errorf("invalid output format: %s", outputFormat)
+	}
+	if err != nil {
+		return fmt.Errorf("encode %s output: %w", outputFormat, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		_, err = w.Write([]byte("\n"))
+	}
+	return err
+}
+
+// marshalJSON encodes v without HTML escaping, indented or on a single line.
+func marshalJSON(v any, indent bool) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if indent {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// marshalYAML renders v's JSON form as block-style YAML, so YAML output uses the same
+// field names, order, and omissions as JSON output.
+func marshalYAML(v any) ([]byte, error) {
+	data, err := marshalJSON(v, false)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, errors.New("empty document")
+	}
+	blockStyle(&doc)
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc.Content[0]); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// blockStyle clears the flow and quoting styles left by decoding JSON, letting the
+// encoder choose block collections and quote only scalars that need it.
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
+	"gopkg.in/yaml.v3"
+)
+
+func useOutputFormat(t *testing.T, format OutputFormat) {
+	t.Helper()
+	SetOutputFormat(format)
+	t.Cleanup(func() { SetOutputFormat(OutputText) })
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	for _, value := range []string{"text", "json", "ndjson", "yaml"} {
+		if format, err := ParseOutputFormat(value); err != nil || string(format) != value {
+			t.Fatalf("ParseOutputFormat(%q) = %q, %v", value, format, err)
+		}
+	}
+	if _, err := ParseOutputFormat("xml"); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
+
+func TestStructuredOutputAcrossCommands(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	useOutputFormat(t, OutputJSON)
+	output, err := captureStdout(func() error {
+		return RunCapture([]string{"--author", "Ada", "--no-git", "--prompt", "<hello>", "--response", "world"})
+	})
+	if err != nil {
+		t.Fatalf("RunCapture: %v", err)
+	}
+	var intent model.IntentRecord
+	if err := json.Unmarshal([]byte(output), &intent); err != nil {
+		t.Fatalf("decode capture output %q: %v", output, err)
+	}
+	if intent.ID == "" || intent.Prompt != "<hello>" || !strings.Contains(output, `"<hello>"`) {
+		t.Fatalf("expected the stored intent without HTML escaping, got %s", output)
+	}
+
+	output, err = captureStdout(func() error { return RunVerify([]string{intent.ID}) })
+	if err != nil {
+		t.Fatalf("RunVerify: %v", err)
+	}
+	var verified verifyResult
+	if err := json.Unmarshal([]byte(output), &verified); err != nil || !verified.Valid || verified.StoredHash != intent.Hash {
+		t.Fatalf("expected valid verify response, got %s (%v)", output, err)
+	}
+
+	output, err = captureStdout(func() error { return RunList(nil) })
+	if err != nil {
+		t.Fatalf("RunList: %v", err)
+	}
+	var listed struct {
+		Intents []model.IntentRecord `json:"intents"`
+	}
+	if err := json.Unmarshal([]byte(output), &listed); err != nil || len(listed.Intents) != 1 || listed.Intents[0].Hash != intent.Hash {
+		t.Fatalf("expected one listed intent, got %s (%v)", output, err)
+	}
+
+	output, err = captureStdout(func() error { return RunCheckpoint([]string{"list"}) })
+	if err != nil {
+		t.Fatalf("checkpoint list: %v", err)
+	}
+	if strings.TrimSpace(output) != "{\n  \"checkpoints\": []\n}" {
+		t.Fatalf("expected an empty checkpoint array, got %s", output)
+	}
+
+	useOutputFormat(t, OutputNDJSON)
+	if _, err := captureStdout(func() error {
+		return RunCapture([]string{"--author", "Bob", "--no-git", "--prompt", "second", "--response", "ok"})
+	}); err != nil {
+		t.Fatalf("RunCapture: %v", err)
+	}
+	output, err = captureStdout(func() error { return RunList(nil) })
+	if err != nil {
+		t.Fatalf("RunList: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per intent, got %q", output)
+	}
+	for _, line := range lines {
+		var record model.IntentRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil || record.ID == "" {
+			t.Fatalf("expected an intent per line, got %q (%v)", line, err)
+		}
+	}
+
+	useOutputFormat(t, OutputYAML)
+	output, err = captureStdout(func() error { return RunShow([]string{intent.ID}) })
+	if err != nil {
+		t.Fatalf("RunShow: %v", err)
+	}
+	var shown map[string]any
+	if err := yaml.Unmarshal([]byte(output), &shown); err != nil {
+		t.Fatalf("decode yaml %q: %v", output, err)
+	}
+	if shown["id"] != intent.ID || shown["source_type"] != "cli" || !strings.HasPrefix(output, "id: ") {
+		t.Fatalf("expected intent fields in JSON order, got %s", output)
+	}
+}
+
+func TestOutputFlagAmongCommandFlags(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+	useOutputFormat(t, OutputText)
+
+	// As another flag's value --output is plain text, not the output format.
+	output, err := captureStdout(func() error {
+		return RunCapture([]string{"--author", "Ada", "--no-git", "--title", "--output", "--prompt", "x", "--response", "ok"})
+	})
+	if err != nil {
+		t.Fatalf("RunCapture: %v", err)
+	}
+	intent := loadCapturedIntent(t, output)
+	if intent.Title != "--output" || intent.Prompt != "x" {
+		t.Fatalf("expected the title --output, got %+v", intent)
+	}
+
+	output, err = captureStdout(func() error { return RunList([]string{"--author", "Ada", "--output", "json", "--limit", "5"}) })
+	if err != nil {
+		t.Fatalf("RunList: %v", err)
+	}
+	var listed struct {
+		Intents []model.IntentRecord `json:"intents"`
+	}
+	if err := json.Unmarshal([]byte(output), &listed); err != nil || len(listed.Intents) != 1 {
+		t.Fatalf("expected --output json among list flags, got %s (%v)", output, err)
+	}
+
+	useOutputFormat(t, OutputText)
+	output, err = captureStdout(func() error { return RunProject([]string{"current", "--output=yaml"}) })
+	if err != nil {
+		t.Fatalf("project current: %v", err)
+	}
+	if strings.TrimSpace(output) != "active_project: alpha" {
+		t.Fatalf("expected yaml project status, got %q", output)
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	var buf bytes.Buffer
+	WriteError(&buf, errors.New("boom"), 1)
+	if buf.String() != "boom\n" {
+		t.Fatalf("expected plain text error, got %q", buf.String())
+	}
+
+	useOutputFormat(t, OutputYAML)
+	buf.Reset()
+	WriteError(&buf, &ExitError{Code: ExitCaptureQueued, Err: errors.New("capture queued")}, ExitCaptureQueued)
+	if buf.String() != `{"error":"capture queued","exit_code":4}`+"\n" {
+		t.Fatalf("expected one-line JSON error, got %q", buf.String())
+	}
+}
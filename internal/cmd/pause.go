@@ -0,0 +1,369 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	yanzilibrary "github.com/chuxorg/chux-yanzi-cli/internal/library"
+)
+
+// RunPause pauses capture for the active project.
+func RunPause(args []string) error {
+	fs := newFlagSet("pause")
+	reason := fs.String("reason", "", "optional reason recorded with the pause")
+	author := fs.String("author", "", "event author (default: active role)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errors.New(`usage: yanzi pause [--reason "..."]`)
+	}
+
+	project, cfg, eventAuthor, err := loadPauseContext(*author)
+	if err != nil {
+		return err
+	}
+	paused, err := pauseCapture(cfg, project, eventAuthor, *reason)
+	if err != nil {
+		return err
+	}
+	status := captureStatus{Project: project, Paused: true, Changed: paused, Message: pauseAck(project, paused)}
+	return printResult(status, func() {
+		fmt.Printf("Yanzi: %s\n", status.Message)
+	})
+}
+
+// RunResume resumes capture for the active project and stores any queued captures.
+func RunResume(args []string) error {
+	fs := newFlagSet("resume")
+	author := fs.String("author", "", "event author (default: active role)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errors.New("usage: yanzi resume")
+	}
+
+	project, cfg, eventAuthor, err := loadPauseContext(*author)
+	if err != nil {
+		return err
+	}
+	resumed, stored, err := resumeCapture(cfg, project, eventAuthor)
+	if err != nil {
+		return err
+	}
+	status := captureStatus{Project: project, Changed: resumed, Stored: stored, Message: resumeAck(project, resumed, stored)}
+	return printResult(status, func() {
+		fmt.Printf("Yanzi: %s\n", status.Message)
+	})
+}
+
+// captureStatus reports a pause or resume: whether capture is now paused, whether the
+// command changed that, and how many queued captures a resume stored.
+type captureStatus struct {
+	Project string `json:"project"`
+	Paused  bool   `json:"paused"`
+	Changed bool   `json:"changed"`
+	Stored  int    `json:"stored"`
+	Message string `json:"message"`
+}
+
+// loadPauseContext resolves the active project, config, and event author for pause commands.
+func loadPauseContext(author string) (string, config.Config, string, error) {
+	project, err := loadActiveProject()
+	if err != nil {
+		return "", config.Config{}, "", err
+	}
+	if project == "" {
+		return "", config.Config{}, "", errors.New("no active project set")
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return "", config.Config{}, "", err
+	}
+	author = strings.TrimSpace(author)
+	if author == "" {
+		author, err = loadActiveRole()
+		if err != nil {
+			return "", config.Config{}, "", err
+		}
+	}
+	return project, cfg, author, nil
+}
+
+// pauseCapture records a pause event and then persists the paused flag for a project.
+// It reports false, without recording anything, when capture is already paused.
+func pauseCapture(cfg config.Config, project, author, reason string) (bool, error) {
+	state, err := loadProjectState()
+	if err != nil {
+		return false, err
+	}
+	if _, ok := state.Paused[project]; ok {
+		return false, nil
+	}
+
+	reason = strings.TrimSpace(reason)
+	value := "paused"
+	if reason != "" {
+		value = reason
+	}
+	// Record the event first so a failed write leaves capture running and a retry
+	// records it.
+	if err := recordMetaEvent(cfg, project, author, yanzilibrary.MetaCommand{Name: yanzilibrary.MetaCommandPause}, value); err != nil {
+		return false, err
+	}
+
+	if state.Paused == nil {
+		state.Paused = map[string]pauseState{}
+	}
+	state.Paused[project] = pauseState{
+		Reason:   reason,
+		PausedAt: time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if err := saveProjectState(state); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// resumeCapture records a resume event, clears the paused flag, and stores queued captures.
+// It reports false when capture was not paused.
+func resumeCapture(cfg config.Config, project, author string) (bool, int, error) {
+	state, err := loadProjectState()
+	if err != nil {
+		return false, 0, err
+	}
+	if _, ok := state.Paused[project]; !ok {
+		return false, 0, nil
+	}
+
+	if err := recordMetaEvent(cfg, project, author, yanzilibrary.MetaCommand{Name: yanzilibrary.MetaCommandResume}, "resumed"); err != nil {
+		return false, 0, err
+	}
+	delete(state.Paused, project)
+	if err := saveProjectState(state); err != nil {
+		return false, 0, err
+	}
+
+	stored, err := drainCaptureQueue(cfg, project)
+	return true, stored, err
+}
+
+func pauseAck(project string, paused bool) string {
+	if !paused {
+		return fmt.Sprintf("capture already paused for project %s.", project)
+	}
+	return fmt.Sprintf("capture paused for project %s.", project)
+}
+
+func resumeAck(project string, resumed bool, stored int) string {
+	if !resumed {
+		return fmt.Sprintf("capture is not paused for project %s.", project)
+	}
+	if stored > 0 {
+		return fmt.Sprintf("capture resumed for project %s (%d queued captures stored).", project, stored)
+	}
+	return fmt.Sprintf("capture resumed for project %s.", project)
+}
+
+// describePause renders a pause record for status output.
+func describePause(pause pauseState) string {
+	text := "paused since " + pause.PausedAt
+	if pause.Reason != "" {
+		text += " (" + pause.Reason + ")"
+	}
+	return text
+}
+
+// handlePausedCapture skips or queues a capture for a paused project and returns
+// an ExitError whose code tells the caller which happened. Queued captures keep
+// whether they should be chained when stored.
+func handlePausedCapture(mode config.PausedCapture, project string, pause pauseState, input createIntentInput, chain bool) error {
+	switch mode {
+	case config.PausedCaptureQueue:
+		queued, err := queueCapture(project, input, chain)
+		if err != nil {
+			return err
+		}
+		return &ExitError{
+			Code: ExitCaptureQueued,
+			Err:  fmt.Errorf("capture queued: project %s is %s (%d queued)", project, describePause(pause), queued),
+		}
+	case config.PausedCaptureSkip:
+		return &ExitError{
+			Code: ExitCaptureSkipped,
+			Err:  fmt.Errorf("capture skipped: project %s is %s", project, describePause(pause)),
+		}
+	default:
+		return fmt.Errorf("invalid paused capture mode: %s (expected skip or queue)", mode)
+	}
+}
+
+// queuedCapture is one line of the paused-capture queue file.
+type queuedCapture struct {
+	Project  string            `json:"project"`
+	QueuedAt string            `json:"queued_at"`
+	Capture  createIntentInput `json:"capture"`
+	NoChain  bool              `json:"no_chain,omitempty"`
+}
+
+// queueCapture appends a capture to the queue and returns how many are queued for the project.
+func queueCapture(project string, input createIntentInput, chain bool) (int, error) {
+	entries, err := readCaptureQueue()
+	if err != nil {
+		return 0, err
+	}
+	entries = append(entries, queuedCapture{
+		Project:  project,
+		QueuedAt: time.Now().UTC().Format(time.RFC3339Nano),
+		Capture:  input,
+		NoChain:  !chain,
+	})
+	if err := writeCaptureQueue(entries); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, entry := range entries {
+		if entry.Project == project {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// drainCaptureQueue passes the queued captures for a project through the pre_capture
+// hooks and stores them in queue order. Entries that were not stored, including any
+// after a failure or a hook veto, stay in the queue.
+func drainCaptureQueue(cfg config.Config, project string) (int, error) {
+	entries, err := readCaptureQueue()
+	if err != nil {
+		return 0, err
+	}
+	redactor, err := newRedactor(cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	remaining := make([]queuedCapture, 0, len(entries))
+	stored := 0
+	var storeErr error
+	for _, entry := range entries {
+		if entry.Project != project || storeErr != nil {
+			remaining = append(remaining, entry)
+			continue
+		}
+		input := entry.Capture
+		queuedAt, _ := json.Marshal(map[string]string{"queued_at": entry.QueuedAt})
+		input.Meta, storeErr = mergeMeta(input.Meta, queuedAt)
+		if storeErr == nil {
+			input, storeErr = runCapturePreHooks(cfg, redactor, input)
+		}
+		if storeErr == nil {
+			chainProject := project
+			if entry.NoChain {
+				chainProject = ""
+			}
+			_, storeErr = storeIntent(cfg, input, chainProject)
+		}
+		if storeErr != nil {
+			remaining = append(remaining, entry)
+			continue
+		}
+		stored++
+	}
+
+	if stored > 0 {
+		if err := writeCaptureQueue(remaining); err != nil {
+			return stored, err
+		}
+	}
+	if storeErr != nil {
+		return stored, fmt.Errorf("store queued capture: %w", storeErr)
+	}
+	return stored, nil
+}
+
+func captureQueuePath() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "capture_queue.ndjson"), nil
+}
+
+func readCaptureQueue() ([]queuedCapture, error) {
+	path, err := captureQueuePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read capture queue: %w", err)
+	}
+
+	var entries []queuedCapture
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var entry queuedCapture
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("invalid capture queue entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read capture queue: %w", err)
+	}
+	return entries, nil
+}
+
+func writeCaptureQueue(entries []queuedCapture) error {
+	path, err := captureQueuePath()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove capture queue: %w", err)
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("encode capture queue entry: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create state dir: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("write capture queue: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("persist capture queue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+)
+
+func TestPausedCaptureIsSkipped(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	output, err := captureStdout(func() error {
+		return RunPause([]string{"--reason", "spike"})
+	})
+	if err != nil {
+		t.Fatalf("RunPause: %v", err)
+	}
+	if strings.TrimSpace(output) != "Yanzi: capture paused for project alpha." {
+		t.Fatalf("unexpected acknowledgement: %q", output)
+	}
+
+	err = RunCapture([]string{"--author", "Ada", "--prompt", "p", "--response", "r"})
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCaptureSkipped {
+		t.Fatalf("expected skipped exit error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "(spike)") {
+		t.Fatalf("expected pause reason in error, got %v", err)
+	}
+	if count := countIntentsBySource(t, "cli"); count != 0 {
+		t.Fatalf("expected no captures stored, got %d", count)
+	}
+
+	current, err := captureStdout(func() error {
+		return RunProject([]string{"current"})
+	})
+	if err != nil {
+		t.Fatalf("RunProject current: %v", err)
+	}
+	if !strings.Contains(current, "Capture: paused since ") || !strings.Contains(current, "(spike)") {
+		t.Fatalf("expected paused state in project current, got %q", current)
+	}
+}
+
+func TestPausedCaptureQueuesUntilResume(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	if _, err := captureStdout(func() error { return RunPause(nil) }); err != nil {
+		t.Fatalf("RunPause: %v", err)
+	}
+
+	err := RunCapture([]string{"--author", "Ada", "--prompt", "p", "--response", "r", "--when-paused", "queue"})
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCaptureQueued {
+		t.Fatalf("expected queued exit error, got %v", err)
+	}
+	if count := countIntentsBySource(t, "cli"); count != 0 {
+		t.Fatalf("expected capture to wait in the queue, got %d stored", count)
+	}
+
+	output, err := captureStdout(func() error {
+		return RunResume(nil)
+	})
+	if err != nil {
+		t.Fatalf("RunResume: %v", err)
+	}
+	if !strings.Contains(output, "capture resumed for project alpha (1 queued captures stored).") {
+		t.Fatalf("unexpected acknowledgement: %q", output)
+	}
+	if count := countIntentsBySource(t, "cli"); count != 1 {
+		t.Fatalf("expected queued capture stored, got %d", count)
+	}
+
+	events := loadMetaEvents(t)
+	if len(events) != 2 || events[0] != "Engineer @yanzi pause" || events[1] != "Engineer @yanzi resume" {
+		t.Fatalf("unexpected events: %v", events)
+	}
+}
+
+func TestMetaPauseIsIdempotent(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	output, err := captureStdout(func() error {
+		if err := RunMeta([]string{"pause"}, "v1.0.0"); err != nil {
+			return err
+		}
+		return RunMeta([]string{"pause"}, "v1.0.0")
+	})
+	if err != nil {
+		t.Fatalf("RunMeta: %v", err)
+	}
+	if !strings.Contains(output, "capture already paused for project alpha.") {
+		t.Fatalf("unexpected acknowledgement: %q", output)
+	}
+	if events := loadMetaEvents(t); len(events) != 1 {
+		t.Fatalf("expected a single pause event, got %v", events)
+	}
+}
+
+func TestPauseStaysOffWhenEventFails(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	unreachable := config.Config{Mode: config.ModeHTTP, BaseURL: "http://127.0.0.1:1"}
+	if _, err := pauseCapture(unreachable, "alpha", "Engineer", "spike"); err == nil {
+		t.Fatal("expected the pause event write to fail")
+	}
+	state, err := loadProjectState()
+	if err != nil {
+		t.Fatalf("loadProjectState: %v", err)
+	}
+	if _, ok := state.Paused["alpha"]; ok {
+		t.Fatal("expected capture to stay running when the pause event is not recorded")
+	}
+
+	if _, err := captureStdout(func() error { return RunPause(nil) }); err != nil {
+		t.Fatalf("RunPause retry: %v", err)
+	}
+	if events := loadMetaEvents(t); len(events) != 1 {
+		t.Fatalf("expected the retry to record the pause event, got %v", events)
+	}
+}
+
+func countIntentsBySource(t *testing.T, source string) int {
+	t.Helper()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	var count int
+	if err := db.QueryRowContext(context.Background(), `SELECT COUNT(1) FROM intents WHERE source_type = ?`, source).Scan(&count); err != nil {
+		t.Fatalf("count intents: %v", err)
+	}
+	return count
+}
@@ -2,13 +2,9 @@ package cmd
 
 import (
 	"context"
-	"crypto/sha256"
 	"database/sql"
-	"encoding/hex"
 	"errors"
-	"flag"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
@@ -31,12 +27,26 @@ func RunProject(args []string) error {
 		return runProjectUse(args[1:])
 	case "current":
 		return runProjectCurrent(args[1:])
+	case "describe":
+		return runProjectDescribe(args[1:])
+	case "archive":
+		return runProjectArchive(args[1:], yanzilibrary.ProjectArchived)
+	case "unarchive":
+		return runProjectArchive(args[1:], yanzilibrary.ProjectUnarchived)
+	case "rename":
+		return runProjectRename(args[1:])
+	case "log":
+		return runProjectLog(args[1:])
 	default:
 		return projectUsageError()
 	}
 }
 
 func runProjectCreate(args []string) error {
+	args, err := parseArgs("project create", args)
+	if err != nil {
+		return err
+	}
 	if len(args) != 1 {
 		return errors.New("usage: yanzi project create <name>")
 	}
@@ -63,8 +73,9 @@ func runProjectCreate(args []string) error {
 			return err
 		}
 
-		fmt.Printf("Project created: %s\n", project.Name)
-		return nil
+		return printResult(project, func() {
+			fmt.Printf("Project created: %s\n", project.Name)
+		})
 	case config.ModeHTTP:
 		return errors.New("project commands are not available in http mode")
 	default:
@@ -73,13 +84,14 @@ func runProjectCreate(args []string) error {
 }
 
 func runProjectList(args []string) error {
-	fs := flag.NewFlagSet("project list", flag.ContinueOnError)
-	fs.SetOutput(os.Stderr)
+	fs := newFlagSet("project list")
+	var all bool
+	fs.BoolVar(&all, "all", false, "include archived projects")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 	if len(fs.Args()) != 0 {
-		return errors.New("usage: yanzi project list")
+		return errors.New("usage: yanzi project list [--all]")
 	}
 
 	cfg, err := config.Load()
@@ -99,12 +111,26 @@ func runProjectList(args []string) error {
 		if err != nil {
 			return err
 		}
-
-		fmt.Println("Name\tCreatedAt\tDescription")
-		for _, project := range projects {
-			fmt.Printf("%s\t%s\t%s\n", project.Name, project.CreatedAt.Format(time.RFC3339Nano), project.Description)
+		if !all {
+			active := projects[:0]
+			for _, project := range projects {
+				if !project.Archived {
+					active = append(active, project)
+				}
+			}
+			projects = active
 		}
-		return nil
+
+		return printList("projects", projects, func() {
+			fmt.Println("Name\tCreatedAt\tDescription")
+			for _, project := range projects {
+				fmt.Printf("%s\t%s\t%s", project.Name, project.CreatedAt.Format(time.RFC3339Nano), project.Description)
+				if project.Archived {
+					fmt.Print("\t(archived)")
+				}
+				fmt.Println()
+			}
+		})
 	case config.ModeHTTP:
 		return errors.New("project commands are not available in http mode")
 	default:
@@ -113,6 +139,10 @@ func runProjectList(args []string) error {
 }
 
 func runProjectUse(args []string) error {
+	args, err := parseArgs("project use", args)
+	if err != nil {
+		return err
+	}
 	if len(args) != 1 {
 		return errors.New("usage: yanzi project use <name>")
 	}
@@ -139,6 +169,9 @@ func runProjectUse(args []string) error {
 		found := false
 		for _, project := range projects {
 			if project.Name == name {
+				if project.Archived {
+					return fmt.Errorf("project is archived: %s (run yanzi project unarchive %s first)", name, name)
+				}
 				found = true
 				break
 			}
@@ -151,8 +184,9 @@ func runProjectUse(args []string) error {
 			return err
 		}
 
-		fmt.Printf("Active project set to %s.\n", name)
-		return nil
+		return printResult(projectStatus{ActiveProject: name}, func() {
+			fmt.Printf("Active project set to %s.\n", name)
+		})
 	case config.ModeHTTP:
 		return errors.New("project commands are not available in http mode")
 	default:
@@ -161,6 +195,10 @@ func runProjectUse(args []string) error {
 }
 
 func runProjectCurrent(args []string) error {
+	args, err := parseArgs("project current", args)
+	if err != nil {
+		return err
+	}
 	if len(args) != 0 {
 		return errors.New("usage: yanzi project current")
 	}
@@ -169,17 +207,180 @@ func runProjectCurrent(args []string) error {
 	if err != nil {
 		return err
 	}
-	if active == "" {
-		fmt.Println("No active project")
-		return nil
+	status := projectStatus{ActiveProject: active}
+	if active != "" {
+		pause, paused, err := loadPauseState(active)
+		if err != nil {
+			return err
+		}
+		if paused {
+			status.Pause = &pause
+		}
 	}
+	return printResult(status, func() {
+		if status.ActiveProject == "" {
+			fmt.Println("No active project")
+			return
+		}
+		fmt.Printf("Active project: %s\n", status.ActiveProject)
+		if status.Pause != nil {
+			fmt.Printf("Capture: %s\n", describePause(*status.Pause))
+		}
+	})
+}
 
-	fmt.Printf("Active project: %s\n", active)
-	return nil
+// projectStatus is the active project and, while capture is paused, its pause record.
+type projectStatus struct {
+	ActiveProject string      `json:"active_project"`
+	Pause         *pauseState `json:"pause,omitempty"`
+}
+
+func runProjectDescribe(args []string) error {
+	args, err := parseArgs("project describe", args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 || strings.TrimSpace(args[0]) == "" {
+		return errors.New("usage: yanzi project describe <name> <description>")
+	}
+	event, err := appendProjectEventLocal(yanzilibrary.ProjectEvent{
+		Action:      yanzilibrary.ProjectDescribed,
+		Project:     args[0],
+		Description: strings.TrimSpace(args[1]),
+	})
+	if err != nil {
+		return err
+	}
+	return printResult(event, func() {
+		fmt.Printf("Project description updated: %s\n", event.Project)
+	})
+}
+
+// runProjectArchive archives or restores a project; action is ProjectArchived or
+// ProjectUnarchived.
+func runProjectArchive(args []string, action string) error {
+	args, err := parseArgs("project "+action, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 || strings.TrimSpace(args[0]) == "" {
+		return fmt.Errorf("usage: yanzi project %s <name>", action)
+	}
+	event, err := appendProjectEventLocal(yanzilibrary.ProjectEvent{Action: action, Project: args[0]})
+	if err != nil {
+		return err
+	}
+	return printResult(event, func() {
+		if event.Archived {
+			fmt.Printf("Project archived: %s\n", event.Project)
+			return
+		}
+		fmt.Printf("Project restored: %s\n", event.Project)
+	})
+}
+
+func runProjectRename(args []string) error {
+	args, err := parseArgs("project rename", args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 || strings.TrimSpace(args[0]) == "" || strings.TrimSpace(args[1]) == "" {
+		return errors.New("usage: yanzi project rename <name> <new-name>")
+	}
+	event, err := appendProjectEventLocal(yanzilibrary.ProjectEvent{
+		Action:       yanzilibrary.ProjectRenamed,
+		Project:      args[1],
+		PreviousName: args[0],
+	})
+	if err != nil {
+		return err
+	}
+	if err := renameProjectState(event.PreviousName, event.Project); err != nil {
+		return err
+	}
+	return printResult(event, func() {
+		fmt.Printf("Project renamed: %s -> %s\n", event.PreviousName, event.Project)
+	})
+}
+
+// runProjectLog prints the project ledger, oldest first. With a name it prints only the
+// events of that project, including those recorded under its earlier names.
+func runProjectLog(args []string) error {
+	args, err := parseArgs("project log", args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		return errors.New("usage: yanzi project log [name]")
+	}
+
+	db, err := openProjectDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	events, err := yanzilibrary.ListProjectEvents(context.Background(), db)
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		name := strings.TrimSpace(args[0])
+		events = yanzilibrary.ProjectHistory(events, name)
+		if len(events) == 0 {
+			return fmt.Errorf("project not found: %s", name)
+		}
+	}
+
+	return printList("events", events, func() {
+		fmt.Println("Hash\tCreatedAt\tAction\tProject\tDetail")
+		for _, event := range events {
+			fmt.Printf("%s\t%s\t%s\t%s\t%s\n", event.Hash, event.CreatedAt, event.Action, event.Project, projectEventDetail(event))
+		}
+	})
+}
+
+// projectEventDetail summarizes what an event changed for the text log.
+func projectEventDetail(event yanzilibrary.ProjectEvent) string {
+	switch event.Action {
+	case yanzilibrary.ProjectCreated, yanzilibrary.ProjectDescribed:
+		return event.Description
+	case yanzilibrary.ProjectRenamed:
+		return "from " + event.PreviousName
+	default:
+		return ""
+	}
 }
 
 func projectUsageError() error {
-	return errors.New("usage: yanzi project <create|list|use|current>")
+	return errors.New("usage: yanzi project <create|list|use|current|describe|archive|unarchive|rename|log>")
+}
+
+// openProjectDB opens the local database for project commands, which are only
+// available in local mode.
+func openProjectDB() (*sql.DB, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	switch cfg.Mode {
+	case config.ModeLocal:
+		return openLocalDB(cfg)
+	case config.ModeHTTP:
+		return nil, errors.New("project commands are not available in http mode")
+	default:
+		return nil, fmt.Errorf("invalid mode: %s", cfg.Mode)
+	}
+}
+
+// appendProjectEventLocal records a project change in the local project ledger.
+func appendProjectEventLocal(event yanzilibrary.ProjectEvent) (yanzilibrary.ProjectEvent, error) {
+	db, err := openProjectDB()
+	if err != nil {
+		return yanzilibrary.ProjectEvent{}, err
+	}
+	defer db.Close()
+	return yanzilibrary.AppendProjectEvent(context.Background(), db, event)
 }
 
 func createProjectLocal(ctx context.Context, db *sql.DB, name string) (yanzilibrary.Project, error) {
@@ -188,41 +389,22 @@ func createProjectLocal(ctx context.Context, db *sql.DB, name string) (yanzilibr
 		return yanzilibrary.Project{}, errors.New("project name is required")
 	}
 
-	var count int
-	if err := db.QueryRowContext(ctx, `SELECT COUNT(1) FROM projects WHERE name = ?`, name).Scan(&count); err != nil {
+	event, err := yanzilibrary.AppendProjectEvent(ctx, db, yanzilibrary.ProjectEvent{Action: yanzilibrary.ProjectCreated, Project: name})
+	if err != nil {
 		return yanzilibrary.Project{}, err
 	}
-	if count > 0 {
-		return yanzilibrary.Project{}, fmt.Errorf("project already exists: %s", name)
-	}
-
-	createdAt := time.Now().UTC()
-	createdAtText := createdAt.Format(time.RFC3339Nano)
-	description := ""
-	hash := hashProjectRecord(name, description, createdAtText)
-	if _, err := db.ExecContext(
-		ctx,
-		`INSERT INTO projects (name, description, created_at, prev_hash, hash) VALUES (?, ?, ?, ?, ?)`,
-		name,
-		description,
-		createdAtText,
-		nil,
-		hash,
-	); err != nil {
-		if isProjectUniqueViolation(err) {
-			return yanzilibrary.Project{}, fmt.Errorf("project already exists: %s", name)
-		}
+	createdAt, err := time.Parse(time.RFC3339Nano, event.CreatedAt)
+	if err != nil {
 		return yanzilibrary.Project{}, err
 	}
 	return yanzilibrary.Project{
-		Name:        name,
-		Description: description,
-		CreatedAt:   createdAt,
+		Name:      name,
+		CreatedAt: createdAt,
 	}, nil
 }
 
 func listProjectsLocal(ctx context.Context, db *sql.DB) ([]yanzilibrary.Project, error) {
-	rows, err := db.QueryContext(ctx, `SELECT name, description, created_at FROM projects ORDER BY created_at ASC, name ASC`)
+	rows, err := db.QueryContext(ctx, `SELECT name, description, created_at, archived FROM projects ORDER BY created_at ASC, name ASC`)
 	if err != nil {
 		return nil, err
 	}
@@ -233,7 +415,7 @@ func listProjectsLocal(ctx context.Context, db *sql.DB) ([]yanzilibrary.Project,
 		var project yanzilibrary.Project
 		var description sql.NullString
 		var createdAtText string
-		if err := rows.Scan(&project.Name, &description, &createdAtText); err != nil {
+		if err := rows.Scan(&project.Name, &description, &createdAtText, &project.Archived); err != nil {
 			return nil, err
 		}
 		if description.Valid {
@@ -250,15 +432,3 @@ func listProjectsLocal(ctx context.Context, db *sql.DB) ([]yanzilibrary.Project,
 	}
 	return projects, nil
 }
-
-func hashProjectRecord(name, description, createdAt string) string {
-	sum := sha256.Sum256([]byte(name + "\n" + description + "\n" + createdAt))
-	return hex.EncodeToString(sum[:])
-}
-
-func isProjectUniqueViolation(err error) bool {
-	if err == nil {
-		return false
-	}
-	return strings.Contains(strings.ToLower(err.Error()), "unique")
-}
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
+
+	yanzilibrary "github.com/chuxorg/chux-yanzi-cli/internal/library"
 )
 
 func TestRunProjectCreate(t *testing.T) {
@@ -67,3 +70,90 @@ func TestRunProjectCreateDuplicate(t *testing.T) {
 		t.Fatalf("expected clear duplicate error, got %v", err)
 	}
 }
+
+func TestRunProjectLedgerCommands(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	writeStateFile(t, home, "alpha")
+
+	for _, args := range [][]string{
+		{"create", "alpha"},
+		{"describe", "alpha", "Payments service"},
+		{"archive", "alpha"},
+	} {
+		if _, err := captureStdout(func() error { return RunProject(args) }); err != nil {
+			t.Fatalf("RunProject %v: %v", args, err)
+		}
+	}
+
+	if err := RunProject([]string{"use", "alpha"}); err == nil || !strings.Contains(err.Error(), "archived") {
+		t.Fatalf("expected use of an archived project to fail, got %v", err)
+	}
+	output, err := captureStdout(func() error { return RunProject([]string{"list"}) })
+	if err != nil {
+		t.Fatalf("RunProject list: %v", err)
+	}
+	if strings.Contains(output, "alpha") {
+		t.Fatalf("expected archived projects to be hidden, got %q", output)
+	}
+	output, err = captureStdout(func() error { return RunProject([]string{"list", "--all"}) })
+	if err != nil {
+		t.Fatalf("RunProject list --all: %v", err)
+	}
+	if !strings.Contains(output, "Payments service\t(archived)") {
+		t.Fatalf("expected the archived project with --all, got %q", output)
+	}
+
+	for _, args := range [][]string{
+		{"unarchive", "alpha"},
+		{"rename", "alpha", "payments"},
+	} {
+		if _, err := captureStdout(func() error { return RunProject(args) }); err != nil {
+			t.Fatalf("RunProject %v: %v", args, err)
+		}
+	}
+	if active, err := loadActiveProject(); err != nil || active != "payments" {
+		t.Fatalf("expected the active project to follow the rename, got %q, %v", active, err)
+	}
+
+	useOutputFormat(t, OutputJSON)
+	output, err = captureStdout(func() error { return RunProject([]string{"log", "payments"}) })
+	if err != nil {
+		t.Fatalf("RunProject log: %v", err)
+	}
+	var log struct {
+		Events []yanzilibrary.ProjectEvent `json:"events"`
+	}
+	if err := json.Unmarshal([]byte(output), &log); err != nil {
+		t.Fatalf("decode log %q: %v", output, err)
+	}
+	var actions []string
+	for _, event := range log.Events {
+		actions = append(actions, event.Action)
+	}
+	if strings.Join(actions, ",") != "create,describe,archive,unarchive,rename" {
+		t.Fatalf("unexpected project history: %v", actions)
+	}
+
+	if _, err := captureStdout(func() error {
+		return RunCapture([]string{"--author", "Ada", "--no-git", "--prompt", "p", "--response", "r"})
+	}); err != nil {
+		t.Fatalf("RunCapture: %v", err)
+	}
+	if err := RunProject([]string{"rename", "payments", "billing"}); err == nil || !strings.Contains(err.Error(), "cannot be renamed") {
+		t.Fatalf("expected rename of a project with intents to fail, got %v", err)
+	}
+
+	output, err = captureStdout(func() error { return RunVerify([]string{"--all"}) })
+	if err != nil {
+		t.Fatalf("verify --all: %v", err)
+	}
+	var audit ledgerAudit
+	if err := json.Unmarshal([]byte(output), &audit); err != nil {
+		t.Fatalf("decode audit %q: %v", output, err)
+	}
+	if audit.Status != ledgerClean || audit.Projects != 1 || audit.ProjectEvents != 5 {
+		t.Fatalf("unexpected audit: %+v", audit)
+	}
+}
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/redact"
+)
+
+// redactionsMetaKey records, per rule, how many values were redacted from a capture.
+const redactionsMetaKey = "redactions"
+
+// captureRedaction lists what was redacted from one capture field.
+type captureRedaction struct {
+	Field    string
+	Findings []redact.Finding
+}
+
+// redactionEntry is one rule's count for one field in structured dry-run output.
+// Line is set for batch captures.
+type redactionEntry struct {
+	Line  int    `json:"line,omitempty"`
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+	Count int    `json:"count"`
+}
+
+// newRedactor builds the redactor for the built-in detectors plus config rules.
+func newRedactor(cfg config.Config) (*redact.Redactor, error) {
+	patterns := make([]redact.Pattern, 0, len(cfg.Redaction.Rules))
+	for _, rule := range cfg.Redaction.Rules {
+		patterns = append(patterns, redact.Pattern{Name: rule.Name, Regex: rule.Pattern})
+	}
+	return redact.New(patterns)
+}
+
+// redactCapture replaces secrets in the title, prompt, response, text attachments, and
+// string meta values of a capture and records the per-rule counts under the redactions
+// meta key. Values are never recorded. It returns what was redacted from each field, in
+// field order, with attachments reported as attachment.<name> and meta values as
+// meta.<key>.
+func redactCapture(redactor *redact.Redactor, input createIntentInput) (createIntentInput, []captureRedaction, error) {
+	report := redactFields(redactor, []redactField{
+		{"title", &input.Title},
+		{"prompt", &input.Prompt},
+		{"response", &input.Response},
+	})
+	input.Attachments = redactAttachments(redactor, input.Attachments, &report)
+	meta, err := redactMeta(redactor, input.Meta, &report)
+	if err != nil {
+		return createIntentInput{}, nil, err
+	}
+	input.Meta = meta
+	return input, report, nil
+}
+
+// redactCheckpoint replaces secrets in a checkpoint's summary and string meta values,
+// recording the per-rule counts in its meta like redactCapture.
+func redactCheckpoint(redactor *redact.Redactor, pending pendingCheckpoint) (pendingCheckpoint, error) {
+	report := redactFields(redactor, []redactField{{"summary", &pending.Summary}})
+	meta, err := redactMeta(redactor, pending.Meta, &report)
+	if err != nil {
+		return pendingCheckpoint{}, err
+	}
+	pending.Meta = meta
+	return pending, nil
+}
+
+// redactField is a named text field redacted in place.
+type redactField struct {
+	name  string
+	value *string
+}
+
+// redactFields redacts each field in place and reports what was redacted from it.
+func redactFields(redactor *redact.Redactor, fields []redactField) []captureRedaction {
+	var report []captureRedaction
+	for _, field := range fields {
+		redacted, findings := redactor.Redact(*field.value)
+		if len(findings) == 0 {
+			continue
+		}
+		*field.value = redacted
+		report = append(report, captureRedaction{Field: field.name, Findings: findings})
+	}
+	return report
+}
+
+// redactAttachments redacts the content of text attachments, valid UTF-8 without NUL
+// bytes, and appends what was redacted from each to report. Binary attachments are
+// stored as given. The input slice is not modified.
+func redactAttachments(redactor *redact.Redactor, attachments []attachmentInput, report *[]captureRedaction) []attachmentInput {
+	if len(attachments) == 0 {
+		return attachments
+	}
+	redacted := make([]attachmentInput, len(attachments))
+	copy(redacted, attachments)
+	for i, attachment := range redacted {
+		if !utf8.Valid(attachment.Content) || bytes.IndexByte(attachment.Content, 0) >= 0 {
+			continue
+		}
+		text, findings := redactor.Redact(string(attachment.Content))
+		if len(findings) == 0 {
+			continue
+		}
+		redacted[i].Content = []byte(text)
+		*report = append(*report, captureRedaction{Field: "attachment." + attachment.Name, Findings: findings})
+	}
+	return redacted
+}
+
+// redactMeta redacts every string value in a meta object, at any depth, appends what
+// was redacted under each top-level key to report, and adds the counts for the whole
+// report to those already under the redactions meta key, so a record redacted again
+// after its pre-hooks keeps the counts of both passes. The project key is left alone
+// because it routes the record to its chain.
+func redactMeta(redactor *redact.Redactor, meta json.RawMessage, report *[]captureRedaction) (json.RawMessage, error) {
+	if len(meta) > 0 {
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(meta, &fields); err != nil {
+			return nil, fmt.Errorf("decode meta: %w", err)
+		}
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			if key != "project" && key != redactionsMetaKey {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		changed := false
+		for _, key := range keys {
+			value, findings, err := redactJSONStrings(redactor, fields[key])
+			if err != nil {
+				return nil, err
+			}
+			if len(findings) == 0 {
+				continue
+			}
+			fields[key] = value
+			changed = true
+			*report = append(*report, captureRedaction{Field: "meta." + key, Findings: findings})
+		}
+		if changed {
+			encoded, err := json.Marshal(fields)
+			if err != nil {
+				return nil, fmt.Errorf("encode meta: %w", err)
+			}
+			meta = encoded
+		}
+	}
+	if len(*report) == 0 {
+		return meta, nil
+	}
+
+	groups := make([][]redact.Finding, 0, len(*report)+1)
+	for _, field := range *report {
+		groups = append(groups, field.Findings)
+	}
+	if earlier, ok := recordedRedactions(meta); ok {
+		groups = append(groups, earlier)
+	}
+	summary, err := json.Marshal(map[string]string{redactionsMetaKey: redact.Summary(redact.Merge(groups...))})
+	if err != nil {
+		return nil, fmt.Errorf("encode meta: %w", err)
+	}
+	return mergeMeta(meta, summary)
+}
+
+// recordedRedactions returns the counts already recorded under the redactions meta key.
+func recordedRedactions(meta json.RawMessage) ([]redact.Finding, bool) {
+	var fields map[string]json.RawMessage
+	if len(meta) == 0 || json.Unmarshal(meta, &fields) != nil {
+		return nil, false
+	}
+	var summary string
+	if err := json.Unmarshal(fields[redactionsMetaKey], &summary); err != nil {
+		return nil, false
+	}
+	return redact.ParseSummary(summary)
+}
+
+// redactJSONStrings redacts the strings in a JSON value, descending into objects and
+// arrays. The value is re-encoded only when something was redacted.
+func redactJSONStrings(redactor *redact.Redactor, value json.RawMessage) (json.RawMessage, []redact.Finding, error) {
+	trimmed := bytes.TrimSpace(value)
+	if len(trimmed) == 0 {
+		return value, nil, nil
+	}
+
+	var (
+		findings []redact.Finding
+		decoded  any
+	)
+	switch trimmed[0] {
+	case '"':
+		var text string
+		if err := json.Unmarshal(trimmed, &text); err != nil {
+			return nil, nil, fmt.Errorf("decode meta: %w", err)
+		}
+		text, findings = redactor.Redact(text)
+		decoded = text
+	case '{':
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(trimmed, &fields); err != nil {
+			return nil, nil, fmt.Errorf("decode meta: %w", err)
+		}
+		var groups [][]redact.Finding
+		for key, field := range fields {
+			redacted, found, err := redactJSONStrings(redactor, field)
+			if err != nil {
+				return nil, nil, err
+			}
+			fields[key], groups = redacted, append(groups, found)
+		}
+		findings, decoded = redact.Merge(groups...), fields
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, nil, fmt.Errorf("decode meta: %w", err)
+		}
+		var groups [][]redact.Finding
+		for i, item := range items {
+			redacted, found, err := redactJSONStrings(redactor, item)
+			if err != nil {
+				return nil, nil, err
+			}
+			items[i], groups = redacted, append(groups, found)
+		}
+		findings, decoded = redact.Merge(groups...), items
+	default:
+		return value, nil, nil
+	}
+	if len(findings) == 0 {
+		return value, nil, nil
+	}
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode meta: %w", err)
+	}
+	return encoded, findings, nil
+}
+
+// printRedactionReport describes what a capture would have redacted, without the values.
+func printRedactionReport(report []captureRedaction) error {
+	return printList("redactions", redactionEntries(0, report), func() {
+		if len(report) == 0 {
+			fmt.Println("redactions: none")
+			return
+		}
+		fmt.Println("redactions:")
+		for _, field := range report {
+			for _, finding := range field.Findings {
+				fmt.Printf("  %s\t%s\t%d\n", field.Field, finding.Rule, finding.Count)
+			}
+		}
+	})
+}
+
+// redactionEntries flattens a capture's redaction report, tagging entries with a batch line.
+func redactionEntries(line int, report []captureRedaction) []redactionEntry {
+	var entries []redactionEntry
+	for _, field := range report {
+		for _, finding := range field.Findings {
+			entries = append(entries, redactionEntry{Line: line, Field: field.Field, Rule: finding.Rule, Count: finding.Count})
+		}
+	}
+	return entries
+}
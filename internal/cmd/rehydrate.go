@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -13,8 +14,14 @@ import (
 
 // RunRehydrate renders the latest checkpoint and artifacts since.
 func RunRehydrate(args []string) error {
-	if len(args) != 0 {
-		return errors.New("usage: yanzi rehydrate")
+	fs := newFlagSet("rehydrate")
+	since := fs.String("since", "", "only artifacts at or after this "+timeRangeUsage)
+	until := fs.String("until", "", "rehydrate as of this "+timeRangeUsage)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errors.New("usage: yanzi rehydrate [--since <time>] [--until <time>]")
 	}
 
 	project, err := loadActiveProject()
@@ -43,7 +50,11 @@ func RunRehydrate(args []string) error {
 		return fmt.Errorf("invalid mode: %s", cfg.Mode)
 	}
 
-	payload, err := yanzilibrary.RehydrateProject(project)
+	window, err := parseRehydrateRange(*since, *until)
+	if err != nil {
+		return err
+	}
+	payload, err := yanzilibrary.RehydrateProjectInRange(project, window.since, window.until)
 	if err != nil {
 		if errors.Is(err, yanzilibrary.ErrCheckpointNotFound) {
 			return errors.New("no checkpoint found for active project")
@@ -59,17 +70,78 @@ func RunRehydrate(args []string) error {
 		return intents[i].CreatedAt.Before(intents[j].CreatedAt)
 	})
 
-	fmt.Printf("Project: %s\n", payload.Project)
-	fmt.Println("Latest Checkpoint:")
-	fmt.Printf("* CreatedAt: %s\n", payload.LatestCheckpoint.CreatedAt)
-	fmt.Printf("* Summary: %s\n", payload.LatestCheckpoint.Summary)
-	fmt.Println("Artifacts Since Checkpoint:")
-	if len(intents) == 0 {
-		fmt.Println("  (none)")
-		return nil
+	result := rehydrateResult{
+		Project:          payload.Project,
+		LatestCheckpoint: payload.LatestCheckpoint,
+		Artifacts:        make([]rehydrateArtifact, 0, len(intents)),
+	}
+	pause, paused, err := loadPauseState(project)
+	if err != nil {
+		return err
+	}
+	if paused {
+		result.Pause = &pause
 	}
-	for i, intent := range intents {
-		fmt.Printf("%d. %s %s %s\n", i+1, intent.ID, intent.CreatedAt.Format(time.RFC3339Nano), "intent")
+	for _, intent := range intents {
+		result.Artifacts = append(result.Artifacts, rehydrateArtifact{
+			ID:         intent.ID,
+			Type:       "intent",
+			CreatedAt:  intent.CreatedAt.Format(time.RFC3339Nano),
+			Author:     intent.Author,
+			SourceType: intent.SourceType,
+			Title:      intent.Title,
+			Hash:       intent.Hash,
+		})
+	}
+
+	return printResult(result, func() {
+		fmt.Printf("Project: %s\n", result.Project)
+		if result.Pause != nil {
+			fmt.Printf("Capture: %s\n", describePause(*result.Pause))
+		}
+		fmt.Println("Latest Checkpoint:")
+		fmt.Printf("* CreatedAt: %s\n", result.LatestCheckpoint.CreatedAt)
+		fmt.Printf("* Summary: %s\n", result.LatestCheckpoint.Summary)
+		fmt.Println("Artifacts Since Checkpoint:")
+		if len(result.Artifacts) == 0 {
+			fmt.Println("  (none)")
+			return
+		}
+		for i, artifact := range result.Artifacts {
+			fmt.Printf("%d. %s %s %s\n", i+1, artifact.ID, artifact.CreatedAt, artifact.Type)
+		}
+	})
+}
+
+// rehydrateResult is the rehydrated project context in structured output.
+type rehydrateResult struct {
+	Project          string                  `json:"project"`
+	Pause            *pauseState             `json:"pause,omitempty"`
+	LatestCheckpoint yanzilibrary.Checkpoint `json:"latest_checkpoint"`
+	Artifacts        []rehydrateArtifact     `json:"artifacts"`
+}
+
+// rehydrateArtifact is one artifact recorded since the latest checkpoint, oldest first.
+type rehydrateArtifact struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	CreatedAt  string `json:"created_at"`
+	Author     string `json:"author"`
+	SourceType string `json:"source_type"`
+	Title      string `json:"title,omitempty"`
+	Hash       string `json:"hash"`
+}
+
+// parseRehydrateRange parses the rehydrate window, opening the database only when a
+// bound is given so that checkpoint ids can be resolved.
+func parseRehydrateRange(since, until string) (timeRange, error) {
+	if since == "" && until == "" {
+		return timeRange{}, nil
+	}
+	db, err := yanzilibrary.InitDB()
+	if err != nil {
+		return timeRange{}, err
 	}
-	return nil
+	defer db.Close()
+	return parseTimeRange(since, until, time.Now(), localCheckpointTime(context.Background(), db))
 }
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+)
+
+// searchHighlight marks matched terms in search snippets.
+const searchHighlight = "**"
+
+// searchFilters narrows full-text search results.
+type searchFilters struct {
+	project string
+	author  string
+	source  string
+	window  timeRange
+}
+
+// searchResult is one ranked match with a snippet of the best matching field.
+type searchResult struct {
+	ID         string `json:"id"`
+	CreatedAt  string `json:"created_at"`
+	Author     string `json:"author"`
+	SourceType string `json:"source_type"`
+	Title      string `json:"title,omitempty"`
+	Snippet    string `json:"snippet"`
+}
+
+// RunSearch runs a full-text search over intent titles, prompts, and responses.
+func RunSearch(args []string) error {
+	fs := newFlagSet("search")
+	project := fs.String("project", "", "project filter")
+	author := fs.String("author", "", "author filter")
+	source := fs.String("source", "", "source filter")
+	since := fs.String("since", "", "only intents at or after this "+timeRangeUsage)
+	until := fs.String("until", "", "only intents at or before this "+timeRangeUsage)
+	limit := fs.Int("limit", 20, "max results to return")
+	rawQuery := fs.Bool("fts", false, "pass the query through as SQLite FTS5 query syntax")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || strings.TrimSpace(fs.Arg(0)) == "" {
+		return errors.New("usage: yanzi search \"<query>\"")
+	}
+
+	query := fs.Arg(0)
+	if !*rawQuery {
+		query = quoteSearchTerms(query)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Mode != config.ModeLocal {
+		return errors.New("search is only available in local mode")
+	}
+
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	window, err := parseTimeRange(*since, *until, time.Now(), localCheckpointTime(ctx, db))
+	if err != nil {
+		return err
+	}
+	results, err := searchLocalIntents(ctx, db, query, searchFilters{
+		project: *project,
+		author:  *author,
+		source:  *source,
+		window:  window,
+	}, *limit)
+	if err != nil {
+		if problem, ok := ftsQueryProblem(err); ok && *rawQuery {
+			return fmt.Errorf("invalid --fts query %q: %s; drop --fts to search the words literally", fs.Arg(0), problem)
+		}
+		return err
+	}
+
+	return printList("results", results, func() {
+		fmt.Println("ID\tCreated_At\tAuthor\tSource\tTitle\tSnippet")
+		for _, result := range results {
+			fmt.Printf("%s\t%s\t%s\t%s\t%s\t%s\n", result.ID, result.CreatedAt, result.Author, result.SourceType, result.Title, result.Snippet)
+		}
+	})
+}
+
+// quoteSearchTerms turns plain text into an FTS5 query matching every word, so
+// punctuation such as hyphens and dots is searched rather than parsed as syntax.
+func quoteSearchTerms(text string) string {
+	words := strings.Fields(text)
+	quoted := make([]string, 0, len(words))
+	for _, word := range words {
+		quoted = append(quoted, `"`+strings.ReplaceAll(word, `"`, `""`)+`"`)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// ftsQueryProblem reports whether err is SQLite rejecting an FTS5 query, and returns
+// the reason without the driver's prefix and result code.
+func ftsQueryProblem(err error) (string, bool) {
+	message := err.Error()
+	for _, marker := range []string{"fts5: ", "no such column: ", "unterminated string", "unknown special query"} {
+		i := strings.Index(message, marker)
+		if i < 0 {
+			continue
+		}
+		problem := strings.TrimSuffix(message[i:], " (1)")
+		return strings.TrimPrefix(problem, "fts5: "), true
+	}
+	return "", false
+}
+
+// searchLocalIntents matches an FTS5 query against the intents_fts index and returns
+// results ordered by relevance, newest first among equal ranks. With a time window,
+// rows are rechecked against its exact bounds as they stream until limit is reached.
+func searchLocalIntents(ctx context.Context, db *sql.DB, query string, filters searchFilters, limit int) ([]searchResult, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	conditions := []string{"intents_fts MATCH ?"}
+	args := []any{query}
+	if filters.project != "" {
+		conditions = append(conditions, "i.project = ?")
+		args = append(args, filters.project)
+	}
+	if filters.author != "" {
+		conditions = append(conditions, "i.author = ?")
+		args = append(args, filters.author)
+	}
+	if filters.source != "" {
+		conditions = append(conditions, "i.source_type = ?")
+		args = append(args, filters.source)
+	}
+	windowConditions, windowArgs := filters.window.sqlConditions("i.created_at")
+	conditions = append(conditions, windowConditions...)
+	args = append(args, windowArgs...)
+	limitClause := ""
+	if filters.window.isZero() {
+		limitClause = "LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT i.id, i.created_at, i.author, i.source_type, i.title,
+			snippet(intents_fts, -1, '`+searchHighlight+`', '`+searchHighlight+`', '…', 12)
+		FROM intents_fts
+		JOIN intents AS i ON i.id = intents_fts.id
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY intents_fts.rank, i.created_at DESC
+		`+limitClause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search %q: %w", query, err)
+	}
+	defer rows.Close()
+
+	var results []searchResult
+	for len(results) < limit && rows.Next() {
+		var result searchResult
+		var title sql.NullString
+		if err := rows.Scan(&result.ID, &result.CreatedAt, &result.Author, &result.SourceType, &title, &result.Snippet); err != nil {
+			return nil, err
+		}
+		if !filters.window.containsTimestamp(result.CreatedAt) {
+			continue
+		}
+		result.Title = title.String
+		result.Snippet = strings.Join(strings.Fields(result.Snippet), " ")
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("search %q: %w", query, err)
+	}
+	return results, nil
+}
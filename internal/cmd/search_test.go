@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+)
+
+func openSearchTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSearchLocalIntentsRanksAndFilters(t *testing.T) {
+	db := openSearchTestDB(t)
+	seedIntentWithMeta(t, db, "a1", "2026-01-01T10:00:00Z", "Ada", "cli", "How do I rotate the token?", "Call refresh once.", map[string]any{"project": "alpha"})
+	seedIntentWithMeta(t, db, "a2", "2026-01-02T10:00:00.5Z", "Bob", "cli", "token token token refresh", "token refresh handled", map[string]any{"project": "alpha"})
+	seedIntentWithMeta(t, db, "b1", "2026-01-03T10:00:00Z", "Ada", "import", "token in beta", "ok", map[string]any{"project": "beta"})
+	seedIntentWithMeta(t, db, "c1", "2026-01-04T10:00:00Z", "Ada", "cli", "unrelated", "nothing here", map[string]any{"project": "alpha"})
+	ctx := context.Background()
+
+	results, err := searchLocalIntents(ctx, db, quoteSearchTerms("token"), searchFilters{}, 10)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if ids := searchResultIDs(results); ids != "a2,b1,a1" && ids != "a2,a1,b1" {
+		t.Fatalf("expected a2 ranked first without c1, got %s", ids)
+	}
+	if !strings.Contains(results[0].Snippet, "**token**") {
+		t.Fatalf("expected highlighted snippet, got %q", results[0].Snippet)
+	}
+
+	cases := []struct {
+		name    string
+		filters searchFilters
+		want    string
+	}{
+		{"project", searchFilters{project: "alpha"}, "a1,a2"},
+		{"author", searchFilters{author: "Ada"}, "a1,b1"},
+		{"source", searchFilters{source: "import"}, "b1"},
+		{"window", searchFilters{window: timeRange{
+			since: time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC),
+			until: time.Date(2026, 1, 2, 10, 0, 0, 500_000_000, time.UTC),
+		}}, "a2"},
+		{"sub-second window", searchFilters{window: timeRange{
+			since: time.Date(2026, 1, 2, 10, 0, 0, 600_000_000, time.UTC),
+			until: time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC),
+		}}, ""},
+	}
+	for _, tc := range cases {
+		results, err := searchLocalIntents(ctx, db, quoteSearchTerms("token"), tc.filters, 10)
+		if err != nil {
+			t.Fatalf("%s: search: %v", tc.name, err)
+		}
+		sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+		if got := searchResultIDs(results); got != tc.want {
+			t.Fatalf("%s: expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestRunSearchQuotesPlainText(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+
+	if _, err := captureStdout(func() error {
+		return RunCapture([]string{"--author", "Ada", "--no-git", "--prompt", "explain hash-chained ledgers", "--response", "each record links to the last"})
+	}); err != nil {
+		t.Fatalf("RunCapture: %v", err)
+	}
+
+	output, err := captureStdout(func() error {
+		return RunSearch([]string{"hash-chained"})
+	})
+	if err != nil {
+		t.Fatalf("RunSearch: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], "**hash-chained**") {
+		t.Fatalf("expected one highlighted match, got %q", output)
+	}
+
+	for _, query := range []string{"hash-chained", "NOT", `"open`} {
+		err := RunSearch([]string{"--fts", query})
+		if err == nil || !strings.HasPrefix(err.Error(), "invalid --fts query ") || !strings.HasSuffix(err.Error(), "drop --fts to search the words literally") || strings.Contains(err.Error(), "SQL logic error") {
+			t.Fatalf("expected a usage error for FTS5 query %q, got %v", query, err)
+		}
+	}
+}
+
+func TestSearchIndexBackfillsExistingIntents(t *testing.T) {
+	db := openSearchTestDB(t)
+	for _, stmt := range []string{
+		`DROP TRIGGER intents_fts_after_insert`,
+		`DROP TRIGGER intents_fts_after_delete`,
+		`DROP TRIGGER intents_fts_after_update`,
+		`DROP TABLE intents_fts`,
+		`DELETE FROM schema_migrations WHERE version IN ('0007_create_intents_fts.sql', '0012_key_intents_fts_by_id.sql')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	seedIntentWithMeta(t, db, "old", "2025-06-01T00:00:00Z", "Ada", "cli", "legacy migration notes", "ok", map[string]any{})
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	migrated, err := openLocalDB(cfg)
+	if err != nil {
+		t.Fatalf("reopen db: %v", err)
+	}
+	defer migrated.Close()
+
+	results, err := searchLocalIntents(context.Background(), migrated, quoteSearchTerms("legacy"), searchFilters{}, 10)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if searchResultIDs(results) != "old" {
+		t.Fatalf("expected backfilled intent, got %s", searchResultIDs(results))
+	}
+}
+
+func TestSearchSurvivesRowidRenumbering(t *testing.T) {
+	db := openSearchTestDB(t)
+	seedIntentWithMeta(t, db, "a1", "2026-01-01T10:00:00Z", "Ada", "cli", "rotate the token", "ok", map[string]any{})
+	seedIntentWithMeta(t, db, "a2", "2026-01-02T10:00:00Z", "Ada", "cli", "unrelated", "ok", map[string]any{})
+
+	// VACUUM may renumber the implicit rowids of intents; swap them to the same effect.
+	for _, stmt := range []string{
+		`UPDATE intents SET rowid = rowid + 1000`,
+		`UPDATE intents SET rowid = CASE id WHEN 'a1' THEN 2 ELSE 1 END`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	results, err := searchLocalIntents(context.Background(), db, quoteSearchTerms("token"), searchFilters{}, 10)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if searchResultIDs(results) != "a1" || !strings.Contains(results[0].Snippet, "**token**") {
+		t.Fatalf("expected the token intent after renumbering, got %+v", results)
+	}
+}
+
+func searchResultIDs(results []searchResult) string {
+	ids := make([]string, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.ID)
+	}
+	return strings.Join(ids, ",")
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
+)
+
+const (
+	// minRefPrefix is the shortest id or hash prefix that is resolved.
+	minRefPrefix = 4
+	// defaultAbbrev is the length abbreviated ids start from before they are extended
+	// to stay unique, as with git log --oneline.
+	defaultAbbrev = 7
+	// maxRefCandidates is how many candidates an ambiguous prefix error lists.
+	maxRefCandidates = 10
+)
+
+// ambiguousRefError reports a prefix that matches more than one record.
+type ambiguousRefError struct {
+	kind       string
+	prefix     string
+	candidates []string
+	more       bool
+}
+
+func (e *ambiguousRefError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s prefix %s is ambiguous; candidates:", e.kind, e.prefix)
+	for _, candidate := range e.candidates {
+		b.WriteString("\n  " + candidate)
+	}
+	if e.more {
+		b.WriteString("\n  ...")
+	}
+	return b.String()
+}
+
+// isRefPrefix reports whether ref can be resolved as a prefix: lowercase hex of at least
+// minRefPrefix characters. Other values only match exactly.
+func isRefPrefix(ref string) bool {
+	if len(ref) < minRefPrefix {
+		return false
+	}
+	for _, r := range ref {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// dbResolveIntent loads the intent whose id is ref or whose id or hash starts with ref.
+// It returns sql.ErrNoRows when nothing matches and an error listing the candidates when
+// the prefix is ambiguous.
+func dbResolveIntent(ctx context.Context, db *sql.DB, ref string) (model.IntentRecord, error) {
+	ref = strings.TrimSpace(ref)
+	record, err := dbGetIntent(ctx, db, ref)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return record, err
+	}
+	prefix := strings.ToLower(ref)
+	if !isRefPrefix(prefix) {
+		return model.IntentRecord{}, sql.ErrNoRows
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT `+intentColumns+` FROM intents
+		WHERE id GLOB ? OR hash GLOB ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?`, prefix+"*", prefix+"*", maxRefCandidates+1)
+	if err != nil {
+		return model.IntentRecord{}, err
+	}
+	defer rows.Close()
+	var matches []model.IntentRecord
+	for rows.Next() {
+		record, err := scanIntent(rows)
+		if err != nil {
+			return model.IntentRecord{}, err
+		}
+		matches = append(matches, record)
+	}
+	if err := rows.Err(); err != nil {
+		return model.IntentRecord{}, err
+	}
+
+	switch len(matches) {
+	case 0:
+		return model.IntentRecord{}, sql.ErrNoRows
+	case 1:
+		return matches[0], nil
+	}
+	ambiguous := &ambiguousRefError{kind: "intent", prefix: ref, more: len(matches) > maxRefCandidates}
+	for i, match := range matches {
+		if i == maxRefCandidates {
+			break
+		}
+		field := "id"
+		if !strings.HasPrefix(match.ID, prefix) {
+			field = "hash " + match.Hash
+		}
+		ambiguous.candidates = append(ambiguous.candidates, fmt.Sprintf("%s  %s  %s  (%s)", match.ID, match.CreatedAt, match.Title, field))
+	}
+	return model.IntentRecord{}, ambiguous
+}
+
+// dbResolveCheckpointHash returns the hash of the checkpoint whose hash is or starts with
+// ref, sql.ErrNoRows when nothing matches, and an error listing the candidates when the
+// prefix is ambiguous.
+func dbResolveCheckpointHash(ctx context.Context, db *sql.DB, ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	var hash string
+	err := db.QueryRowContext(ctx, `SELECT hash FROM checkpoints WHERE hash = ?`, ref).Scan(&hash)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return hash, err
+	}
+	prefix := strings.ToLower(ref)
+	if !isRefPrefix(prefix) {
+		return "", sql.ErrNoRows
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT hash, project, created_at, summary FROM checkpoints
+		WHERE hash GLOB ?
+		ORDER BY created_at DESC
+		LIMIT ?`, prefix+"*", maxRefCandidates+1)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	var hashes, candidates []string
+	for rows.Next() {
+		var project, createdAt, summary string
+		if err := rows.Scan(&hash, &project, &createdAt, &summary); err != nil {
+			return "", err
+		}
+		hashes = append(hashes, hash)
+		candidates = append(candidates, fmt.Sprintf("%s  %s  %s  %s", hash, project, createdAt, summary))
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	switch len(hashes) {
+	case 0:
+		return "", sql.ErrNoRows
+	case 1:
+		return hashes[0], nil
+	}
+	more := len(candidates) > maxRefCandidates
+	if more {
+		candidates = candidates[:maxRefCandidates]
+	}
+	return "", &ambiguousRefError{kind: "checkpoint", prefix: ref, candidates: candidates, more: more}
+}
+
+// idAbbreviator shortens intent ids and hashes for text output to the shortest prefix of
+// at least defaultAbbrev characters that resolves to a single intent. Without a database,
+// as in http mode where prefixes are not resolved, values are printed in full.
+type idAbbreviator struct {
+	ctx context.Context
+	db  *sql.DB
+}
+
+// short returns the abbreviated form of an intent id or hash.
+func (a idAbbreviator) short(value string) string {
+	if a.db == nil || !isRefPrefix(value) {
+		return value
+	}
+	for n := defaultAbbrev; n < len(value); n++ {
+		prefix := value[:n]
+		var count int
+		err := a.db.QueryRowContext(a.ctx, `SELECT COUNT(*) FROM (
+			SELECT id FROM intents WHERE id GLOB ?1
+			UNION SELECT id FROM intents WHERE hash GLOB ?1
+			LIMIT 2)`, prefix+"*").Scan(&count)
+		if err != nil {
+			return value
+		}
+		if count <= 1 {
+			return prefix
+		}
+	}
+	return value
+}
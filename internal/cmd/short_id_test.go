@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDBResolveIntentPrefixes(t *testing.T) {
+	db := openSearchTestDB(t)
+	for _, row := range []struct{ id, hash string }{
+		{"abcd1111000000000000000000000000", "ffff0000aaaa"},
+		{"abcd1112000000000000000000000000", "eeee0000bbbb"},
+		{"12345678000000000000000000000000", "abcd9999cccc"},
+	} {
+		if _, err := db.Exec(`INSERT INTO intents (id, created_at, author, source_type, prompt, response, hash)
+			VALUES (?, '2026-01-01T00:00:00Z', 'Ada', 'cli', 'p', 'r', ?)`, row.id, row.hash); err != nil {
+			t.Fatalf("seed %s: %v", row.id, err)
+		}
+	}
+	ctx := context.Background()
+
+	cases := map[string]string{
+		"abcd11110":                        "abcd1111000000000000000000000000",
+		"ABCD1112":                         "abcd1112000000000000000000000000",
+		"1234":                             "12345678000000000000000000000000",
+		"ffff":                             "abcd1111000000000000000000000000",
+		"abcd9":                            "12345678000000000000000000000000",
+		"12345678000000000000000000000000": "12345678000000000000000000000000",
+	}
+	for ref, want := range cases {
+		record, err := dbResolveIntent(ctx, db, ref)
+		if err != nil || record.ID != want {
+			t.Fatalf("resolve %s: got %q, %v; want %s", ref, record.ID, err, want)
+		}
+	}
+
+	_, err := dbResolveIntent(ctx, db, "abcd")
+	var ambiguous *ambiguousRefError
+	if !errors.As(err, &ambiguous) || len(ambiguous.candidates) != 3 {
+		t.Fatalf("expected three candidates for abcd, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "(hash abcd9999cccc)") {
+		t.Fatalf("expected the hash match to be labelled, got %v", err)
+	}
+	for _, ref := range []string{"abc", "9999", "abcz"} {
+		if _, err := dbResolveIntent(ctx, db, ref); !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf("resolve %s: expected no match, got %v", ref, err)
+		}
+	}
+
+	abbrev := idAbbreviator{ctx: ctx, db: db}
+	if got := abbrev.short("12345678000000000000000000000000"); got != "1234567" {
+		t.Fatalf("expected a seven character id, got %s", got)
+	}
+	if got := abbrev.short("abcd1111000000000000000000000000"); got != "abcd1111" {
+		t.Fatalf("expected the prefix extended past other ids, got %s", got)
+	}
+	if got := (idAbbreviator{}).short("abcd1111000000000000000000000000"); len(got) != 32 {
+		t.Fatalf("expected full ids without a database, got %s", got)
+	}
+}
+
+func TestDBResolveCheckpointHashPrefixes(t *testing.T) {
+	db := openSearchTestDB(t)
+	for _, hash := range []string{"beef0001", "beef0002", "cafe0001"} {
+		if _, err := db.Exec(`INSERT INTO checkpoints (hash, project, summary, created_at, artifact_ids)
+			VALUES (?, 'alpha', 'snapshot', '2026-01-01T00:00:00Z', '[]')`, hash); err != nil {
+			t.Fatalf("seed %s: %v", hash, err)
+		}
+	}
+	ctx := context.Background()
+
+	if hash, err := dbResolveCheckpointHash(ctx, db, "cafe"); err != nil || hash != "cafe0001" {
+		t.Fatalf("expected cafe0001, got %q, %v", hash, err)
+	}
+	if _, err := dbResolveCheckpointHash(ctx, db, "beef"); err == nil || !strings.Contains(err.Error(), "beef0001") || !strings.Contains(err.Error(), "beef0002") {
+		t.Fatalf("expected ambiguous candidates, got %v", err)
+	}
+	if _, ok, err := localCheckpointTime(ctx, db)("cafe00"); !ok || err != nil {
+		t.Fatalf("expected checkpoint prefix to resolve as a time bound, got %v, %v", ok, err)
+	}
+}
@@ -2,9 +2,7 @@ package cmd
 
 import (
 	"context"
-	"flag"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/chuxorg/chux-yanzi-cli/internal/client"
@@ -13,8 +11,8 @@ import (
 
 // RunShow prints full intent details by id.
 func RunShow(args []string) error {
-	fs := flag.NewFlagSet("show", flag.ContinueOnError)
-	fs.SetOutput(os.Stderr)
+	fs := newFlagSet("show")
+	format := fs.String("format", "", formatUsage)
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -27,6 +25,10 @@ func RunShow(args []string) error {
 	if err != nil {
 		return err
 	}
+	tmpl, err := loadFormatTemplate(cfg, *format)
+	if err != nil {
+		return err
+	}
 
 	var intent client.IntentRecord
 	switch cfg.Mode {
@@ -57,24 +59,40 @@ func RunShow(args []string) error {
 		return fmt.Errorf("invalid mode: %s", cfg.Mode)
 	}
 
-	fmt.Printf("ID: %s\n", intent.ID)
-	fmt.Printf("Created_At: %s\n", intent.CreatedAt)
-	fmt.Printf("Author: %s\n", intent.Author)
-	fmt.Printf("Source: %s\n", intent.SourceType)
-	fmt.Printf("Title: %s\n", intent.Title)
-	fmt.Printf("Prev_Hash: %s\n", intent.PrevHash)
-	fmt.Printf("Hash: %s\n", intent.Hash)
-	if len(intent.Meta) > 0 {
-		fmt.Printf("Meta: %s\n", string(intent.Meta))
-	} else {
-		fmt.Printf("Meta: \n")
+	if tmpl != nil {
+		return renderTemplate(tmpl, intent)
 	}
-	fmt.Println("--- Prompt ---")
-	fmt.Println(intent.Prompt)
-	fmt.Println("--- Response ---")
-	fmt.Println(intent.Response)
-
-	return nil
+	return printResult(intent, func() {
+		fmt.Printf("ID: %s\n", intent.ID)
+		fmt.Printf("Created_At: %s\n", intent.CreatedAt)
+		fmt.Printf("Author: %s\n", intent.Author)
+		fmt.Printf("Source: %s\n", intent.SourceType)
+		fmt.Printf("Title: %s\n", intent.Title)
+		fmt.Printf("Prev_Hash: %s\n", intent.PrevHash)
+		fmt.Printf("Hash: %s\n", intent.Hash)
+		meta, err := decodeMeta(string(intent.Meta))
+		if err != nil {
+			// Not an object; show it verbatim rather than hiding it.
+			fmt.Printf("Meta: %s\n", string(intent.Meta))
+		} else if len(meta) > 0 {
+			fmt.Println("Meta:")
+			for _, line := range sortedMetaPairs(meta) {
+				fmt.Println(line)
+			}
+		} else {
+			fmt.Printf("Meta: \n")
+		}
+		if len(intent.Attachments) > 0 {
+			fmt.Println("Attachments:")
+			for _, attachment := range intent.Attachments {
+				fmt.Printf("  %s\n", formatAttachment(attachment))
+			}
+		}
+		fmt.Println("--- Prompt ---")
+		fmt.Println(intent.Prompt)
+		fmt.Println("--- Response ---")
+		fmt.Println(intent.Response)
+	})
 }
 
 func isNotFoundError(err error) bool {
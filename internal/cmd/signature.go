@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/signing"
+	yanzilibrary "github.com/chuxorg/chux-yanzi-cli/internal/library"
+)
+
+// errSigningLocalOnly rejects http mode writes while signing.key is set, so a record
+// the library stores unsigned is not mistaken for a signed one.
+var errSigningLocalOnly = errors.New("signing is only available in local mode")
+
+// loadSigner returns the key configured under signing.key, or nil when records are not
+// signed.
+func loadSigner(cfg config.Config) (*signing.Key, error) {
+	if cfg.Signing.Key == "" {
+		return nil, nil
+	}
+	dir, err := config.KeysDir()
+	if err != nil {
+		return nil, err
+	}
+	key, err := signing.LoadKey(dir, cfg.Signing.Key)
+	if err != nil {
+		return nil, fmt.Errorf("signing key: %w", err)
+	}
+	return &key, nil
+}
+
+// signIntent signs a hashed intent when signer is set.
+func signIntent(signer *signing.Key, record *model.IntentRecord) error {
+	if signer == nil {
+		return nil
+	}
+	signature, err := signer.Sign(signing.RecordIntent, record.Hash)
+	if err != nil {
+		return fmt.Errorf("sign intent: %w", err)
+	}
+	record.KeyID, record.Signature = signer.ID, signature
+	return nil
+}
+
+// checkpointStore returns a checkpoint store that signs with signer when it is set.
+func checkpointStore(db *sql.DB, signer *signing.Key) *yanzilibrary.CheckpointStore {
+	if signer == nil {
+		return yanzilibrary.NewCheckpointStore(db)
+	}
+	return yanzilibrary.NewSigningCheckpointStore(db, func(hash string) (string, string, error) {
+		signature, err := signer.Sign(signing.RecordCheckpoint, hash)
+		return signer.ID, signature, err
+	})
+}
+
+// signatureCheck verifies record signatures against the trusted keys. Unsigned records
+// pass unless requireSigned is set.
+type signatureCheck struct {
+	trusted       signing.TrustedKeys
+	requireSigned bool
+}
+
+// loadSignatureCheck reads the trusted-keys file named by the config.
+func loadSignatureCheck(cfg config.Config, requireSigned bool) (signatureCheck, error) {
+	trusted, err := signing.LoadTrustedKeys(cfg.Signing.TrustedKeys)
+	if err != nil {
+		return signatureCheck{}, err
+	}
+	return signatureCheck{trusted: trusted, requireSigned: requireSigned}, nil
+}
+
+// check returns the signature status, the trusted key owner when verified, and a
+// problem description when the signature is not acceptable.
+func (c signatureCheck) check(record, hash, keyID, signature string) (signing.Status, string, string) {
+	status, key := c.trusted.Check(record, hash, keyID, signature)
+	switch status {
+	case signing.Verified:
+		return status, key.Owner, ""
+	case signing.Untrusted:
+		return status, "", fmt.Sprintf("signing key %s is not trusted", keyID)
+	case signing.Invalid:
+		return status, "", fmt.Sprintf("signature by key %s does not verify", orUnknown(keyID))
+	default:
+		if c.requireSigned {
+			return status, "", "record is not signed"
+		}
+		return status, "", ""
+	}
+}
+
+// intentIssues returns the signature issue for an intent, if any.
+func (c signatureCheck) intentIssues(record model.IntentRecord) []ledgerIssue {
+	if _, _, problem := c.check(signing.RecordIntent, record.Hash, record.KeyID, record.Signature); problem != "" {
+		return []ledgerIssue{{Kind: issueSignature, Record: "intent", ID: record.ID, Detail: problem}}
+	}
+	return nil
+}
+
+// checkCheckpointSignature records a checkpoint's signer, or a signature problem, in
+// its verification result.
+func (c signatureCheck) checkCheckpointSignature(checkpoint yanzilibrary.Checkpoint, result *yanzilibrary.CheckpointVerification) {
+	_, signer, problem := c.check(signing.RecordCheckpoint, checkpoint.Hash, checkpoint.KeyID, checkpoint.Signature)
+	result.Signer = signer
+	if problem != "" {
+		result.Problems = append(result.Problems, yanzilibrary.CheckpointProblem{Kind: issueSignature, Detail: problem})
+		result.Valid = false
+	}
+}
+
+// orUnknown renders an empty key id in problem details.
+func orUnknown(keyID string) string {
+	if keyID == "" {
+		return "(none)"
+	}
+	return keyID
+}
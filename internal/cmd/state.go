@@ -12,52 +12,97 @@ import (
 )
 
 type projectState struct {
-	ActiveProject string `json:"active_project"`
+	ActiveProject string                `json:"active_project"`
+	Role          string                `json:"role,omitempty"`
+	Paused        map[string]pauseState `json:"paused,omitempty"`
 }
 
+// pauseState records why and when capture was paused for a project.
+type pauseState struct {
+	Reason   string `json:"reason,omitempty"`
+	PausedAt string `json:"paused_at"`
+}
+
+// defaultRole is the agent role assumed until a session declares one.
+const defaultRole = "Engineer"
+
 func loadActiveProject() (string, error) {
-	path, err := statePath()
+	state, err := loadProjectState()
 	if err != nil {
 		return "", err
 	}
+	return state.ActiveProject, nil
+}
+
+// loadActiveRole returns the declared agent role, or defaultRole when none is set.
+func loadActiveRole() (string, error) {
+	state, err := loadProjectState()
+	if err != nil {
+		return "", err
+	}
+	if state.Role == "" {
+		return defaultRole, nil
+	}
+	return state.Role, nil
+}
 
-	project, err := readActiveProject(path)
+// loadPauseState returns the pause record for a project, if capture is paused.
+func loadPauseState(project string) (pauseState, bool, error) {
+	state, err := loadProjectState()
+	if err != nil {
+		return pauseState{}, false, err
+	}
+	pause, ok := state.Paused[strings.TrimSpace(project)]
+	return pause, ok, nil
+}
+
+// loadProjectState reads ~/.yanzi/state.json, falling back to ./.yanzi/state.json.
+// A missing state file yields the zero state.
+func loadProjectState() (projectState, error) {
+	path, err := statePath()
+	if err != nil {
+		return projectState{}, err
+	}
+
+	state, err := readProjectState(path)
 	if err == nil {
-		return project, nil
+		return state, nil
 	}
 	if !errors.Is(err, os.ErrNotExist) {
-		return "", err
+		return projectState{}, err
 	}
 
 	wd, err := os.Getwd()
 	if err != nil {
-		return "", fmt.Errorf("resolve working dir: %w", err)
+		return projectState{}, fmt.Errorf("resolve working dir: %w", err)
 	}
 	fallback := filepath.Join(wd, ".yanzi", "state.json")
-	project, err = readActiveProject(fallback)
+	state, err = readProjectState(fallback)
 	if errors.Is(err, os.ErrNotExist) {
-		return "", nil
+		return projectState{}, nil
 	}
-	return project, err
+	return state, err
 }
 
-func readActiveProject(path string) (string, error) {
+func readProjectState(path string) (projectState, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return "", os.ErrNotExist
+			return projectState{}, os.ErrNotExist
 		}
-		return "", fmt.Errorf("read state file: %w", err)
+		return projectState{}, fmt.Errorf("read state file: %w", err)
 	}
 	if len(strings.TrimSpace(string(data))) == 0 {
-		return "", nil
+		return projectState{}, nil
 	}
 
 	var state projectState
 	if err := json.Unmarshal(data, &state); err != nil {
-		return "", fmt.Errorf("invalid state file: %w", err)
+		return projectState{}, fmt.Errorf("invalid state file: %w", err)
 	}
-	return strings.TrimSpace(state.ActiveProject), nil
+	state.ActiveProject = strings.TrimSpace(state.ActiveProject)
+	state.Role = strings.TrimSpace(state.Role)
+	return state, nil
 }
 
 func attachProjectMeta(meta json.RawMessage, project string) (json.RawMessage, error) {
@@ -65,13 +110,17 @@ func attachProjectMeta(meta json.RawMessage, project string) (json.RawMessage, e
 		return meta, nil
 	}
 
-	payload := map[string]string{}
+	projectValue, err := json.Marshal(strings.TrimSpace(project))
+	if err != nil {
+		return nil, fmt.Errorf("encode meta: %w", err)
+	}
+	payload := map[string]json.RawMessage{}
 	if len(meta) > 0 {
 		if err := json.Unmarshal(meta, &payload); err != nil {
 			return nil, fmt.Errorf("decode meta: %w", err)
 		}
 	}
-	payload["project"] = strings.TrimSpace(project)
+	payload["project"] = projectValue
 
 	encoded, err := json.Marshal(payload)
 	if err != nil {
@@ -81,6 +130,49 @@ func attachProjectMeta(meta json.RawMessage, project string) (json.RawMessage, e
 }
 
 func saveActiveProject(name string) error {
+	state, err := loadProjectState()
+	if err != nil {
+		return err
+	}
+	state.ActiveProject = strings.TrimSpace(name)
+	return saveProjectState(state)
+}
+
+// renameProjectState moves the active project and any pause record from one project
+// name to another.
+func renameProjectState(from, to string) error {
+	state, err := loadProjectState()
+	if err != nil {
+		return err
+	}
+	changed := false
+	if state.ActiveProject == from {
+		state.ActiveProject = to
+		changed = true
+	}
+	if pause, ok := state.Paused[from]; ok {
+		delete(state.Paused, from)
+		state.Paused[to] = pause
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return saveProjectState(state)
+}
+
+// saveActiveRole persists the declared agent role.
+func saveActiveRole(role string) error {
+	state, err := loadProjectState()
+	if err != nil {
+		return err
+	}
+	state.Role = strings.TrimSpace(role)
+	return saveProjectState(state)
+}
+
+// saveProjectState atomically writes the state file under ~/.yanzi.
+func saveProjectState(state projectState) error {
 	path, err := statePath()
 	if err != nil {
 		return err
@@ -91,7 +183,6 @@ func saveActiveProject(name string) error {
 		return fmt.Errorf("create state dir: %w", err)
 	}
 
-	state := projectState{ActiveProject: strings.TrimSpace(name)}
 	data, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
 		return fmt.Errorf("encode state: %w", err)
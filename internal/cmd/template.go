@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
+	yanzilibrary "github.com/chuxorg/chux-yanzi-cli/internal/library"
+)
+
+// formatUsage describes the values --format accepts.
+const formatUsage = "text/template for each record, or the name of a template in config.yaml"
+
+// templateFuncs are the helpers available to --format templates.
+var templateFuncs = template.FuncMap{
+	"truncate": templateTruncate,
+	"date":     templateDate,
+	"meta":     templateMeta,
+	"indent":   templateIndent,
+}
+
+// loadFormatTemplate parses a --format value: an inline template when it contains "{{",
+// otherwise the name of a template under templates in config.yaml. It returns nil when
+// no format is given.
+func loadFormatTemplate(cfg config.Config, format string) (*template.Template, error) {
+	if format == "" {
+		return nil, nil
+	}
+	if StructuredOutput() {
+		return nil, fmt.Errorf("--format cannot be combined with --output %s", outputFormat)
+	}
+	name, body := "format", format
+	if !strings.Contains(format, "{{") {
+		var ok bool
+		body, ok = cfg.Templates[format]
+		if !ok {
+			return nil, fmt.Errorf("unknown template %q (define it under templates in config.yaml)", format)
+		}
+		name = format
+	}
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("parse template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// renderTemplate writes one record through tmpl to stdout, ending it with a newline
+// unless the template already does.
+func renderTemplate(tmpl *template.Template, data any) error {
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return fmt.Errorf("render template %s: %w", tmpl.Name(), err)
+	}
+	if !bytes.HasSuffix(b.Bytes(), []byte("\n")) {
+		b.WriteByte('\n')
+	}
+	_, err := os.Stdout.Write(b.Bytes())
+	return err
+}
+
+// templateTruncate shortens s to at most n characters, marking a cut with an ellipsis.
+func templateTruncate(n int, s string) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	if n <= 0 {
+		return ""
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// templateDate formats an RFC3339 timestamp or time.Time with a Go time layout.
+func templateDate(layout string, value any) (string, error) {
+	var t time.Time
+	switch v := value.(type) {
+	case time.Time:
+		t = v
+	case string:
+		if v == "" {
+			return "", nil
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return "", fmt.Errorf("date: %w", err)
+		}
+		t = parsed
+	default:
+		return "", fmt.Errorf("date: unsupported value %T", value)
+	}
+	return t.Format(layout), nil
+}
+
+// templateMeta looks up a meta key in an intent, a checkpoint, or raw meta JSON. Strings
+// are returned as is, other values as compact JSON, and missing keys as "".
+func templateMeta(key string, value any) (string, error) {
+	var raw json.RawMessage
+	switch v := value.(type) {
+	case json.RawMessage:
+		raw = v
+	case model.IntentRecord:
+		raw = v.Meta
+	case yanzilibrary.Checkpoint:
+		raw = v.Meta
+	default:
+		return "", fmt.Errorf("meta: unsupported value %T", value)
+	}
+	if len(raw) == 0 {
+		return "", nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return "", errors.New("meta: not a JSON object")
+	}
+	field, ok := fields[key]
+	if !ok {
+		return "", nil
+	}
+	var text string
+	if err := json.Unmarshal(field, &text); err == nil {
+		return text, nil
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, field); err != nil {
+		return "", fmt.Errorf("meta: %w", err)
+	}
+	return compact.String(), nil
+}
+
+// templateIndent prefixes every line of s with n spaces.
+func templateIndent(n int, s string) string {
+	if n <= 0 || s == "" {
+		return s
+	}
+	pad := strings.Repeat(" ", n)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	if got := templateTruncate(5, "héllo world"); got != "héll…" {
+		t.Fatalf("unexpected truncate: %q", got)
+	}
+	if got := templateTruncate(20, "short"); got != "short" {
+		t.Fatalf("unexpected truncate: %q", got)
+	}
+	if got, err := templateDate("2006-01-02", "2026-03-04T05:06:07.123Z"); err != nil || got != "2026-03-04" {
+		t.Fatalf("unexpected date: %q, %v", got, err)
+	}
+	if _, err := templateDate("2006", "yesterday"); err == nil {
+		t.Fatal("expected error for an unparseable date")
+	}
+	record := model.IntentRecord{Meta: json.RawMessage(`{"ticket":"YZ-1","tags":["a", "b"]}`)}
+	for key, want := range map[string]string{"ticket": "YZ-1", "tags": `["a","b"]`, "missing": ""} {
+		if got, err := templateMeta(key, record); err != nil || got != want {
+			t.Fatalf("meta %s: got %q, %v; want %q", key, got, err, want)
+		}
+	}
+	if got := templateIndent(2, "a\nb"); got != "  a\n  b" {
+		t.Fatalf("unexpected indent: %q", got)
+	}
+}
+
+func TestFormatTemplates(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestConfig(t, home)
+	configPath := filepath.Join(home, ".yanzi", "config.yaml")
+	config, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	config = append(config, "templates:\n  oneline: '{{.Author}}: {{truncate 8 .Prompt}} [{{meta \"ticket\" .}}]'\n"...)
+	if err := os.WriteFile(configPath, config, 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	createTestProject(t, "alpha")
+	writeStateFile(t, home, "alpha")
+
+	for _, author := range []string{"Ada", "Bob"} {
+		if _, err := captureStdout(func() error {
+			return RunCapture([]string{"--author", author, "--no-git", "--prompt", "a long prompt", "--response", "ok", "--meta", "ticket=YZ-" + author})
+		}); err != nil {
+			t.Fatalf("RunCapture: %v", err)
+		}
+	}
+
+	output, err := captureStdout(func() error { return RunList([]string{"--format", "oneline"}) })
+	if err != nil {
+		t.Fatalf("RunList: %v", err)
+	}
+	if output != "Bob: a long … [YZ-Bob]\nAda: a long … [YZ-Ada]\n" {
+		t.Fatalf("unexpected named template output: %q", output)
+	}
+
+	output, err = captureStdout(func() error { return RunChain([]string{"--format", "{{.Author}}"}) })
+	if err != nil {
+		t.Fatalf("RunChain: %v", err)
+	}
+	if output != "Ada\nBob\n" {
+		t.Fatalf("expected the chain oldest first, got %q", output)
+	}
+
+	if _, err := captureStdout(func() error { return RunCheckpoint([]string{"create", "--summary", "snapshot", "--no-git"}) }); err != nil {
+		t.Fatalf("checkpoint create: %v", err)
+	}
+	output, err = captureStdout(func() error {
+		return RunCheckpoint([]string{"list", "--format", "{{.Summary}} {{.Project}}"})
+	})
+	if err != nil {
+		t.Fatalf("checkpoint list: %v", err)
+	}
+	if output != "snapshot alpha\n" {
+		t.Fatalf("unexpected checkpoint template output: %q", output)
+	}
+
+	if err := RunList([]string{"--format", "missing"}); err == nil || !strings.Contains(err.Error(), `unknown template "missing"`) {
+		t.Fatalf("expected unknown template error, got %v", err)
+	}
+	ids, err := captureStdout(func() error { return RunList([]string{"--format", "{{.ID}}", "--limit", "1"}) })
+	if err != nil {
+		t.Fatalf("RunList: %v", err)
+	}
+	output, err = captureStdout(func() error {
+		return RunShow([]string{"--format", "{{.Author}}\n{{indent 2 .Response}}\n", strings.TrimSpace(ids)})
+	})
+	if err != nil || output != "Bob\n  ok\n" {
+		t.Fatalf("unexpected show template output: %q, %v", output, err)
+	}
+	useOutputFormat(t, OutputJSON)
+	if err := RunList([]string{"--format", "oneline"}); err == nil || !strings.Contains(err.Error(), "--output") {
+		t.Fatalf("expected --format to conflict with --output, got %v", err)
+	}
+}
@@ -98,3 +98,23 @@ func captureStdout(fn func() error) (string, error) {
 
 	return buf.String(), runErr
 }
+
+func withStdin(t *testing.T, content string) {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	if _, err := writer.WriteString(content); err != nil {
+		t.Fatalf("write stdin: %v", err)
+	}
+	_ = writer.Close()
+
+	stdin := os.Stdin
+	os.Stdin = reader
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		_ = reader.Close()
+	})
+}
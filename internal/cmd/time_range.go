@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeRange bounds record timestamps, inclusive at both ends; a zero bound leaves that side open.
+type timeRange struct {
+	since time.Time
+	until time.Time
+}
+
+// checkpointTimeFunc returns the creation time of the checkpoint with the given id,
+// and false when no such checkpoint exists.
+type checkpointTimeFunc func(id string) (time.Time, bool, error)
+
+// timeRangeUsage describes the values --since and --until accept.
+const timeRangeUsage = "RFC3339 time, duration ago (2h, 3d), or checkpoint id"
+
+// parseTimeRange parses --since and --until values relative to now. checkpointTime
+// resolves checkpoint ids and may be nil where checkpoints are unavailable.
+func parseTimeRange(since, until string, now time.Time, checkpointTime checkpointTimeFunc) (timeRange, error) {
+	var r timeRange
+	var err error
+	if strings.TrimSpace(since) != "" {
+		r.since, err = parseTimeBound("--since", since, now, checkpointTime)
+		if err != nil {
+			return timeRange{}, err
+		}
+	}
+	if strings.TrimSpace(until) != "" {
+		r.until, err = parseTimeBound("--until", until, now, checkpointTime)
+		if err != nil {
+			return timeRange{}, err
+		}
+	}
+	if !r.since.IsZero() && !r.until.IsZero() && r.until.Before(r.since) {
+		return timeRange{}, fmt.Errorf("--until %s is before --since %s", until, since)
+	}
+	return r, nil
+}
+
+// parseTimeBound accepts an RFC3339 timestamp, the id of a checkpoint, which stands for
+// the time it was created, or a duration before now such as 90m, 2h, or 3d. Checkpoint
+// ids are tried before durations because a hex prefix such as 2024d is also a day count.
+func parseTimeBound(flagName, value string, now time.Time, checkpointTime checkpointTimeFunc) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.UTC(), nil
+	}
+	if checkpointTime != nil {
+		t, ok, err := checkpointTime(value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("resolve %s checkpoint %s: %w", flagName, value, err)
+		}
+		if ok {
+			return t, nil
+		}
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.UTC().AddDate(0, 0, -n), nil
+		}
+	} else if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.UTC().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s value %q (expected %s)", flagName, value, timeRangeUsage)
+}
+
+// localCheckpointTime resolves checkpoint ids, or unambiguous prefixes of them, against
+// the local checkpoints table.
+func localCheckpointTime(ctx context.Context, db *sql.DB) checkpointTimeFunc {
+	return func(id string) (time.Time, bool, error) {
+		hash, err := dbResolveCheckpointHash(ctx, db, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, false, nil
+		}
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		var createdAt string
+		if err := db.QueryRowContext(ctx, `SELECT created_at FROM checkpoints WHERE hash = ?`, hash).Scan(&createdAt); err != nil {
+			return time.Time{}, false, err
+		}
+		t, err := time.Parse(time.RFC3339Nano, createdAt)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("parse checkpoint created_at: %w", err)
+		}
+		return t, true, nil
+	}
+}
+
+// isZero reports whether the range is open at both ends.
+func (r timeRange) isZero() bool {
+	return r.since.IsZero() && r.until.IsZero()
+}
+
+// contains reports whether t falls within the range.
+func (r timeRange) contains(t time.Time) bool {
+	if !r.since.IsZero() && t.Before(r.since) {
+		return false
+	}
+	if !r.until.IsZero() && t.After(r.until) {
+		return false
+	}
+	return true
+}
+
+// containsTimestamp reports whether a stored RFC3339 timestamp falls within the range.
+// Unparseable timestamps are only kept by an open range.
+func (r timeRange) containsTimestamp(value string) bool {
+	if r.isZero() {
+		return true
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return false
+	}
+	return r.contains(t)
+}
+
+// sqlConditions returns WHERE conditions on a created_at style column and their arguments.
+// Stored timestamps are RFC3339 with trimmed fractional seconds, which do not sort as text
+// within a second, so the conditions widen each bound to its whole second and can use the
+// column's index; callers recheck rows with containsTimestamp.
+func (r timeRange) sqlConditions(column string) ([]string, []any) {
+	var conditions []string
+	var args []any
+	if !r.since.IsZero() {
+		conditions = append(conditions, column+" >= ?")
+		args = append(args, r.since.Truncate(time.Second).Format(secondLayout))
+	}
+	if !r.until.IsZero() {
+		conditions = append(conditions, column+" < ?")
+		args = append(args, r.until.Truncate(time.Second).Add(time.Second).Format(secondLayout))
+	}
+	return conditions, args
+}
+
+// secondLayout formats a bound as the whole-second prefix of a stored RFC3339 UTC timestamp.
+const secondLayout = "2006-01-02T15:04:05"
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTimeBound(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	checkpoint := time.Date(2026, 3, 1, 9, 0, 0, 250, time.UTC)
+	checkpointTime := func(id string) (time.Time, bool, error) {
+		return checkpoint, id == "cafe01" || id == "2024d", nil
+	}
+	cases := map[string]time.Time{
+		"2026-03-01T08:30:00+02:00": time.Date(2026, 3, 1, 6, 30, 0, 0, time.UTC),
+		"2h":                        now.Add(-2 * time.Hour),
+		"3d":                        now.AddDate(0, 0, -3),
+		"2025d":                     now.AddDate(0, 0, -2025),
+		"cafe01":                    checkpoint,
+		"2024d":                     checkpoint,
+	}
+	for value, want := range cases {
+		got, err := parseTimeBound("--since", value, now, checkpointTime)
+		if err != nil {
+			t.Fatalf("parseTimeBound(%q): %v", value, err)
+		}
+		if !got.Equal(want) {
+			t.Fatalf("parseTimeBound(%q) = %s, want %s", value, got, want)
+		}
+	}
+	if _, err := parseTimeBound("--since", "yesterday", now, checkpointTime); err == nil {
+		t.Fatal("expected error for unsupported value")
+	}
+	if _, err := parseTimeBound("--since", "cafe01", now, nil); err == nil {
+		t.Fatal("expected checkpoint id to be rejected without a resolver")
+	}
+	if _, err := parseTimeRange("1h", "2h", now, nil); err == nil || !strings.Contains(err.Error(), "before --since") {
+		t.Fatalf("expected inverted range error, got %v", err)
+	}
+}
+
+func TestTimeRangeFlagsAcrossCommands(t *testing.T) {
+	workdir := t.TempDir()
+	t.Setenv("HOME", workdir)
+	withCwd(t, workdir)
+	writeTestConfig(t, workdir)
+	writeStateFile(t, workdir, "alpha")
+
+	db := openConfiguredDBForExportTest(t)
+	defer db.Close()
+	seedProject(t, db, "alpha")
+	seedIntentWithSource(t, db, "early", "2026-01-01T06:00:00Z", "alpha", "engineer", "cli", "prompt early", "response")
+	seedCheckpointForExport(t, db, "alpha", "2026-01-01T12:00:00Z", "first")
+	seedIntentWithSource(t, db, "middle", "2026-01-01T18:00:00.25Z", "alpha", "engineer", "cli", "prompt middle", "response")
+	seedCheckpointForExport(t, db, "alpha", "2026-01-02T12:00:00Z", "second")
+	seedIntentWithSource(t, db, "late", "2026-01-02T18:00:00Z", "alpha", "engineer", "cli", "prompt late", "response")
+
+	checkpointID := func(summary string) string {
+		var id string
+		if err := db.QueryRowContext(context.Background(), `SELECT hash FROM checkpoints WHERE summary = ?`, summary).Scan(&id); err != nil {
+			t.Fatalf("load checkpoint %s: %v", summary, err)
+		}
+		return id
+	}
+	first, second := checkpointID("first"), checkpointID("second")
+
+	output, err := captureStdout(func() error {
+		return RunList([]string{"--since", first, "--until", second})
+	})
+	if err != nil {
+		t.Fatalf("RunList: %v", err)
+	}
+	if !strings.Contains(output, "middle\t") || strings.Contains(output, "early\t") || strings.Contains(output, "late\t") {
+		t.Fatalf("expected only the intent between checkpoints, got %q", output)
+	}
+
+	output, err = captureStdout(func() error {
+		return RunCheckpoint([]string{"list", "--since", "2026-01-02T00:00:00Z"})
+	})
+	if err != nil {
+		t.Fatalf("RunCheckpoint list: %v", err)
+	}
+	if !strings.Contains(output, "1\t2026-01-02T12:00:00Z\tsecond") || strings.Contains(output, "first") {
+		t.Fatalf("expected only the second checkpoint, got %q", output)
+	}
+
+	if _, err := captureStdout(func() error {
+		return RunExport([]string{"--format", "markdown", "--since", first, "--until", second}, "test")
+	}); err != nil {
+		t.Fatalf("RunExport: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(workdir, "YANZI_LOG.md"))
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	exported := string(data)
+	if !strings.Contains(exported, "Since: 2026-01-01T12:00:00Z") || !strings.Contains(exported, "### Capture: middle") {
+		t.Fatalf("expected windowed export, got %q", exported)
+	}
+	if strings.Contains(exported, "### Capture: early") || strings.Contains(exported, "### Capture: late") {
+		t.Fatalf("expected captures outside the window excluded, got %q", exported)
+	}
+	if !strings.Contains(exported, "Summary: first") || !strings.Contains(exported, "Summary: second") {
+		t.Fatalf("expected boundary checkpoints included, got %q", exported)
+	}
+
+	output, err = captureStdout(func() error {
+		return RunRehydrate([]string{"--until", "2026-01-02T00:00:00Z"})
+	})
+	if err != nil {
+		t.Fatalf("RunRehydrate: %v", err)
+	}
+	if !strings.Contains(output, "* Summary: first") || !strings.Contains(output, "1. middle ") || strings.Contains(output, "late") {
+		t.Fatalf("expected rehydrate as of the first day, got %q", output)
+	}
+}
@@ -3,23 +3,35 @@ package cmd
 import (
 	"context"
 	"errors"
-	"flag"
 	"fmt"
-	"os"
+	"runtime"
+	"strings"
 
 	"github.com/chuxorg/chux-yanzi-cli/internal/client"
 	"github.com/chuxorg/chux-yanzi-cli/internal/config"
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/signing"
 )
 
-// RunVerify verifies the stored hash for a given intent id.
+// RunVerify verifies the stored hash and signature for a given intent id or, with --all,
+// audits the whole ledger. An invalid intent or tampered ledger exits with
+// ExitLedgerTampered and a ledger that cannot be fully read with ExitLedgerUnreadable.
 func RunVerify(args []string) error {
-	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
-	fs.SetOutput(os.Stderr)
+	fs := newFlagSet("verify")
+	all := fs.Bool("all", false, "audit every intent, checkpoint, and project")
+	project := fs.String("project", "", "limit --all to one project")
+	workers := fs.Int("workers", runtime.NumCPU(), "concurrent hash checks for --all")
+	requireSigned := fs.Bool("require-signed", false, "treat unsigned intents and checkpoints as failures")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
-	if fs.NArg() != 1 {
-		return errors.New("usage: yanzi verify <intent-id>")
+	if *all {
+		if fs.NArg() != 0 {
+			return errors.New("usage: yanzi verify --all [--project <name>] [--workers <n>] [--require-signed]")
+		}
+		return runVerifyAll(*project, *workers, *requireSigned)
+	}
+	if fs.NArg() != 1 || *project != "" {
+		return errors.New("usage: yanzi verify [--require-signed] <intent-id> | --all [--project <name>]")
 	}
 
 	id := fs.Arg(0)
@@ -30,19 +42,15 @@ func RunVerify(args []string) error {
 	var resp verifyResult
 	switch cfg.Mode {
 	case config.ModeHTTP:
+		if *requireSigned {
+			return errors.New("--require-signed is only available in local mode")
+		}
 		cli := client.New(cfg.BaseURL)
 		httpResp, err := cli.VerifyIntent(context.Background(), id)
 		if err != nil {
 			return fmt.Errorf("http request to %s failed: %w", cfg.BaseURL, err)
 		}
-		resp = verifyResult{
-			ID:           httpResp.ID,
-			Valid:        httpResp.Valid,
-			StoredHash:   httpResp.StoredHash,
-			ComputedHash: httpResp.ComputedHash,
-			PrevHash:     httpResp.PrevHash,
-			Error:        httpResp.Error,
-		}
+		resp = httpResp
 	case config.ModeLocal:
 		ctx := context.Background()
 		db, err := openLocalDB(cfg)
@@ -51,7 +59,11 @@ func RunVerify(args []string) error {
 		}
 		defer db.Close()
 
-		localResp, err := verifyLocalIntent(ctx, db, id)
+		sigs, err := loadSignatureCheck(cfg, *requireSigned)
+		if err != nil {
+			return err
+		}
+		localResp, err := verifyLocalIntent(ctx, db, id, sigs)
 		if err != nil {
 			return err
 		}
@@ -60,16 +72,90 @@ func RunVerify(args []string) error {
 		return fmt.Errorf("invalid mode: %s", cfg.Mode)
 	}
 
-	status := "✖ INVALID"
-	if resp.Valid {
-		status = "✔ VALID"
+	if err := printResult(resp, func() {
+		status := "✖ INVALID"
+		if resp.Valid {
+			status = "✔ VALID"
+		}
+		fmt.Println(status)
+		fmt.Printf("stored_hash: %s\n", resp.StoredHash)
+		fmt.Printf("computed_hash: %s\n", resp.ComputedHash)
+		if resp.SignatureStatus != "" {
+			fmt.Printf("signature: %s\n", describeSignature(resp))
+		}
+		if resp.Error != nil {
+			fmt.Printf("error: %s\n", *resp.Error)
+		}
+	}); err != nil {
+		return err
 	}
-	fmt.Println(status)
-	fmt.Printf("stored_hash: %s\n", resp.StoredHash)
-	fmt.Printf("computed_hash: %s\n", resp.ComputedHash)
-	if resp.Error != nil {
-		fmt.Printf("error: %s\n", *resp.Error)
+	if !resp.Valid {
+		return &ExitError{Code: ExitLedgerTampered, Err: fmt.Errorf("intent %s failed verification", resp.ID)}
 	}
-
 	return nil
 }
+
+// runVerifyAll audits the local ledger and prints a summary of the issues found.
+func runVerifyAll(project string, workers int, requireSigned bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Mode != config.ModeLocal {
+		return errors.New("verify --all is only available in local mode")
+	}
+	db, err := openLocalDB(cfg)
+	if err != nil {
+		return &ExitError{Code: ExitLedgerUnreadable, Err: err}
+	}
+	defer db.Close()
+
+	sigs, err := loadSignatureCheck(cfg, requireSigned)
+	if err != nil {
+		return &ExitError{Code: ExitLedgerUnreadable, Err: err}
+	}
+	audit, err := auditLedger(context.Background(), db, strings.TrimSpace(project), workers, sigs)
+	if err != nil {
+		return &ExitError{Code: ExitLedgerUnreadable, Err: err}
+	}
+	if err := printResult(audit, func() {
+		if audit.Status == ledgerClean {
+			fmt.Println("✔ CLEAN")
+		} else {
+			fmt.Printf("✖ %s\n", strings.ToUpper(audit.Status))
+		}
+		if audit.Project != "" {
+			fmt.Printf("project: %s\n", audit.Project)
+		}
+		fmt.Printf("intents: %d\n", audit.Intents)
+		fmt.Printf("checkpoints: %d\n", audit.Checkpoints)
+		fmt.Printf("projects: %d\n", audit.Projects)
+		fmt.Printf("project_events: %d\n", audit.ProjectEvents)
+		fmt.Printf("links: %d\n", audit.Links)
+		fmt.Printf("signed: %d\n", audit.Signed)
+		fmt.Printf("mismatches: %d\n", audit.Mismatches)
+		fmt.Printf("missing_links: %d\n", audit.MissingLinks)
+		fmt.Printf("orphans: %d\n", audit.Orphans)
+		fmt.Printf("forks: %d\n", audit.Forks)
+		fmt.Printf("bad_signatures: %d\n", audit.BadSignatures)
+		fmt.Printf("unreadable: %d\n", audit.Unreadable)
+		for _, issue := range audit.Issues {
+			fmt.Printf("%s\t%s\t%s\t%s\n", issue.Kind, issue.Record, issue.ID, issue.Detail)
+		}
+	}); err != nil {
+		return err
+	}
+	return audit.exitError()
+}
+
+// describeSignature renders a verify result's signature status for text output.
+func describeSignature(resp verifyResult) string {
+	switch {
+	case resp.SignatureStatus == string(signing.Verified) && resp.Signer != "":
+		return fmt.Sprintf("%s (key %s, %s)", resp.SignatureStatus, resp.KeyID, resp.Signer)
+	case resp.KeyID != "":
+		return fmt.Sprintf("%s (key %s)", resp.SignatureStatus, resp.KeyID)
+	default:
+		return resp.SignatureStatus
+	}
+}
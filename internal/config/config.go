@@ -20,11 +20,58 @@ const (
 	ModeHTTP  Mode = "http"
 )
 
+// PausedCapture controls what capture does while the active project is paused.
+type PausedCapture string
+
+const (
+	PausedCaptureSkip  PausedCapture = "skip"
+	PausedCaptureQueue PausedCapture = "queue"
+)
+
 // Config holds CLI configuration values loaded from disk.
 type Config struct {
-	Mode    Mode   `yaml:"mode"`
-	DBPath  string `yaml:"db_path"`
-	BaseURL string `yaml:"base_url"`
+	Mode          Mode          `yaml:"mode"`
+	DBPath        string        `yaml:"db_path"`
+	BaseURL       string        `yaml:"base_url"`
+	PausedCapture PausedCapture `yaml:"paused_capture"`
+	Redaction     Redaction     `yaml:"redaction"`
+	Hooks         Hooks         `yaml:"hooks"`
+	// Templates are named text/template formats selected with --format <name>.
+	Templates map[string]string `yaml:"templates"`
+	Signing   Signing           `yaml:"signing"`
+}
+
+// Signing selects the key that signs local captures and checkpoints and the file of
+// public keys whose signatures verify accepts.
+type Signing struct {
+	// Key is the id, or a unique prefix of it, of a key under ~/.yanzi/keys. Records are
+	// not signed when it is empty.
+	Key string `yaml:"key"`
+	// TrustedKeys defaults to ~/.yanzi/trusted_keys.
+	TrustedKeys string `yaml:"trusted_keys"`
+}
+
+// Redaction configures secret redaction applied to captures before they are hashed.
+type Redaction struct {
+	Rules []RedactionRule `yaml:"rules"`
+}
+
+// RedactionRule is a named regular expression redacted in addition to the built-in detectors.
+type RedactionRule struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"`
+}
+
+// Hooks lists shell commands run before and after capture, checkpoint create, and export.
+// Pre-hooks receive the pending record as JSON on stdin and may veto it by exiting
+// non-zero or replace it by printing a modified record; post-hooks receive the stored record.
+type Hooks struct {
+	PreCapture     []string `yaml:"pre_capture"`
+	PostCapture    []string `yaml:"post_capture"`
+	PreCheckpoint  []string `yaml:"pre_checkpoint"`
+	PostCheckpoint []string `yaml:"post_checkpoint"`
+	PreExport      []string `yaml:"pre_export"`
+	PostExport     []string `yaml:"post_export"`
 }
 
 // Load reads ~/.yanzi/config.yaml and returns defaults if missing.
@@ -61,10 +108,30 @@ func Load() (Config, error) {
 	applyDefaults(&cfg)
 	cfg.BaseURL = strings.TrimSpace(cfg.BaseURL)
 	cfg.DBPath = strings.TrimSpace(cfg.DBPath)
+	cfg.Signing.Key = strings.TrimSpace(cfg.Signing.Key)
 
 	if cfg.Mode != ModeLocal && cfg.Mode != ModeHTTP {
 		return cfg, fmt.Errorf("invalid mode: %s", cfg.Mode)
 	}
+	if cfg.PausedCapture != PausedCaptureSkip && cfg.PausedCapture != PausedCaptureQueue {
+		return cfg, fmt.Errorf("invalid paused_capture: %s", cfg.PausedCapture)
+	}
+	for i, rule := range cfg.Redaction.Rules {
+		if strings.TrimSpace(rule.Name) == "" || rule.Pattern == "" {
+			return cfg, fmt.Errorf("invalid redaction rule %d: name and pattern are required", i+1)
+		}
+	}
+	if err := validateHooks(cfg.Hooks); err != nil {
+		return cfg, err
+	}
+	for name, body := range cfg.Templates {
+		if strings.TrimSpace(name) == "" || strings.Contains(name, "{{") {
+			return cfg, fmt.Errorf("invalid template name %q", name)
+		}
+		if strings.TrimSpace(body) == "" {
+			return cfg, fmt.Errorf("invalid template %s: template is required", name)
+		}
+	}
 	if cfg.Mode == ModeHTTP && cfg.BaseURL == "" {
 		return cfg, errors.New("base_url is required when mode=http")
 	}
@@ -76,11 +143,42 @@ func Load() (Config, error) {
 }
 
 func applyDefaults(cfg *Config) {
+	if cfg.PausedCapture == "" {
+		cfg.PausedCapture = PausedCaptureSkip
+	}
 	if cfg.Mode == ModeLocal && cfg.DBPath == "" {
 		if path, err := DefaultDBPath(); err == nil {
 			cfg.DBPath = path
 		}
 	}
+	cfg.Signing.TrustedKeys = strings.TrimSpace(cfg.Signing.TrustedKeys)
+	if cfg.Signing.TrustedKeys == "" {
+		if dir, err := StateDir(); err == nil {
+			cfg.Signing.TrustedKeys = filepath.Join(dir, "trusted_keys")
+		}
+	}
+}
+
+func validateHooks(hooks Hooks) error {
+	stages := []struct {
+		name     string
+		commands []string
+	}{
+		{"pre_capture", hooks.PreCapture},
+		{"post_capture", hooks.PostCapture},
+		{"pre_checkpoint", hooks.PreCheckpoint},
+		{"post_checkpoint", hooks.PostCheckpoint},
+		{"pre_export", hooks.PreExport},
+		{"post_export", hooks.PostExport},
+	}
+	for _, stage := range stages {
+		for i, command := range stage.commands {
+			if strings.TrimSpace(command) == "" {
+				return fmt.Errorf("invalid %s hook %d: command is required", stage.name, i+1)
+			}
+		}
+	}
+	return nil
 }
 
 func ensureEOF(dec *yaml.Decoder) error {
@@ -111,6 +209,15 @@ func StateDir() (string, error) {
 	return filepath.Join(home, ".yanzi"), nil
 }
 
+// KeysDir returns the ~/.yanzi/keys directory holding local signing keys.
+func KeysDir() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "keys"), nil
+}
+
 // DefaultDBPath returns the default SQLite path under ~/.yanzi.
 func DefaultDBPath() (string, error) {
 	dir, err := StateDir()
@@ -112,3 +112,114 @@ func TestLoadRejectsMultipleDocuments(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestLoadPausedCapture(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.PausedCapture != PausedCaptureSkip {
+		t.Fatalf("expected default paused_capture skip, got %q", cfg.PausedCapture)
+	}
+
+	configPath := filepath.Join(home, ".yanzi", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte("mode: local\npaused_capture: later\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "invalid paused_capture") {
+		t.Fatalf("expected invalid paused_capture error, got %v", err)
+	}
+}
+
+func TestLoadRedactionRules(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	configPath := filepath.Join(home, ".yanzi", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	data := "mode: local\nredaction:\n  rules:\n    - name: corp-token\n      pattern: 'corp_[a-z]+'\n"
+	if err := os.WriteFile(configPath, []byte(data), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(cfg.Redaction.Rules) != 1 || cfg.Redaction.Rules[0].Name != "corp-token" || cfg.Redaction.Rules[0].Pattern != "corp_[a-z]+" {
+		t.Fatalf("unexpected redaction rules: %+v", cfg.Redaction.Rules)
+	}
+
+	if err := os.WriteFile(configPath, []byte("mode: local\nredaction:\n  rules:\n    - name: missing-pattern\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "invalid redaction rule 1") {
+		t.Fatalf("expected invalid redaction rule error, got %v", err)
+	}
+}
+
+func TestLoadHooks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	configPath := filepath.Join(home, ".yanzi", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	data := "mode: local\nhooks:\n  pre_capture:\n    - ./lint.sh\n  post_export:\n    - notify-send exported\n"
+	if err := os.WriteFile(configPath, []byte(data), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(cfg.Hooks.PreCapture) != 1 || cfg.Hooks.PreCapture[0] != "./lint.sh" {
+		t.Fatalf("unexpected pre_capture hooks: %+v", cfg.Hooks.PreCapture)
+	}
+	if len(cfg.Hooks.PostExport) != 1 || cfg.Hooks.PostExport[0] != "notify-send exported" {
+		t.Fatalf("unexpected post_export hooks: %+v", cfg.Hooks.PostExport)
+	}
+
+	if err := os.WriteFile(configPath, []byte("mode: local\nhooks:\n  post_checkpoint:\n    - ' '\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "invalid post_checkpoint hook 1") {
+		t.Fatalf("expected invalid hook error, got %v", err)
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	configPath := filepath.Join(home, ".yanzi", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	data := "mode: local\ntemplates:\n  oneline: '{{.ID}} {{.Title}}'\n"
+	if err := os.WriteFile(configPath, []byte(data), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.Templates["oneline"] != "{{.ID}} {{.Title}}" {
+		t.Fatalf("unexpected templates: %+v", cfg.Templates)
+	}
+
+	if err := os.WriteFile(configPath, []byte("mode: local\ntemplates:\n  empty: ''\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "invalid template empty") {
+		t.Fatalf("expected invalid template error, got %v", err)
+	}
+}
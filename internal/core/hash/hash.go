@@ -100,6 +100,9 @@ func canonicalIntentPreimage(record model.IntentRecord) ([]byte, error) {
 	if len(meta) > 0 {
 		addRawField(&b, &first, "meta", meta)
 	}
+	if len(record.Attachments) > 0 {
+		addRawField(&b, &first, "attachments", canonicalAttachments(record.Attachments))
+	}
 	if record.PrevHash != "" {
 		addStringField(&b, &first, "prev_hash", record.PrevHash)
 	}
@@ -108,6 +111,29 @@ func canonicalIntentPreimage(record model.IntentRecord) ([]byte, error) {
 	return []byte(b.String()), nil
 }
 
+// canonicalAttachments encodes attachment references in their stored order with
+// sorted keys, omitting an empty media type.
+func canonicalAttachments(attachments []model.Attachment) json.RawMessage {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, attachment := range attachments {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('{')
+		first := true
+		addStringField(&b, &first, "digest", attachment.Digest)
+		if attachment.MediaType != "" {
+			addStringField(&b, &first, "media_type", attachment.MediaType)
+		}
+		addStringField(&b, &first, "name", attachment.Name)
+		addRawField(&b, &first, "size", json.RawMessage(strconv.FormatInt(attachment.Size, 10)))
+		b.WriteByte('}')
+	}
+	b.WriteByte(']')
+	return json.RawMessage(b.String())
+}
+
 func normalizeRFC3339(value string) (string, error) {
 	parsed, err := time.Parse(time.RFC3339Nano, value)
 	if err != nil {
@@ -54,3 +54,53 @@ func TestHashIntentCanonicalization(t *testing.T) {
 		t.Fatalf("expected identical hash for newline variants, got %s and %s", hash1, hash4)
 	}
 }
+
+func TestHashIntentAttachments(t *testing.T) {
+	base := model.IntentRecord{
+		ID:         "01HZYFQ7T9ZV54X2G4A8M4J2C1",
+		CreatedAt:  "2026-02-09T10:00:00Z",
+		Author:     "alice",
+		SourceType: "cli",
+		Prompt:     "hello",
+		Response:   "world",
+	}
+	plain, err := HashIntent(base)
+	if err != nil {
+		t.Fatalf("hash base: %v", err)
+	}
+
+	preimage, err := canonicalIntentPreimage(base)
+	if err != nil {
+		t.Fatalf("preimage: %v", err)
+	}
+	if string(preimage) != `{"id":"01HZYFQ7T9ZV54X2G4A8M4J2C1","created_at":"2026-02-09T10:00:00Z","author":"alice","source_type":"cli","prompt":"hello","response":"world"}` {
+		t.Fatalf("records without attachments must keep the v0 preimage, got %s", preimage)
+	}
+
+	attached := base
+	attached.Attachments = []model.Attachment{{Name: "fix.diff", Digest: "abc", Size: 12, MediaType: "text/x-diff"}}
+	preimage, err = canonicalIntentPreimage(attached)
+	if err != nil {
+		t.Fatalf("preimage with attachments: %v", err)
+	}
+	want := `{"id":"01HZYFQ7T9ZV54X2G4A8M4J2C1","created_at":"2026-02-09T10:00:00Z","author":"alice","source_type":"cli","prompt":"hello","response":"world","attachments":[{"digest":"abc","media_type":"text/x-diff","name":"fix.diff","size":12}]}`
+	if string(preimage) != want {
+		t.Fatalf("unexpected preimage:\n%s\nwant:\n%s", preimage, want)
+	}
+
+	withAttachment, err := HashIntent(attached)
+	if err != nil {
+		t.Fatalf("hash with attachment: %v", err)
+	}
+	if withAttachment == plain {
+		t.Fatal("expected attachments to change the hash")
+	}
+	attached.Attachments = []model.Attachment{{Name: "fix.diff", Digest: "abd", Size: 12, MediaType: "text/x-diff"}}
+	tampered, err := HashIntent(attached)
+	if err != nil {
+		t.Fatalf("hash with other digest: %v", err)
+	}
+	if tampered == withAttachment {
+		t.Fatal("expected the attachment digest to be covered by the hash")
+	}
+}
@@ -18,8 +18,22 @@ type IntentRecord struct {
 	Prompt     string          `json:"prompt"`
 	Response   string          `json:"response"`
 	Meta       json.RawMessage `json:"meta,omitempty"`
-	PrevHash   string          `json:"prev_hash,omitempty"`
-	Hash       string          `json:"hash"`
+	// Attachments reference content-addressed blobs; they are hashed after meta when present.
+	Attachments []Attachment `json:"attachments,omitempty"`
+	PrevHash    string       `json:"prev_hash,omitempty"`
+	Hash        string       `json:"hash"`
+	// KeyID and Signature sign Hash when the intent was captured with a signing key.
+	// They are stored beside the intent and are not part of its hash.
+	KeyID     string `json:"key_id,omitempty"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// Attachment references an artifact stored as a blob keyed by its SHA-256 digest.
+type Attachment struct {
+	Name      string `json:"name"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+	MediaType string `json:"media_type,omitempty"`
 }
 
 // Validate checks required fields for the v0 schema.
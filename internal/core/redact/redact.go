@@ -0,0 +1,200 @@
+// Package redact removes secrets from capture text before it is hashed and stored.
+package redact
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HighEntropyRule names the detector for long random-looking tokens.
+const HighEntropyRule = "high-entropy"
+
+// Pattern is a named regular expression supplied by the user. When the
+// expression has a capture group, only the first group is redacted.
+type Pattern struct {
+	Name  string
+	Regex string
+}
+
+// Finding reports how many values a rule redacted. It never carries the value.
+type Finding struct {
+	Rule  string
+	Count int
+}
+
+// Redactor replaces secrets matched by its rules with "[REDACTED:<rule>]".
+type Redactor struct {
+	rules []rule
+}
+
+type rule struct {
+	name string
+	re   *regexp.Regexp
+	// keep reports whether a match should be left alone; nil redacts every match.
+	keep func(string) bool
+}
+
+// builtinPatterns are applied in order before custom patterns and the entropy detector.
+var builtinPatterns = []Pattern{
+	{Name: "private-key", Regex: `(?s)-----BEGIN [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----.*?-----END [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----`},
+	{Name: "aws-access-key-id", Regex: `\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`},
+	{Name: "aws-secret-access-key", Regex: `(?i)aws_?secret_?(?:access_?)?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+]{40})\b`},
+	{Name: "github-token", Regex: `\b(?:gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{22,255})\b`},
+	{Name: "jwt", Regex: `\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`},
+}
+
+// highEntropyPattern matches candidate tokens for the entropy detector.
+var highEntropyPattern = regexp.MustCompile(`[A-Za-z0-9+/_=-]{32,}`)
+
+// minEntropy is the Shannon entropy, in bits per character, at or above which a
+// candidate token is treated as a secret. Hex digests top out at 4 bits and are
+// never redacted, so commit and content hashes survive.
+const minEntropy = 4.2
+
+// New builds a Redactor from the built-in detectors plus custom patterns.
+func New(custom []Pattern) (*Redactor, error) {
+	r := &Redactor{}
+	for _, pattern := range builtinPatterns {
+		r.rules = append(r.rules, rule{name: pattern.Name, re: regexp.MustCompile(pattern.Regex)})
+	}
+	for _, pattern := range custom {
+		name := strings.TrimSpace(pattern.Name)
+		if name == "" {
+			return nil, errors.New("redaction rule name is required")
+		}
+		if pattern.Regex == "" {
+			return nil, fmt.Errorf("redaction rule %s: pattern is required", name)
+		}
+		re, err := regexp.Compile(pattern.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("redaction rule %s: %w", name, err)
+		}
+		r.rules = append(r.rules, rule{name: name, re: re})
+	}
+	r.rules = append(r.rules, rule{name: HighEntropyRule, re: highEntropyPattern, keep: lowEntropy})
+	return r, nil
+}
+
+// Redact returns text with every secret replaced and the per-rule counts, in rule order.
+func (r *Redactor) Redact(text string) (string, []Finding) {
+	var findings []Finding
+	for _, rule := range r.rules {
+		count := 0
+		text = rule.re.ReplaceAllStringFunc(text, func(match string) string {
+			if rule.keep != nil && rule.keep(match) {
+				return match
+			}
+			count++
+			return rule.redactMatch(match)
+		})
+		if count > 0 {
+			findings = append(findings, Finding{Rule: rule.name, Count: count})
+		}
+	}
+	return text, findings
+}
+
+// redactMatch replaces the whole match, or only the first capture group when the rule has one.
+func (r rule) redactMatch(match string) string {
+	placeholder := Placeholder(r.name)
+	if r.re.NumSubexp() == 0 {
+		return placeholder
+	}
+	loc := r.re.FindStringSubmatchIndex(match)
+	if len(loc) < 4 || loc[2] < 0 {
+		return placeholder
+	}
+	return match[:loc[2]] + placeholder + match[loc[3]:]
+}
+
+// Placeholder returns the text that replaces a value redacted by rule.
+func Placeholder(rule string) string {
+	return "[REDACTED:" + rule + "]"
+}
+
+// Merge sums findings by rule and returns them sorted by rule name.
+func Merge(groups ...[]Finding) []Finding {
+	counts := map[string]int{}
+	for _, group := range groups {
+		for _, finding := range group {
+			counts[finding.Rule] += finding.Count
+		}
+	}
+	merged := make([]Finding, 0, len(counts))
+	for name, count := range counts {
+		merged = append(merged, Finding{Rule: name, Count: count})
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Rule < merged[j].Rule
+	})
+	return merged
+}
+
+// Summary renders findings as "rule:count" pairs joined by commas.
+func Summary(findings []Finding) string {
+	parts := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		parts = append(parts, finding.Rule+":"+strconv.Itoa(finding.Count))
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseSummary reads findings back from a Summary string. It reports false when
+// summary is not in that form.
+func ParseSummary(summary string) ([]Finding, bool) {
+	if summary == "" {
+		return nil, true
+	}
+	var findings []Finding
+	for _, part := range strings.Split(summary, ",") {
+		name, count, ok := strings.Cut(part, ":")
+		n, err := strconv.Atoi(count)
+		if !ok || name == "" || err != nil || n < 1 {
+			return nil, false
+		}
+		findings = append(findings, Finding{Rule: name, Count: n})
+	}
+	return findings, true
+}
+
+// lowEntropy reports whether a candidate token looks like ordinary text rather than a secret:
+// it lacks a letter or a digit, is plain hex, or its character entropy is below minEntropy.
+func lowEntropy(token string) bool {
+	var letters, digits, hex int
+	for _, c := range token {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+			hex++
+		case c >= 'a' && c <= 'f', c >= 'A' && c <= 'F':
+			letters++
+			hex++
+		case c >= 'g' && c <= 'z', c >= 'G' && c <= 'Z':
+			letters++
+		}
+	}
+	if letters == 0 || digits == 0 || hex == len(token) {
+		return true
+	}
+	return shannonEntropy(token) < minEntropy
+}
+
+// shannonEntropy returns the entropy of token in bits per character.
+func shannonEntropy(token string) float64 {
+	counts := map[rune]int{}
+	for _, c := range token {
+		counts[c]++
+	}
+	total := float64(len(token))
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / total
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
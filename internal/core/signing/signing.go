@@ -0,0 +1,275 @@
+// Package signing signs record hashes with Ed25519 keys and checks the signatures
+// against a trusted-keys file. A signature covers the record's v0 hash and is stored
+// beside the record, so signing never changes the hash itself.
+package signing
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Record kinds bound into the signed message, so a signature over an intent hash cannot
+// be replayed as one over a checkpoint hash.
+const (
+	RecordIntent     = "intent"
+	RecordCheckpoint = "checkpoint"
+)
+
+// Algorithm is the key type written in trusted-keys lines.
+const Algorithm = "ed25519"
+
+// Status is the outcome of checking a record's signature.
+type Status string
+
+const (
+	// Unsigned means the record carries no signature.
+	Unsigned Status = "unsigned"
+	// Verified means the signature is valid for a trusted key.
+	Verified Status = "verified"
+	// Untrusted means the signing key is not in the trusted-keys file.
+	Untrusted Status = "untrusted"
+	// Invalid means the signature is malformed or does not match the record hash.
+	Invalid Status = "invalid"
+)
+
+const (
+	keyFileSuffix   = ".pem"
+	pemPrivateKey   = "PRIVATE KEY"
+	pemOwnerHeader  = "Owner"
+	keyIDBytes      = 8
+	messageVersion  = "yanzi-signature-v1"
+	keyDirPerm      = 0o700
+	privateFilePerm = 0o600
+)
+
+// Key is an Ed25519 public key and, for local keys, its private half.
+type Key struct {
+	ID     string
+	Owner  string
+	Public ed25519.PublicKey
+	// Path is the private key file of a local key.
+	Path    string
+	private ed25519.PrivateKey
+}
+
+// KeyID derives the short identifier of a public key: the first 8 bytes of its SHA-256
+// digest in hex.
+func KeyID(public ed25519.PublicKey) string {
+	sum := sha256.Sum256(public)
+	return hex.EncodeToString(sum[:keyIDBytes])
+}
+
+// Message returns the bytes signed for a record: a version tag, the record kind, and
+// the record hash.
+func Message(record, hash string) []byte {
+	return []byte(messageVersion + "\n" + record + "\n" + hash)
+}
+
+// Sign signs a record hash and returns the signature in standard base64.
+func (k Key) Sign(record, hash string) (string, error) {
+	if len(k.private) != ed25519.PrivateKeySize {
+		return "", fmt.Errorf("key %s has no private key", k.ID)
+	}
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(k.private, Message(record, hash))), nil
+}
+
+// EncodedPublicKey returns the public key in standard base64.
+func (k Key) EncodedPublicKey() string {
+	return base64.StdEncoding.EncodeToString(k.Public)
+}
+
+// TrustedLine renders the key as a trusted-keys line: "ed25519 <public-key> <owner>".
+func (k Key) TrustedLine() string {
+	line := Algorithm + " " + k.EncodedPublicKey()
+	if k.Owner != "" {
+		line += " " + k.Owner
+	}
+	return line
+}
+
+// Generate creates a new key for owner and writes its private half to dir.
+func Generate(dir, owner string) (Key, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return Key{}, fmt.Errorf("generate key: %w", err)
+	}
+	key := Key{ID: KeyID(public), Owner: strings.TrimSpace(owner), Public: public, private: private}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return Key{}, fmt.Errorf("encode key: %w", err)
+	}
+	block := &pem.Block{Type: pemPrivateKey, Bytes: der}
+	if key.Owner != "" {
+		block.Headers = map[string]string{pemOwnerHeader: key.Owner}
+	}
+
+	if err := os.MkdirAll(dir, keyDirPerm); err != nil {
+		return Key{}, fmt.Errorf("create key dir: %w", err)
+	}
+	key.Path = filepath.Join(dir, key.ID+keyFileSuffix)
+	f, err := os.OpenFile(key.Path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, privateFilePerm)
+	if err != nil {
+		return Key{}, fmt.Errorf("write key: %w", err)
+	}
+	if err := pem.Encode(f, block); err != nil {
+		_ = f.Close()
+		return Key{}, fmt.Errorf("write key: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return Key{}, fmt.Errorf("write key: %w", err)
+	}
+	return key, nil
+}
+
+// LoadKeys reads every local key in dir, ordered by id. A missing dir has no keys.
+func LoadKeys(dir string) ([]Key, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Key{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read key dir: %w", err)
+	}
+
+	keys := []Key{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), keyFileSuffix) {
+			continue
+		}
+		key, err := readKeyFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, nil
+}
+
+// LoadKey returns the local key whose id is, or uniquely starts with, id.
+func LoadKey(dir, id string) (Key, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return Key{}, errors.New("key id is required")
+	}
+	keys, err := LoadKeys(dir)
+	if err != nil {
+		return Key{}, err
+	}
+	var matches []Key
+	for _, key := range keys {
+		if key.ID == id {
+			return key, nil
+		}
+		if strings.HasPrefix(key.ID, id) {
+			matches = append(matches, key)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Key{}, fmt.Errorf("key not found: %s", id)
+	case 1:
+		return matches[0], nil
+	default:
+		return Key{}, fmt.Errorf("key id prefix %s is ambiguous", id)
+	}
+}
+
+func readKeyFile(path string) (Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, fmt.Errorf("read key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemPrivateKey {
+		return Key{}, fmt.Errorf("invalid key file %s: expected a PEM %s", path, pemPrivateKey)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return Key{}, fmt.Errorf("invalid key file %s: %w", path, err)
+	}
+	private, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return Key{}, fmt.Errorf("invalid key file %s: not an %s key", path, Algorithm)
+	}
+	public := private.Public().(ed25519.PublicKey)
+	return Key{ID: KeyID(public), Owner: block.Headers[pemOwnerHeader], Public: public, Path: path, private: private}, nil
+}
+
+// TrustedKeys maps key ids to the public keys whose signatures are accepted.
+type TrustedKeys map[string]Key
+
+// LoadTrustedKeys reads a trusted-keys file. A missing file trusts no keys.
+func LoadTrustedKeys(path string) (TrustedKeys, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return TrustedKeys{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read trusted keys: %w", err)
+	}
+	defer f.Close()
+	return ParseTrustedKeys(f)
+}
+
+// ParseTrustedKeys parses trusted-keys lines of the form
+// "ed25519 <base64-public-key> [owner]". Blank lines and lines starting with # are
+// ignored; the owner is the rest of the line.
+func ParseTrustedKeys(r io.Reader) (TrustedKeys, error) {
+	keys := TrustedKeys{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 2 || fields[0] != Algorithm {
+			return nil, fmt.Errorf("trusted keys line %d: expected %q followed by a public key", line, Algorithm)
+		}
+		public, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil || len(public) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("trusted keys line %d: invalid public key", line)
+		}
+		key := Key{ID: KeyID(public), Owner: strings.Join(fields[2:], " "), Public: public}
+		keys[key.ID] = key
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read trusted keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Check verifies a record's signature. It returns the status and, when verified, the
+// trusted key that made it.
+func (t TrustedKeys) Check(record, hash, keyID, signature string) (Status, Key) {
+	if keyID == "" && signature == "" {
+		return Unsigned, Key{}
+	}
+	if keyID == "" || signature == "" {
+		return Invalid, Key{}
+	}
+	key, ok := t[keyID]
+	if !ok {
+		return Untrusted, Key{}
+	}
+	raw, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || !ed25519.Verify(key.Public, Message(record, hash), raw) {
+		return Invalid, Key{}
+	}
+	return Verified, key
+}
@@ -0,0 +1,89 @@
+package signing
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSignAndCheck(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "keys")
+	key, err := Generate(dir, "Ada Lovelace")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if info, err := os.Stat(key.Path); err != nil || info.Mode().Perm() != privateFilePerm {
+		t.Fatalf("expected a private key file with mode 0600, got %v %v", info, err)
+	}
+
+	loaded, err := LoadKey(dir, key.ID[:6])
+	if err != nil {
+		t.Fatalf("LoadKey by prefix: %v", err)
+	}
+	if loaded.ID != key.ID || loaded.Owner != "Ada Lovelace" {
+		t.Fatalf("expected the generated key, got %+v", loaded)
+	}
+	if _, err := LoadKey(dir, "ffffffff"); err == nil {
+		t.Fatal("expected an unknown key id to fail")
+	}
+
+	signature, err := loaded.Sign(RecordIntent, "abc123")
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	trusted, err := ParseTrustedKeys(strings.NewReader("# team keys\n\n" + key.TrustedLine() + "\n"))
+	if err != nil {
+		t.Fatalf("ParseTrustedKeys: %v", err)
+	}
+	if trusted[key.ID].Owner != "Ada Lovelace" {
+		t.Fatalf("expected the owner to be parsed, got %+v", trusted[key.ID])
+	}
+
+	other, err := Generate(dir, "")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	otherSignature, err := other.Sign(RecordIntent, "abc123")
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		record    string
+		hash      string
+		keyID     string
+		signature string
+		want      Status
+	}{
+		{"verified", RecordIntent, "abc123", key.ID, signature, Verified},
+		{"unsigned", RecordIntent, "abc123", "", "", Unsigned},
+		{"other hash", RecordIntent, "abc124", key.ID, signature, Invalid},
+		{"other record kind", RecordCheckpoint, "abc123", key.ID, signature, Invalid},
+		{"not base64", RecordIntent, "abc123", key.ID, "!!", Invalid},
+		{"key id only", RecordIntent, "abc123", key.ID, "", Invalid},
+		{"untrusted key", RecordIntent, "abc123", other.ID, otherSignature, Untrusted},
+	}
+	for _, tt := range tests {
+		status, signer := trusted.Check(tt.record, tt.hash, tt.keyID, tt.signature)
+		if status != tt.want {
+			t.Fatalf("%s: expected %s, got %s", tt.name, tt.want, status)
+		}
+		if (status == Verified) != (signer.ID == key.ID) {
+			t.Fatalf("%s: unexpected signer %+v", tt.name, signer)
+		}
+	}
+}
+
+func TestLoadTrustedKeys(t *testing.T) {
+	keys, err := LoadTrustedKeys(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || len(keys) != 0 {
+		t.Fatalf("expected a missing file to trust no keys, got %v %v", keys, err)
+	}
+	for _, bad := range []string{"rsa AAAA owner\n", "ed25519\n", "ed25519 bm90LWEta2V5\n"} {
+		if _, err := ParseTrustedKeys(strings.NewReader(bad)); err == nil {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
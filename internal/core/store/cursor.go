@@ -0,0 +1,49 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
+)
+
+// Cursor is a position in the intent listing order, newest first: created_at
+// descending, then id descending for intents stored with the same timestamp.
+type Cursor struct {
+	CreatedAt string `json:"c"`
+	ID        string `json:"i"`
+}
+
+// ErrInvalidCursor reports a cursor that was not produced by Encode.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorAfter returns the cursor that continues a listing after record.
+func CursorAfter(record model.IntentRecord) Cursor {
+	return Cursor{CreatedAt: record.CreatedAt, ID: record.ID}
+}
+
+// Encode returns the cursor as an opaque URL-safe token.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a token returned by Encode.
+func DecodeCursor(token string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.CreatedAt == "" || c.ID == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// Condition returns a WHERE condition selecting the rows after the cursor when rows are
+// ordered by createdAtColumn DESC, idColumn DESC, and its arguments.
+func (c Cursor) Condition(createdAtColumn, idColumn string) (string, []any) {
+	return "(" + createdAtColumn + ", " + idColumn + ") < (?, ?)", []any{c.CreatedAt, c.ID}
+}
@@ -1,13 +1,17 @@
 package store
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/big"
+	"reflect"
 
 	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
 )
 
 // FilterIntentsByMeta returns intents that match all meta filters (AND semantics).
+// See MetaValueMatches for how a filter value is compared with a typed meta value.
 func FilterIntentsByMeta(intents []model.IntentRecord, filters map[string]string) ([]model.IntentRecord, error) {
 	if len(filters) == 0 {
 		return intents, nil
@@ -15,7 +19,7 @@ func FilterIntentsByMeta(intents []model.IntentRecord, filters map[string]string
 
 	filtered := make([]model.IntentRecord, 0, len(intents))
 	for _, intent := range intents {
-		match, err := matchesMetaFilters(intent.Meta, filters)
+		match, err := MatchesMetaFilters(intent.Meta, filters)
 		if err != nil {
 			return nil, err
 		}
@@ -27,7 +31,8 @@ func FilterIntentsByMeta(intents []model.IntentRecord, filters map[string]string
 	return filtered, nil
 }
 
-func matchesMetaFilters(raw json.RawMessage, filters map[string]string) (bool, error) {
+// MatchesMetaFilters reports whether raw meta matches all filters (AND semantics).
+func MatchesMetaFilters(raw json.RawMessage, filters map[string]string) (bool, error) {
 	if len(filters) == 0 {
 		return true, nil
 	}
@@ -35,24 +40,129 @@ func matchesMetaFilters(raw json.RawMessage, filters map[string]string) (bool, e
 		return false, nil
 	}
 
-	var payload map[string]any
+	var payload map[string]json.RawMessage
 	if err := json.Unmarshal(raw, &payload); err != nil {
 		return false, fmt.Errorf("decode meta: %w", err)
 	}
 
-	meta := make(map[string]string, len(payload))
-	for key, value := range payload {
-		if s, ok := value.(string); ok {
-			meta[key] = s
-		}
-	}
-
 	for key, want := range filters {
-		have, ok := meta[key]
-		if !ok || have != want {
+		have, ok := payload[key]
+		if !ok || !MetaValueMatches(have, want) {
 			return false, nil
 		}
 	}
 
 	return true, nil
 }
+
+// MetaValueMatches reports whether a stored meta value equals a filter value given
+// on the command line. A string value matches the filter text exactly. Any other
+// value matches when the filter text parses as JSON of the same type and value:
+// numbers compare numerically (so 2 matches 2.0), while arrays and objects compare
+// structurally, ignoring key order and whitespace.
+func MetaValueMatches(have json.RawMessage, want string) bool {
+	haveValue, err := decodeMetaValue(have)
+	if err != nil {
+		return false
+	}
+	if text, ok := haveValue.(string); ok {
+		return text == want
+	}
+	wantValue, err := decodeMetaValue([]byte(want))
+	if err != nil {
+		return false
+	}
+	return metaValuesEqual(haveValue, wantValue)
+}
+
+// MetaFilterCondition returns an SQL condition selecting rows whose JSON meta column
+// holds key with a value MetaValueMatches would accept, along with its arguments.
+// SQL can only narrow arrays, objects, and numbers outside the int64 range by type
+// or approximate value, so exact is false when matching rows must be rechecked with
+// MetaValueMatches.
+func MetaFilterCondition(column, key, want string) (condition string, args []any, exact bool) {
+	match := "m.type = 'text' AND m.value = ?"
+	args = []any{key, want}
+	exact = true
+	if value, err := decodeMetaValue([]byte(want)); err == nil {
+		switch v := value.(type) {
+		case json.Number:
+			match += " OR m.type IN ('integer', 'real') AND m.value = ?"
+			if n, err := v.Int64(); err == nil {
+				args = append(args, n)
+			} else {
+				f, _ := v.Float64()
+				args = append(args, f)
+				exact = false
+			}
+		case bool:
+			if v {
+				match += " OR m.type = 'true'"
+			} else {
+				match += " OR m.type = 'false'"
+			}
+		case nil:
+			match += " OR m.type = 'null'"
+		case []any:
+			match += " OR m.type = 'array'"
+			exact = false
+		case map[string]any:
+			match += " OR m.type = 'object'"
+			exact = false
+		}
+	}
+	condition = "EXISTS (SELECT 1 FROM json_each(CASE WHEN json_valid(" + column + ") THEN " + column + " END) AS m" +
+		" WHERE m.key = ? AND (" + match + "))"
+	return condition, args, exact
+}
+
+func decodeMetaValue(raw []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected trailing JSON data")
+	}
+	return value, nil
+}
+
+func metaValuesEqual(a, b any) bool {
+	switch av := a.(type) {
+	case json.Number:
+		bv, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, okA := new(big.Float).SetString(av.String())
+		y, okB := new(big.Float).SetString(bv.String())
+		return okA && okB && x.Cmp(y) == 0
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !metaValuesEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for key, value := range av {
+			other, ok := bv[key]
+			if !ok || !metaValuesEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/chuxorg/chux-yanzi-cli/internal/core/model"
@@ -73,3 +74,37 @@ func TestFilterIntentsByMetaInvalidJSON(t *testing.T) {
 		t.Fatalf("expected error for invalid meta JSON")
 	}
 }
+
+func TestFilterIntentsByMetaTypedValues(t *testing.T) {
+	intents := []model.IntentRecord{
+		{ID: "a", Meta: json.RawMessage(`{"count":2,"dirty":true,"tags":["x","y"],"review":{"by":"ada","score":4}}`)},
+		{ID: "b", Meta: json.RawMessage(`{"count":"2","dirty":"true"}`)},
+		{ID: "c", Meta: json.RawMessage(`{"count":2.0,"dirty":false,"tags":["y","x"]}`)},
+	}
+
+	cases := []struct {
+		filters map[string]string
+		want    []string
+	}{
+		{map[string]string{"count": "2"}, []string{"a", "b", "c"}},
+		{map[string]string{"count": "2.00"}, []string{"a", "c"}},
+		{map[string]string{"dirty": "true"}, []string{"a", "b"}},
+		{map[string]string{"dirty": "false"}, []string{"c"}},
+		{map[string]string{"tags": `["x","y"]`}, []string{"a"}},
+		{map[string]string{"review": `{"score":4.0, "by":"ada"}`}, []string{"a"}},
+		{map[string]string{"tags": "x"}, nil},
+	}
+	for _, tc := range cases {
+		filtered, err := FilterIntentsByMeta(intents, tc.filters)
+		if err != nil {
+			t.Fatalf("filter %v: %v", tc.filters, err)
+		}
+		var got []string
+		for _, intent := range filtered {
+			got = append(got, intent.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Fatalf("filter %v: expected %v, got %v", tc.filters, tc.want, got)
+		}
+	}
+}
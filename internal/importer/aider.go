@@ -0,0 +1,162 @@
+package importer
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	aiderSessionPrefix = "# aider chat started at "
+	// aiderTimeLayout is the local time aider writes in a session header.
+	aiderTimeLayout   = "2006-01-02 15:04:05"
+	aiderPromptPrefix = "#### "
+	aiderToolPrefix   = ">"
+)
+
+// aiderModelLine matches the model banner aider prints at startup and after /model.
+var aiderModelLine = regexp.MustCompile(`^> (?:Main model|Models?): (\S+)`)
+
+// aiderPromptCommands are chat commands whose argument is itself a prompt.
+var aiderPromptCommands = []string{"/ask", "/code", "/architect"}
+
+// ParseAider reads an .aider.chat.history.md file. Each "# aider chat started at"
+// header begins a conversation created at that local time, converted to RFC3339 UTC;
+// "####" lines are user prompts, "> " lines are aider tool output, and everything else
+// is the reply. Conversations are identified by aiderConversationID.
+func ParseAider(r io.Reader) ([]Conversation, error) {
+	var (
+		conversations []Conversation
+		current       *Conversation
+		model         string
+		role          string
+		lines         []string
+	)
+	flushMessage := func() {
+		if role == "" {
+			return
+		}
+		content := strings.Join(lines, "\n")
+		if role == RoleUser {
+			content, role = aiderPrompt(content)
+		}
+		if strings.TrimSpace(content) != "" {
+			message := Message{Role: role, Content: strings.TrimLeft(content, "\n")}
+			if role == RoleAssistant {
+				message.Model = model
+			}
+			current.Messages = append(current.Messages, message)
+		}
+		role, lines = "", nil
+	}
+	startConversation := func(startedAt string) {
+		flushMessage()
+		conversations = append(conversations, Conversation{
+			Title:     "aider chat started at " + startedAt,
+			CreatedAt: aiderStartTime(startedAt),
+		})
+		current = &conversations[len(conversations)-1]
+		model = ""
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if current == nil && !strings.HasPrefix(line, aiderSessionPrefix) {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			// History written before the first header still forms a conversation.
+			startConversation("")
+		}
+		switch {
+		case strings.HasPrefix(line, aiderSessionPrefix):
+			startConversation(strings.TrimSpace(strings.TrimPrefix(line, aiderSessionPrefix)))
+		case line == "####" || strings.HasPrefix(line, aiderPromptPrefix):
+			if role != RoleUser {
+				flushMessage()
+				role = RoleUser
+			}
+			lines = append(lines, strings.TrimPrefix(strings.TrimPrefix(line, "####"), " "))
+		case strings.HasPrefix(line, aiderToolPrefix):
+			if match := aiderModelLine.FindStringSubmatch(line); match != nil {
+				model = match[1]
+				if current.Model == "" {
+					current.Model = model
+				}
+			}
+			if role == RoleUser {
+				flushMessage()
+			}
+		default:
+			if role == RoleUser {
+				flushMessage()
+			}
+			if role == "" {
+				if strings.TrimSpace(line) == "" {
+					continue
+				}
+				role = RoleAssistant
+			}
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read aider history: %w", err)
+	}
+	if current != nil {
+		flushMessage()
+	}
+
+	nonEmpty := conversations[:0]
+	for _, conversation := range conversations {
+		if len(conversation.Messages) > 0 {
+			conversation.ID = aiderConversationID(conversation.CreatedAt, conversation.Messages[0])
+			nonEmpty = append(nonEmpty, conversation)
+		}
+	}
+	return nonEmpty, nil
+}
+
+// aiderStartTime converts a session header's local time to RFC3339 UTC, or returns ""
+// when the header is missing or not in aider's format.
+func aiderStartTime(header string) string {
+	started, err := time.ParseInLocation(aiderTimeLayout, header, time.Local)
+	if err != nil {
+		return ""
+	}
+	return started.UTC().Format(time.RFC3339)
+}
+
+// aiderConversationID names a session by its start time and a digest of its first
+// message. Sessions started in the same second stay distinct, and the id does not
+// change as aider appends to the session.
+func aiderConversationID(createdAt string, first Message) string {
+	sum := sha256.Sum256([]byte(first.Role + "\n" + first.Content))
+	suffix := hex.EncodeToString(sum[:4])
+	if createdAt == "" {
+		return suffix
+	}
+	return createdAt + "-" + suffix
+}
+
+// aiderPrompt classifies a prompt: chat commands are tool input unless they carry
+// a prompt (/ask, /code, /architect), in which case the command word is dropped.
+func aiderPrompt(content string) (string, string) {
+	trimmed := strings.TrimSpace(content)
+	if !strings.HasPrefix(trimmed, "/") {
+		return content, RoleUser
+	}
+	for _, command := range aiderPromptCommands {
+		if trimmed == command || strings.HasPrefix(trimmed, command+" ") || strings.HasPrefix(trimmed, command+"\n") {
+			return strings.TrimSpace(strings.TrimPrefix(trimmed, command)), RoleUser
+		}
+	}
+	return content, RoleTool
+}
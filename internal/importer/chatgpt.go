@@ -0,0 +1,145 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// chatGPTConversation is one entry of a ChatGPT conversations.json export.
+type chatGPTConversation struct {
+	ID             string                 `json:"id"`
+	ConversationID string                 `json:"conversation_id"`
+	Title          string                 `json:"title"`
+	CreateTime     *float64               `json:"create_time"`
+	CurrentNode    string                 `json:"current_node"`
+	DefaultModel   string                 `json:"default_model_slug"`
+	Mapping        map[string]chatGPTNode `json:"mapping"`
+}
+
+type chatGPTNode struct {
+	ID      string          `json:"id"`
+	Parent  string          `json:"parent"`
+	Message *chatGPTMessage `json:"message"`
+}
+
+type chatGPTMessage struct {
+	Author struct {
+		Role string `json:"role"`
+	} `json:"author"`
+	CreateTime *float64 `json:"create_time"`
+	Content    struct {
+		ContentType string            `json:"content_type"`
+		Parts       []json.RawMessage `json:"parts"`
+		Text        string            `json:"text"`
+	} `json:"content"`
+	Recipient string `json:"recipient"`
+	Metadata  struct {
+		ModelSlug string `json:"model_slug"`
+		Hidden    bool   `json:"is_visually_hidden_from_conversation"`
+	} `json:"metadata"`
+}
+
+// ParseChatGPT reads a ChatGPT conversations.json export. Each conversation is a
+// tree of message nodes; the branch ending at current_node, the one shown in the
+// ChatGPT UI, is imported.
+func ParseChatGPT(r io.Reader) ([]Conversation, error) {
+	var raw []chatGPTConversation
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decode chatgpt export: %w", err)
+	}
+
+	conversations := make([]Conversation, 0, len(raw))
+	for i, entry := range raw {
+		id := entry.ConversationID
+		if id == "" {
+			id = entry.ID
+		}
+		if id == "" {
+			return nil, fmt.Errorf("conversation %d: id is required", i+1)
+		}
+		nodes, err := chatGPTBranch(entry)
+		if err != nil {
+			return nil, fmt.Errorf("conversation %s: %w", id, err)
+		}
+
+		conversation := Conversation{
+			ID:        id,
+			Title:     entry.Title,
+			CreatedAt: unixTimestamp(entry.CreateTime),
+			Model:     entry.DefaultModel,
+		}
+		for _, node := range nodes {
+			message := node.Message
+			if message == nil || message.Metadata.Hidden {
+				continue
+			}
+			role := message.Author.Role
+			if role == RoleAssistant && message.Recipient != "" && message.Recipient != "all" {
+				// Assistant messages addressed to a tool are calls, not replies.
+				role = RoleTool
+			}
+			conversation.Messages = append(conversation.Messages, Message{
+				Role:      role,
+				Content:   chatGPTContent(message),
+				CreatedAt: unixTimestamp(message.CreateTime),
+				Model:     message.Metadata.ModelSlug,
+			})
+		}
+		conversations = append(conversations, conversation)
+	}
+	return conversations, nil
+}
+
+// chatGPTBranch walks from current_node to the root and returns the nodes oldest first.
+func chatGPTBranch(entry chatGPTConversation) ([]chatGPTNode, error) {
+	current := entry.CurrentNode
+	if current == "" {
+		return nil, nil
+	}
+	var branch []chatGPTNode
+	seen := map[string]bool{}
+	for current != "" {
+		if seen[current] {
+			return nil, fmt.Errorf("message tree has a cycle at %s", current)
+		}
+		seen[current] = true
+		node, ok := entry.Mapping[current]
+		if !ok {
+			return nil, fmt.Errorf("message node not found: %s", current)
+		}
+		branch = append(branch, node)
+		current = node.Parent
+	}
+	for i, j := 0, len(branch)-1; i < j; i, j = i+1, j-1 {
+		branch[i], branch[j] = branch[j], branch[i]
+	}
+	return branch, nil
+}
+
+// chatGPTContent joins the text parts of a message; non-text parts such as images are skipped.
+func chatGPTContent(message *chatGPTMessage) string {
+	if message.Content.Text != "" {
+		return message.Content.Text
+	}
+	var texts []string
+	for _, part := range message.Content.Parts {
+		var text string
+		if err := json.Unmarshal(part, &text); err == nil {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// unixTimestamp renders fractional Unix seconds as RFC 3339 UTC text.
+func unixTimestamp(seconds *float64) string {
+	if seconds == nil || *seconds <= 0 {
+		return ""
+	}
+	whole, frac := math.Modf(*seconds)
+	return time.Unix(int64(whole), int64(frac*1e9)).UTC().Format(time.RFC3339Nano)
+}
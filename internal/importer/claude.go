@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// claudeConversation is one entry of a Claude data export conversations.json.
+type claudeConversation struct {
+	UUID      string          `json:"uuid"`
+	Name      string          `json:"name"`
+	CreatedAt string          `json:"created_at"`
+	Model     string          `json:"model"`
+	Messages  []claudeMessage `json:"chat_messages"`
+}
+
+type claudeMessage struct {
+	Sender    string `json:"sender"`
+	Text      string `json:"text"`
+	CreatedAt string `json:"created_at"`
+	Content   []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+// ParseClaude reads the conversations.json file from a Claude data export.
+func ParseClaude(r io.Reader) ([]Conversation, error) {
+	var raw []claudeConversation
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decode claude export: %w", err)
+	}
+
+	conversations := make([]Conversation, 0, len(raw))
+	for i, entry := range raw {
+		if entry.UUID == "" {
+			return nil, fmt.Errorf("conversation %d: uuid is required", i+1)
+		}
+		conversation := Conversation{
+			ID:        entry.UUID,
+			Title:     entry.Name,
+			CreatedAt: entry.CreatedAt,
+			Model:     entry.Model,
+		}
+		for _, message := range entry.Messages {
+			conversation.Messages = append(conversation.Messages, Message{
+				Role:      message.Sender,
+				Content:   claudeContent(message),
+				CreatedAt: message.CreatedAt,
+			})
+		}
+		conversations = append(conversations, conversation)
+	}
+	return conversations, nil
+}
+
+// claudeContent prefers the structured text blocks of a message and falls back to
+// its flattened text; tool use and other block types are skipped.
+func claudeContent(message claudeMessage) string {
+	var texts []string
+	for _, block := range message.Content {
+		if block.Type == "text" && block.Text != "" {
+			texts = append(texts, block.Text)
+		}
+	}
+	if len(texts) == 0 {
+		return message.Text
+	}
+	return strings.Join(texts, "\n\n")
+}
@@ -0,0 +1,202 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const chatGPTExport = `[
+  {
+    "id": "conv-1",
+    "title": "Refactor auth",
+    "create_time": 1714557600.5,
+    "current_node": "n4",
+    "mapping": {
+      "root": {"id": "root", "parent": "", "message": null},
+      "n1": {"id": "n1", "parent": "root", "message": {"author": {"role": "system"}, "content": {"content_type": "text", "parts": [""]}, "metadata": {"is_visually_hidden_from_conversation": true}}},
+      "n2": {"id": "n2", "parent": "n1", "message": {"author": {"role": "user"}, "create_time": 1714557601, "content": {"content_type": "text", "parts": ["Split the auth module"]}}},
+      "n3a": {"id": "n3a", "parent": "n2", "message": {"author": {"role": "assistant"}, "create_time": 1714557602, "content": {"content_type": "text", "parts": ["abandoned branch"]}, "metadata": {"model_slug": "gpt-4"}}},
+      "n3": {"id": "n3", "parent": "n2", "message": {"author": {"role": "assistant"}, "recipient": "python", "content": {"content_type": "code", "text": "print(1)"}}},
+      "n4": {"id": "n4", "parent": "n3", "message": {"author": {"role": "assistant"}, "create_time": 1714557605, "content": {"content_type": "text", "parts": ["Done.", {"asset_pointer": "file-1"}]}, "metadata": {"model_slug": "gpt-4o"}}}
+    }
+  }
+]`
+
+func TestParseChatGPTFollowsCurrentBranch(t *testing.T) {
+	conversations, err := ParseChatGPT(strings.NewReader(chatGPTExport))
+	if err != nil {
+		t.Fatalf("ParseChatGPT: %v", err)
+	}
+	if len(conversations) != 1 {
+		t.Fatalf("expected 1 conversation, got %d", len(conversations))
+	}
+	conversation := conversations[0]
+	if conversation.ID != "conv-1" || conversation.Title != "Refactor auth" {
+		t.Fatalf("unexpected conversation: %+v", conversation)
+	}
+	if conversation.CreatedAt != "2024-05-01T10:00:00.5Z" {
+		t.Fatalf("unexpected created_at: %q", conversation.CreatedAt)
+	}
+
+	turns, _, err := conversation.Turns()
+	if err != nil {
+		t.Fatalf("Turns: %v", err)
+	}
+	if len(turns) != 1 {
+		t.Fatalf("expected 1 turn, got %+v", turns)
+	}
+	turn := turns[0]
+	if turn.Prompt != "Split the auth module" || turn.Response != "Done." {
+		t.Fatalf("unexpected turn text: %+v", turn)
+	}
+	if turn.Model != "gpt-4o" || turn.PromptedAt != "2024-05-01T10:00:01Z" || turn.RespondedAt != "2024-05-01T10:00:05Z" {
+		t.Fatalf("unexpected turn metadata: %+v", turn)
+	}
+}
+
+func TestParseClaudeUsesTextBlocks(t *testing.T) {
+	export := `[{
+		"uuid": "c-1",
+		"name": "Schema review",
+		"created_at": "2024-05-02T09:00:00Z",
+		"chat_messages": [
+			{"sender": "human", "text": "Review this schema", "created_at": "2024-05-02T09:00:01Z", "content": [{"type": "text", "text": "Review this schema"}]},
+			{"sender": "assistant", "text": "flattened", "created_at": "2024-05-02T09:00:09Z", "content": [{"type": "tool_use"}, {"type": "text", "text": "Looks good."}]}
+		]
+	}]`
+
+	conversations, err := ParseClaude(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("ParseClaude: %v", err)
+	}
+	turns, _, err := conversations[0].Turns()
+	if err != nil {
+		t.Fatalf("Turns: %v", err)
+	}
+	if len(turns) != 1 || turns[0].Prompt != "Review this schema" || turns[0].Response != "Looks good." {
+		t.Fatalf("unexpected turns: %+v", turns)
+	}
+	if turns[0].PromptedAt != "2024-05-02T09:00:01Z" || turns[0].RespondedAt != "2024-05-02T09:00:09Z" {
+		t.Fatalf("unexpected timestamps: %+v", turns[0])
+	}
+}
+
+func TestParseAiderSessions(t *testing.T) {
+	history := `
+# aider chat started at 2024-06-01 09:15:02
+
+> /usr/bin/aider --model gpt-4o
+> Models: gpt-4o with diff edit format, weak model gpt-4o-mini
+
+#### /add hello.py
+
+> Added hello.py to the chat
+
+#### add a hello function
+#### that prints a greeting
+
+Here is the change:
+
+hello.py
+` + "```python\nprint(\"hi\")\n```" + `
+
+> Applied edit to hello.py
+
+# aider chat started at 2024-06-02 10:00:00
+
+> Main model: claude-3-5-sonnet with diff edit format
+
+#### /ask why?
+
+Because.
+`
+
+	conversations, err := ParseAider(strings.NewReader(history))
+	if err != nil {
+		t.Fatalf("ParseAider: %v", err)
+	}
+	if len(conversations) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(conversations))
+	}
+	started, err := time.ParseInLocation("2006-01-02 15:04:05", "2024-06-01 09:15:02", time.Local)
+	if err != nil {
+		t.Fatalf("parse start time: %v", err)
+	}
+	createdAt := started.UTC().Format(time.RFC3339)
+	first := conversations[0]
+	if first.CreatedAt != createdAt || !strings.HasPrefix(first.ID, createdAt+"-") || len(first.ID) != len(createdAt)+9 || first.Model != "gpt-4o" {
+		t.Fatalf("unexpected first session: %+v", first)
+	}
+
+	turns, unpaired, err := conversations[0].Turns()
+	if err != nil {
+		t.Fatalf("Turns: %v", err)
+	}
+	if unpaired != 0 || len(turns) != 1 {
+		t.Fatalf("expected one turn, got %+v (unpaired %d)", turns, unpaired)
+	}
+	if turns[0].Prompt != "add a hello function\nthat prints a greeting" {
+		t.Fatalf("unexpected prompt: %q", turns[0].Prompt)
+	}
+	if !strings.HasPrefix(turns[0].Response, "Here is the change:") || strings.Contains(turns[0].Response, "Applied edit") {
+		t.Fatalf("unexpected response: %q", turns[0].Response)
+	}
+
+	turns, _, err = conversations[1].Turns()
+	if err != nil {
+		t.Fatalf("Turns: %v", err)
+	}
+	if len(turns) != 1 || turns[0].Prompt != "why?" || turns[0].Model != "claude-3-5-sonnet" {
+		t.Fatalf("unexpected second session turns: %+v", turns)
+	}
+}
+
+func TestParseAiderSessionsInTheSameSecond(t *testing.T) {
+	history := `# aider chat started at 2024-06-01 09:15:02
+
+#### first
+
+one
+
+# aider chat started at 2024-06-01 09:15:02
+
+#### second
+
+two
+
+# aider chat started at sometime
+
+#### third
+
+three
+`
+	conversations, err := ParseAider(strings.NewReader(history))
+	if err != nil {
+		t.Fatalf("ParseAider: %v", err)
+	}
+	if len(conversations) != 3 || conversations[0].ID == conversations[1].ID || conversations[0].CreatedAt != conversations[1].CreatedAt {
+		t.Fatalf("expected distinct ids for sessions started together, got %+v", conversations)
+	}
+	if conversations[2].CreatedAt != "" || len(conversations[2].ID) != 8 {
+		t.Fatalf("expected an unparseable start to leave only the content id, got %+v", conversations[2])
+	}
+
+	// Appending to a session keeps its id.
+	longer, err := ParseAider(strings.NewReader(history + "\n#### fourth\n\nfour\n"))
+	if err != nil {
+		t.Fatalf("ParseAider: %v", err)
+	}
+	if longer[2].ID != conversations[2].ID {
+		t.Fatalf("expected a growing session to keep its id, got %s and %s", conversations[2].ID, longer[2].ID)
+	}
+}
+
+func TestConversationDigestsAreSeededByID(t *testing.T) {
+	messages := []Message{{Role: "user", Content: "hi"}, {Role: "assistant", Content: "hello"}}
+	first, _, _ := Conversation{ID: "a", Messages: messages}.Turns()
+	second, _, _ := Conversation{ID: "b", Messages: messages}.Turns()
+	if first[0].Digest == second[0].Digest {
+		t.Fatal("expected identical openings in different conversations to have different digests")
+	}
+}
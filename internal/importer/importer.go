@@ -0,0 +1,205 @@
+// Package importer converts exported chat conversations into prompt/response turns
+// that can be stored as intent records.
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Message roles recognized when pairing turns.
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleSystem    = "system"
+	RoleTool      = "tool"
+)
+
+// Message is one chat message in a conversation.
+type Message struct {
+	Role    string
+	Content string
+	// CreatedAt is the original timestamp as RFC 3339 text, when the export has one.
+	CreatedAt string
+	// Model names the model that wrote an assistant message, when the export has one.
+	Model string
+}
+
+// Conversation is one exported chat with the metadata the export carried for it.
+type Conversation struct {
+	ID        string
+	Title     string
+	CreatedAt string
+	Model     string
+	Messages  []Message
+}
+
+// Turn is a user prompt paired with the assistant response that followed it.
+type Turn struct {
+	// Index is the 1-based position of the turn in its conversation.
+	Index    int
+	Prompt   string
+	Response string
+	// PromptedAt and RespondedAt are the original timestamps of the first prompt
+	// message and the last response message, when known.
+	PromptedAt  string
+	RespondedAt string
+	Model       string
+	// Digest identifies the turn by its conversation, its content, and every turn before it.
+	Digest string
+}
+
+// Turns pairs the conversation's messages into turns. Turns without a message-level
+// model inherit the conversation model.
+func (c Conversation) Turns() ([]Turn, int, error) {
+	turns, unpaired, err := pairTurns(c.ID, c.Messages)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range turns {
+		if turns[i].Model == "" {
+			turns[i].Model = c.Model
+		}
+	}
+	return turns, unpaired, nil
+}
+
+// PairTurns groups messages into user/assistant turns. Consecutive messages from
+// the same role are joined with a blank line; system and tool messages are ignored.
+// It also reports how many messages could not be paired, such as a trailing prompt
+// that has no response yet.
+func PairTurns(messages []Message) ([]Turn, int, error) {
+	return pairTurns("", messages)
+}
+
+// pairTurns implements PairTurns, seeding the digest chain with a conversation id
+// so identical openings in different conversations stay distinct.
+func pairTurns(seed string, messages []Message) ([]Turn, int, error) {
+	var (
+		turns    []Turn
+		prompt   []Message
+		response []Message
+		unpaired int
+		prev     = seed
+	)
+	flush := func() {
+		if len(prompt) == 0 || len(response) == 0 {
+			unpaired += len(prompt) + len(response)
+			prompt, response = nil, nil
+			return
+		}
+		turn := Turn{
+			Index:       len(turns) + 1,
+			Prompt:      joinContent(prompt),
+			Response:    joinContent(response),
+			PromptedAt:  firstTimestamp(prompt),
+			RespondedAt: lastTimestamp(response),
+			Model:       lastModel(response),
+		}
+		turn.Digest = turnDigest(prev, turn.Prompt, turn.Response)
+		prev = turn.Digest
+		turns = append(turns, turn)
+		prompt, response = nil, nil
+	}
+
+	for i, message := range messages {
+		message.Content = trimText(message.Content)
+		switch normalizeRole(message.Role) {
+		case RoleUser:
+			if len(response) > 0 {
+				flush()
+			}
+			if message.Content != "" {
+				prompt = append(prompt, message)
+			}
+		case RoleAssistant:
+			if len(prompt) == 0 {
+				if message.Content != "" {
+					unpaired++
+				}
+				continue
+			}
+			if message.Content != "" {
+				response = append(response, message)
+			}
+		case RoleSystem, RoleTool:
+			continue
+		default:
+			return nil, 0, fmt.Errorf("message %d: unsupported role %q", i+1, message.Role)
+		}
+	}
+	flush()
+	return turns, unpaired, nil
+}
+
+func joinContent(messages []Message) string {
+	parts := make([]string, 0, len(messages))
+	for _, message := range messages {
+		parts = append(parts, message.Content)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func firstTimestamp(messages []Message) string {
+	for _, message := range messages {
+		if message.CreatedAt != "" {
+			return message.CreatedAt
+		}
+	}
+	return ""
+}
+
+func lastTimestamp(messages []Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].CreatedAt != "" {
+			return messages[i].CreatedAt
+		}
+	}
+	return ""
+}
+
+func lastModel(messages []Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Model != "" {
+			return messages[i].Model
+		}
+	}
+	return ""
+}
+
+// normalizeRole maps role spellings used by common exports onto the canonical roles.
+func normalizeRole(role string) string {
+	switch strings.ToLower(strings.TrimSpace(role)) {
+	case "user", "human":
+		return RoleUser
+	case "assistant", "ai", "model":
+		return RoleAssistant
+	case "system", "developer":
+		return RoleSystem
+	case "tool", "function":
+		return RoleTool
+	default:
+		return role
+	}
+}
+
+// turnDigest hashes a turn together with the digest of the turn before it, so the
+// same exchange at a different point in a conversation gets a different digest.
+func turnDigest(prev, prompt, response string) string {
+	payload, _ := json.Marshal(struct {
+		Prev     string `json:"prev"`
+		Prompt   string `json:"prompt"`
+		Response string `json:"response"`
+	}{prev, prompt, response})
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// trimText applies the capture rule: trailing whitespace is removed and nothing else.
+func trimText(text string) string {
+	return strings.TrimRightFunc(text, unicode.IsSpace)
+}
@@ -0,0 +1,114 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// transcriptMessage is the wire form of a transcript message.
+type transcriptMessage struct {
+	Role      string          `json:"role"`
+	Content   json.RawMessage `json:"content"`
+	CreatedAt string          `json:"created_at"`
+	Model     string          `json:"model"`
+}
+
+// ParseTranscript reads chat messages from either a JSON array or NDJSON (one
+// message object per line). Each message has a role and content; content may be
+// a string or an array of parts whose text fields are joined. Optional created_at
+// and model fields are kept.
+func ParseTranscript(r io.Reader) ([]Message, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read transcript: %w", err)
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("transcript is empty")
+	}
+
+	var raw []transcriptMessage
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("decode transcript: %w", err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+		line := 0
+		for scanner.Scan() {
+			line++
+			text := bytes.TrimSpace(scanner.Bytes())
+			if len(text) == 0 {
+				continue
+			}
+			var message transcriptMessage
+			if err := json.Unmarshal(text, &message); err != nil {
+				return nil, fmt.Errorf("decode transcript line %d: %w", line, err)
+			}
+			raw = append(raw, message)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("read transcript: %w", err)
+		}
+	}
+
+	messages := make([]Message, 0, len(raw))
+	for i, message := range raw {
+		if message.Role == "" {
+			return nil, fmt.Errorf("message %d: role is required", i+1)
+		}
+		content, err := decodeContent(message.Content)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i+1, err)
+		}
+		messages = append(messages, Message{
+			Role:      message.Role,
+			Content:   content,
+			CreatedAt: message.CreatedAt,
+			Model:     message.Model,
+		})
+	}
+	return messages, nil
+}
+
+// decodeContent accepts content as a string, null, or an array of strings or
+// {"text": "..."} parts, joining parts with a blank line.
+func decodeContent(raw json.RawMessage) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return "", nil
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, nil
+	}
+
+	var parts []json.RawMessage
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return "", errors.New("content must be a string or an array of parts")
+	}
+	var texts [][]byte
+	for _, part := range parts {
+		var value string
+		if err := json.Unmarshal(part, &value); err == nil {
+			texts = append(texts, []byte(value))
+			continue
+		}
+		var object struct {
+			Text *string `json:"text"`
+		}
+		if err := json.Unmarshal(part, &object); err != nil {
+			return "", errors.New("content parts must be strings or objects")
+		}
+		if object.Text != nil {
+			texts = append(texts, []byte(*object.Text))
+		}
+	}
+	return string(bytes.Join(texts, []byte("\n\n"))), nil
+}
@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTranscriptFormats(t *testing.T) {
+	array := `[{"role":"user","content":"hi"},{"role":"assistant","content":[{"type":"text","text":"hello"},"there"]}]`
+	ndjson := "{\"role\":\"user\",\"content\":\"hi\"}\n\n{\"role\":\"assistant\",\"content\":[{\"type\":\"text\",\"text\":\"hello\"},\"there\"]}\n"
+
+	for name, input := range map[string]string{"array": array, "ndjson": ndjson} {
+		messages, err := ParseTranscript(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("%s: ParseTranscript: %v", name, err)
+		}
+		if len(messages) != 2 {
+			t.Fatalf("%s: expected 2 messages, got %d", name, len(messages))
+		}
+		if messages[1].Content != "hello\n\nthere" {
+			t.Fatalf("%s: expected joined parts, got %q", name, messages[1].Content)
+		}
+	}
+}
+
+func TestParseTranscriptReportsLine(t *testing.T) {
+	_, err := ParseTranscript(strings.NewReader("{\"role\":\"user\",\"content\":\"hi\"}\n{bad\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected line 2 error, got %v", err)
+	}
+}
+
+func TestPairTurns(t *testing.T) {
+	messages := []Message{
+		{Role: "system", Content: "be brief"},
+		{Role: "assistant", Content: "greeting before any prompt"},
+		{Role: "user", Content: "part one"},
+		{Role: "user", Content: "part two  \n"},
+		{Role: "assistant", Content: "answer"},
+		{Role: "tool", Content: "ignored"},
+		{Role: "assistant", Content: "more"},
+		{Role: "human", Content: "second"},
+		{Role: "ai", Content: "reply"},
+		{Role: "user", Content: "unanswered"},
+	}
+
+	turns, unpaired, err := PairTurns(messages)
+	if err != nil {
+		t.Fatalf("PairTurns: %v", err)
+	}
+	if unpaired != 2 {
+		t.Fatalf("expected 2 unpaired messages, got %d", unpaired)
+	}
+	if len(turns) != 2 {
+		t.Fatalf("expected 2 turns, got %d", len(turns))
+	}
+	if turns[0].Prompt != "part one\n\npart two" || turns[0].Response != "answer\n\nmore" {
+		t.Fatalf("unexpected first turn: %+v", turns[0])
+	}
+	if turns[1].Index != 2 || turns[1].Prompt != "second" || turns[1].Response != "reply" {
+		t.Fatalf("unexpected second turn: %+v", turns[1])
+	}
+}
+
+func TestPairTurnsDigestDependsOnHistory(t *testing.T) {
+	first, _, err := PairTurns([]Message{
+		{Role: "user", Content: "a"}, {Role: "assistant", Content: "b"},
+		{Role: "user", Content: "c"}, {Role: "assistant", Content: "d"},
+	})
+	if err != nil {
+		t.Fatalf("PairTurns: %v", err)
+	}
+	again, _, err := PairTurns([]Message{
+		{Role: "user", Content: "a"}, {Role: "assistant", Content: "b"},
+		{Role: "user", Content: "c"}, {Role: "assistant", Content: "d"},
+	})
+	if err != nil {
+		t.Fatalf("PairTurns: %v", err)
+	}
+	alone, _, err := PairTurns([]Message{{Role: "user", Content: "c"}, {Role: "assistant", Content: "d"}})
+	if err != nil {
+		t.Fatalf("PairTurns: %v", err)
+	}
+
+	if first[1].Digest != again[1].Digest {
+		t.Fatal("expected identical transcripts to produce identical digests")
+	}
+	if first[1].Digest == alone[0].Digest {
+		t.Fatal("expected a turn's digest to depend on the turns before it")
+	}
+}
+
+func TestPairTurnsRejectsUnknownRole(t *testing.T) {
+	_, _, err := PairTurns([]Message{{Role: "narrator", Content: "x"}})
+	if err == nil || !strings.Contains(err.Error(), "unsupported role") {
+		t.Fatalf("expected unsupported role error, got %v", err)
+	}
+}
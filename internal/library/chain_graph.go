@@ -0,0 +1,208 @@
+package yanzilibrary
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// MergeParentsMetaKey is the intent meta key listing the hashes a merge capture joins in
+// addition to its prev_hash. It is part of the hashed meta, so the extra parents are
+// covered by the intent hash.
+const MergeParentsMetaKey = "merge_parents"
+
+// ChainNode is one intent in a project's chain graph. Parents lists the prev_hash first,
+// then any merge parents; Children lists the intents that name this one as a parent.
+type ChainNode struct {
+	ID        string   `json:"id"`
+	Hash      string   `json:"hash"`
+	CreatedAt string   `json:"created_at"`
+	Author    string   `json:"author"`
+	Title     string   `json:"title,omitempty"`
+	Parents   []string `json:"parents"`
+	Children  []string `json:"children"`
+}
+
+// ChainFork is an intent that more than one intent names as a parent.
+type ChainFork struct {
+	Hash     string   `json:"hash"`
+	Children []string `json:"children"`
+}
+
+// ChainGraph is the DAG formed by a project's chained intents. Intents stored outside
+// the chain, with no parents and no children, are left out.
+type ChainGraph struct {
+	Project string `json:"project"`
+	// RecordedHead is the head tracked for the project in chain_heads, if any.
+	RecordedHead string `json:"recorded_head,omitempty"`
+	// Nodes are ordered oldest first.
+	Nodes []ChainNode `json:"nodes"`
+	// Heads are the nodes without children, newest first. More than one means the
+	// history has diverged.
+	Heads  []string    `json:"heads"`
+	Roots  []string    `json:"roots"`
+	Forks  []ChainFork `json:"forks"`
+	Merges []string    `json:"merges"`
+	// CommonAncestor is the newest intent every head descends from, set when there is
+	// more than one head and they share an ancestor.
+	CommonAncestor string `json:"common_ancestor,omitempty"`
+	// MissingParents are parent hashes that are not intents of the project.
+	MissingParents []string `json:"missing_parents,omitempty"`
+}
+
+// IntentParents returns an intent's parent hashes: its prev_hash followed by the merge
+// parents listed in its meta, without duplicates.
+func IntentParents(prevHash string, meta json.RawMessage) []string {
+	var parents []string
+	seen := map[string]bool{"": true}
+	add := func(hash string) {
+		hash = strings.TrimSpace(hash)
+		if !seen[hash] {
+			seen[hash] = true
+			parents = append(parents, hash)
+		}
+	}
+	add(prevHash)
+	if len(meta) > 0 {
+		var fields struct {
+			MergeParents []string `json:"merge_parents"`
+		}
+		if err := json.Unmarshal(meta, &fields); err == nil {
+			for _, hash := range fields.MergeParents {
+				add(hash)
+			}
+		}
+	}
+	return parents
+}
+
+// BuildChainGraph loads every intent of a project and returns the graph their parent
+// links form, with its heads, forks, merges, and the common ancestor of diverged heads.
+func BuildChainGraph(ctx context.Context, db *sql.DB, project string) (ChainGraph, error) {
+	project = strings.TrimSpace(project)
+	if project == "" {
+		return ChainGraph{}, errors.New("project is required")
+	}
+	graph := ChainGraph{
+		Project: project,
+		Nodes:   []ChainNode{},
+		Heads:   []string{},
+		Roots:   []string{},
+		Forks:   []ChainFork{},
+		Merges:  []string{},
+	}
+	err := db.QueryRowContext(ctx, `SELECT head_hash FROM chain_heads WHERE project = ?`, project).Scan(&graph.RecordedHead)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return ChainGraph{}, err
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT id, hash, created_at, author, title, meta, prev_hash
+		FROM intents WHERE project = ? ORDER BY created_at ASC, id ASC`, project)
+	if err != nil {
+		return ChainGraph{}, err
+	}
+	defer rows.Close()
+	var all []ChainNode
+	index := make(map[string]int)
+	for rows.Next() {
+		var node ChainNode
+		var title, meta, prevHash sql.NullString
+		if err := rows.Scan(&node.ID, &node.Hash, &node.CreatedAt, &node.Author, &title, &meta, &prevHash); err != nil {
+			return ChainGraph{}, err
+		}
+		node.Title = title.String
+		node.Parents = IntentParents(prevHash.String, json.RawMessage(meta.String))
+		node.Children = []string{}
+		index[node.Hash] = len(all)
+		all = append(all, node)
+	}
+	if err := rows.Err(); err != nil {
+		return ChainGraph{}, err
+	}
+
+	missing := map[string]bool{}
+	for _, node := range all {
+		for _, parent := range node.Parents {
+			i, ok := index[parent]
+			if !ok {
+				missing[parent] = true
+				continue
+			}
+			all[i].Children = append(all[i].Children, node.Hash)
+		}
+	}
+
+	kept := make(map[string]int)
+	for _, node := range all {
+		if len(node.Parents) == 0 && len(node.Children) == 0 && node.Hash != graph.RecordedHead {
+			continue
+		}
+		kept[node.Hash] = len(graph.Nodes)
+		graph.Nodes = append(graph.Nodes, node)
+		if len(node.Children) == 0 {
+			graph.Heads = append(graph.Heads, node.Hash)
+		}
+		if !hasKnownParent(node, index) {
+			graph.Roots = append(graph.Roots, node.Hash)
+		}
+		if len(node.Children) > 1 {
+			graph.Forks = append(graph.Forks, ChainFork{Hash: node.Hash, Children: node.Children})
+		}
+		if len(node.Parents) > 1 {
+			graph.Merges = append(graph.Merges, node.Hash)
+		}
+	}
+	for i, j := 0, len(graph.Heads)-1; i < j; i, j = i+1, j-1 {
+		graph.Heads[i], graph.Heads[j] = graph.Heads[j], graph.Heads[i]
+	}
+	for hash := range missing {
+		graph.MissingParents = append(graph.MissingParents, hash)
+	}
+	sort.Strings(graph.MissingParents)
+	if len(graph.Heads) > 1 {
+		graph.CommonAncestor = commonAncestor(graph.Nodes, kept, graph.Heads)
+	}
+	return graph, nil
+}
+
+// hasKnownParent reports whether any of the node's parents is an intent of the project.
+func hasKnownParent(node ChainNode, index map[string]int) bool {
+	for _, parent := range node.Parents {
+		if _, ok := index[parent]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// commonAncestor returns the newest node that is an ancestor of, or equal to, every head.
+func commonAncestor(nodes []ChainNode, index map[string]int, heads []string) string {
+	counts := make([]int, len(nodes))
+	for _, head := range heads {
+		seen := make(map[int]bool)
+		stack := []int{index[head]}
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if seen[i] {
+				continue
+			}
+			seen[i] = true
+			counts[i]++
+			for _, parent := range nodes[i].Parents {
+				if j, ok := index[parent]; ok {
+					stack = append(stack, j)
+				}
+			}
+		}
+	}
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if counts[i] == len(heads) {
+			return nodes[i].Hash
+		}
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package yanzilibrary
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBuildChainGraphForksAndMerges(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(envDBPath, filepath.Join(t.TempDir(), "yanzi.db"))
+	db, err := InitDB()
+	if err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	defer db.Close()
+
+	insert := func(id, createdAt, hash, prevHash, meta string) {
+		t.Helper()
+		var prev any
+		if prevHash != "" {
+			prev = prevHash
+		}
+		if _, err := db.Exec(`INSERT INTO intents (id, created_at, author, source_type, prompt, response, meta, prev_hash, hash)
+			VALUES (?, ?, 'Ada', 'cli', 'p', 'r', ?, ?, ?)`, id, createdAt, meta, prev, hash); err != nil {
+			t.Fatalf("insert %s: %v", id, err)
+		}
+	}
+	alpha := `{"project":"alpha"}`
+	insert("a", "2026-01-01T00:00:00Z", "ha", "", alpha)
+	insert("b", "2026-01-02T00:00:00Z", "hb", "ha", alpha)
+	insert("c", "2026-01-03T00:00:00Z", "hc", "ha", alpha)
+	insert("loose", "2026-01-04T00:00:00Z", "hloose", "", alpha)
+	insert("other", "2026-01-04T00:00:00Z", "hother", "hb", `{"project":"beta"}`)
+	if _, err := db.Exec(`INSERT INTO chain_heads (project, head_hash, updated_at) VALUES ('alpha', 'hc', '2026-01-03T00:00:00Z')`); err != nil {
+		t.Fatalf("insert head: %v", err)
+	}
+	ctx := context.Background()
+
+	graph, err := BuildChainGraph(ctx, db, "alpha")
+	if err != nil {
+		t.Fatalf("BuildChainGraph: %v", err)
+	}
+	if len(graph.Nodes) != 3 || graph.RecordedHead != "hc" {
+		t.Fatalf("expected three chained nodes, got %+v", graph)
+	}
+	if !reflect.DeepEqual(graph.Heads, []string{"hc", "hb"}) || !reflect.DeepEqual(graph.Roots, []string{"ha"}) {
+		t.Fatalf("unexpected heads or roots: %+v %+v", graph.Heads, graph.Roots)
+	}
+	if len(graph.Forks) != 1 || graph.Forks[0].Hash != "ha" || !reflect.DeepEqual(graph.Forks[0].Children, []string{"hb", "hc"}) {
+		t.Fatalf("expected a fork at ha, got %+v", graph.Forks)
+	}
+	if graph.CommonAncestor != "ha" || len(graph.Merges) != 0 {
+		t.Fatalf("expected common ancestor ha and no merges, got %+v", graph)
+	}
+
+	insert("d", "2026-01-05T00:00:00Z", "hd", "hc", `{"project":"alpha","merge_parents":["hb","hc","hmissing"]}`)
+	graph, err = BuildChainGraph(ctx, db, "alpha")
+	if err != nil {
+		t.Fatalf("BuildChainGraph: %v", err)
+	}
+	if !reflect.DeepEqual(graph.Heads, []string{"hd"}) || !reflect.DeepEqual(graph.Merges, []string{"hd"}) || graph.CommonAncestor != "" {
+		t.Fatalf("expected the merge to leave one head, got %+v", graph)
+	}
+	if last := graph.Nodes[len(graph.Nodes)-1]; !reflect.DeepEqual(last.Parents, []string{"hc", "hb", "hmissing"}) {
+		t.Fatalf("expected prev_hash first and duplicates dropped, got %v", last.Parents)
+	}
+	if !reflect.DeepEqual(graph.MissingParents, []string{"hmissing"}) {
+		t.Fatalf("expected the missing merge parent, got %v", graph.MissingParents)
+	}
+}
@@ -1,6 +1,7 @@
 package yanzilibrary
 
 import (
+	"encoding/json"
 	"strings"
 	"time"
 )
@@ -12,7 +13,13 @@ type Checkpoint struct {
 	CreatedAt            string   `json:"created_at"`
 	ArtifactIDs          []string `json:"artifact_ids"`
 	PreviousCheckpointID string   `json:"previous_checkpoint_id,omitempty"`
-	Hash                 string   `json:"hash"`
+	// Meta is an optional JSON object; it is hashed after previous_checkpoint_id when present.
+	Meta json.RawMessage `json:"meta,omitempty"`
+	Hash string          `json:"hash"`
+	// KeyID and Signature sign Hash when the checkpoint was created with a signing key.
+	// They are stored beside the checkpoint and are not part of its hash.
+	KeyID     string `json:"key_id,omitempty"`
+	Signature string `json:"signature,omitempty"`
 }
 
 // CheckpointValidationError reports invalid checkpoint input.
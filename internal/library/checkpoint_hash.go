@@ -7,6 +7,8 @@ import (
 	"errors"
 	"strings"
 	"time"
+
+	"github.com/chuxorg/chux-yanzi-cli/internal/core/hash"
 )
 
 // HashCheckpoint computes a deterministic SHA-256 hash for a Checkpoint.
@@ -47,6 +49,14 @@ func canonicalCheckpointPreimage(checkpoint Checkpoint) ([]byte, error) {
 		return nil, err
 	}
 
+	var meta json.RawMessage
+	if len(checkpoint.Meta) > 0 {
+		meta, err = hash.CanonicalizeMeta(checkpoint.Meta)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var b strings.Builder
 	b.WriteByte('{')
 	first := true
@@ -58,6 +68,9 @@ func canonicalCheckpointPreimage(checkpoint Checkpoint) ([]byte, error) {
 	if checkpoint.PreviousCheckpointID != "" {
 		addStringField(&b, &first, "previous_checkpoint_id", checkpoint.PreviousCheckpointID)
 	}
+	if len(meta) > 0 {
+		addRawField(&b, &first, "meta", meta)
+	}
 	b.WriteByte('}')
 
 	return []byte(b.String()), nil
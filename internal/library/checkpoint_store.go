@@ -5,13 +5,18 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 )
 
+// CheckpointSigner signs a checkpoint hash and returns the signing key id and signature.
+type CheckpointSigner func(hash string) (keyID, signature string, err error)
+
 // CheckpointStore provides persistence for checkpoints.
 type CheckpointStore struct {
-	db *sql.DB
+	db     *sql.DB
+	signer CheckpointSigner
 }
 
 // NewCheckpointStore constructs a CheckpointStore using the provided database handle.
@@ -19,8 +24,19 @@ func NewCheckpointStore(db *sql.DB) *CheckpointStore {
 	return &CheckpointStore{db: db}
 }
 
+// NewSigningCheckpointStore constructs a CheckpointStore that signs every checkpoint it
+// creates with signer.
+func NewSigningCheckpointStore(db *sql.DB, signer CheckpointSigner) *CheckpointStore {
+	return &CheckpointStore{db: db, signer: signer}
+}
+
 // CreateCheckpoint creates a new checkpoint artifact for a project.
 func (s *CheckpointStore) CreateCheckpoint(ctx context.Context, project, summary string, artifactIDs []string) (Checkpoint, error) {
+	return s.CreateCheckpointWithMeta(ctx, project, summary, artifactIDs, nil)
+}
+
+// CreateCheckpointWithMeta creates a new checkpoint artifact carrying an optional meta object.
+func (s *CheckpointStore) CreateCheckpointWithMeta(ctx context.Context, project, summary string, artifactIDs []string, meta json.RawMessage) (Checkpoint, error) {
 	if s == nil || s.db == nil {
 		return Checkpoint{}, errors.New("checkpoint store is not initialized")
 	}
@@ -54,6 +70,7 @@ func (s *CheckpointStore) CreateCheckpoint(ctx context.Context, project, summary
 		CreatedAt:            createdAt,
 		ArtifactIDs:          artifactIDs,
 		PreviousCheckpointID: previousID,
+		Meta:                 meta,
 	}
 	checkpoint = checkpoint.Normalize()
 
@@ -62,6 +79,12 @@ func (s *CheckpointStore) CreateCheckpoint(ctx context.Context, project, summary
 		return Checkpoint{}, err
 	}
 	checkpoint.Hash = hashValue
+	if s.signer != nil {
+		checkpoint.KeyID, checkpoint.Signature, err = s.signer(hashValue)
+		if err != nil {
+			return Checkpoint{}, fmt.Errorf("sign checkpoint: %w", err)
+		}
+	}
 
 	storedIDs := checkpoint.ArtifactIDs
 	if storedIDs == nil {
@@ -76,17 +99,24 @@ func (s *CheckpointStore) CreateCheckpoint(ctx context.Context, project, summary
 	if checkpoint.PreviousCheckpointID != "" {
 		prev = checkpoint.PreviousCheckpointID
 	}
+	var storedMeta any
+	if len(checkpoint.Meta) > 0 {
+		storedMeta = string(checkpoint.Meta)
+	}
 
 	_, err = s.db.ExecContext(
 		ctx,
-		`INSERT INTO checkpoints (hash, project, summary, created_at, artifact_ids, previous_checkpoint_id)
-		VALUES (?, ?, ?, ?, ?, ?)`,
+		`INSERT INTO checkpoints (hash, project, summary, created_at, artifact_ids, previous_checkpoint_id, meta, key_id, signature)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		checkpoint.Hash,
 		checkpoint.Project,
 		checkpoint.Summary,
 		checkpoint.CreatedAt,
 		string(artifactJSON),
 		prev,
+		storedMeta,
+		nullableString(checkpoint.KeyID),
+		nullableString(checkpoint.Signature),
 	)
 	if err != nil {
 		return Checkpoint{}, err
@@ -114,7 +144,7 @@ func (s *CheckpointStore) ListCheckpoints(ctx context.Context, project string) (
 		return nil, ProjectNotFoundError{Name: project}
 	}
 
-	rows, err := s.db.QueryContext(ctx, `SELECT hash, project, summary, created_at, artifact_ids, previous_checkpoint_id FROM checkpoints WHERE project = ? ORDER BY created_at DESC`, project)
+	rows, err := s.db.QueryContext(ctx, `SELECT `+CheckpointColumns+` FROM checkpoints WHERE project = ? ORDER BY created_at DESC`, project)
 	if err != nil {
 		return nil, err
 	}
@@ -122,27 +152,10 @@ func (s *CheckpointStore) ListCheckpoints(ctx context.Context, project string) (
 
 	checkpoints := []Checkpoint{}
 	for rows.Next() {
-		var checkpoint Checkpoint
-		var artifactText string
-		var prev sql.NullString
-		if err := rows.Scan(
-			&checkpoint.Hash,
-			&checkpoint.Project,
-			&checkpoint.Summary,
-			&checkpoint.CreatedAt,
-			&artifactText,
-			&prev,
-		); err != nil {
+		checkpoint, err := ScanCheckpoint(rows)
+		if err != nil {
 			return nil, err
 		}
-		if artifactText != "" {
-			if err := json.Unmarshal([]byte(artifactText), &checkpoint.ArtifactIDs); err != nil {
-				return nil, err
-			}
-		}
-		if prev.Valid {
-			checkpoint.PreviousCheckpointID = prev.String
-		}
 		checkpoints = append(checkpoints, checkpoint)
 	}
 	if err := rows.Err(); err != nil {
@@ -156,11 +169,55 @@ func CreateCheckpoint(ctx context.Context, db *sql.DB, project, summary string,
 	return NewCheckpointStore(db).CreateCheckpoint(ctx, project, summary, artifactIDs)
 }
 
+// CreateCheckpointWithMeta is a convenience wrapper for CheckpointStore.CreateCheckpointWithMeta.
+func CreateCheckpointWithMeta(ctx context.Context, db *sql.DB, project, summary string, artifactIDs []string, meta json.RawMessage) (Checkpoint, error) {
+	return NewCheckpointStore(db).CreateCheckpointWithMeta(ctx, project, summary, artifactIDs, meta)
+}
+
 // ListCheckpoints is a convenience wrapper for CheckpointStore.ListCheckpoints.
 func ListCheckpoints(ctx context.Context, db *sql.DB, project string) ([]Checkpoint, error) {
 	return NewCheckpointStore(db).ListCheckpoints(ctx, project)
 }
 
+// CheckpointColumns is the checkpoints column list read by ScanCheckpoint.
+const CheckpointColumns = `hash, project, summary, created_at, artifact_ids, previous_checkpoint_id, meta, key_id, signature`
+
+// ScanCheckpoint reads one checkpoint row selected with CheckpointColumns.
+func ScanCheckpoint(row interface{ Scan(dest ...any) error }) (Checkpoint, error) {
+	var checkpoint Checkpoint
+	var artifactText string
+	var prev sql.NullString
+	var meta sql.NullString
+	var keyID, signature sql.NullString
+	if err := row.Scan(
+		&checkpoint.Hash,
+		&checkpoint.Project,
+		&checkpoint.Summary,
+		&checkpoint.CreatedAt,
+		&artifactText,
+		&prev,
+		&meta,
+		&keyID,
+		&signature,
+	); err != nil {
+		return Checkpoint{}, err
+	}
+	if artifactText != "" {
+		if err := json.Unmarshal([]byte(artifactText), &checkpoint.ArtifactIDs); err != nil {
+			return Checkpoint{}, fmt.Errorf("decode artifact_ids for checkpoint %s: %w", checkpoint.Hash, err)
+		}
+	}
+	if prev.Valid {
+		checkpoint.PreviousCheckpointID = prev.String
+	}
+	if meta.Valid && meta.String != "" {
+		checkpoint.Meta = json.RawMessage(meta.String)
+	}
+	checkpoint.KeyID = keyID.String
+	checkpoint.Signature = signature.String
+	return checkpoint, nil
+}
+
 // latestCheckpointID returns the most recent checkpoint hash for a project, or empty if none exist.
 func (s *CheckpointStore) latestCheckpointID(ctx context.Context, project string) (string, error) {
 	var hash string
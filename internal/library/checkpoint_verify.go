@@ -0,0 +1,141 @@
+package yanzilibrary
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// Checkpoint problem kinds reported by VerifyCheckpoint.
+const (
+	// CheckpointMismatch reports a stored hash that differs from the recomputed one.
+	CheckpointMismatch = "mismatch"
+	// CheckpointMissingLink reports a previous checkpoint or artifact that does not resolve.
+	CheckpointMissingLink = "missing_link"
+	// CheckpointOrphan reports a checkpoint whose project does not exist.
+	CheckpointOrphan = "orphan"
+)
+
+// CheckpointProblem is one integrity problem found in a checkpoint.
+type CheckpointProblem struct {
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+// CheckpointVerification is the result of recomputing a checkpoint's hash and resolving
+// its links.
+type CheckpointVerification struct {
+	Hash                 string `json:"hash"`
+	Project              string `json:"project"`
+	CreatedAt            string `json:"created_at"`
+	Summary              string `json:"summary"`
+	PreviousCheckpointID string `json:"previous_checkpoint_id,omitempty"`
+	ComputedHash         string `json:"computed_hash"`
+	KeyID                string `json:"key_id,omitempty"`
+	// Signer is the owner of the trusted key that signed the checkpoint. It is set by
+	// callers that check signatures.
+	Signer   string              `json:"signer,omitempty"`
+	Valid    bool                `json:"valid"`
+	Problems []CheckpointProblem `json:"problems"`
+}
+
+// GetCheckpoint loads a checkpoint by hash. It returns sql.ErrNoRows when none matches.
+func GetCheckpoint(ctx context.Context, db *sql.DB, hash string) (Checkpoint, error) {
+	return ScanCheckpoint(db.QueryRowContext(ctx, `SELECT `+CheckpointColumns+` FROM checkpoints WHERE hash = ?`, hash))
+}
+
+// LatestCheckpointHash returns the hash of a project's newest checkpoint, or "" when it
+// has none.
+func LatestCheckpointHash(ctx context.Context, db *sql.DB, project string) (string, error) {
+	return NewCheckpointStore(db).latestCheckpointID(ctx, strings.TrimSpace(project))
+}
+
+// VerifyCheckpoint recomputes a checkpoint's hash from its stored fields and checks that
+// its project exists, that its previous checkpoint resolves within the same project, and
+// that every artifact id names a stored intent. An error is returned only when the
+// database cannot be read.
+func VerifyCheckpoint(ctx context.Context, db *sql.DB, checkpoint Checkpoint) (CheckpointVerification, error) {
+	result := CheckpointVerification{
+		Hash:                 checkpoint.Hash,
+		Project:              checkpoint.Project,
+		CreatedAt:            checkpoint.CreatedAt,
+		Summary:              checkpoint.Summary,
+		PreviousCheckpointID: checkpoint.PreviousCheckpointID,
+		KeyID:                checkpoint.KeyID,
+		Problems:             []CheckpointProblem{},
+	}
+	report := func(kind, detail string) {
+		result.Problems = append(result.Problems, CheckpointProblem{Kind: kind, Detail: detail})
+	}
+
+	computed, err := HashCheckpoint(checkpoint)
+	switch {
+	case err != nil:
+		report(CheckpointMismatch, "compute hash: "+err.Error())
+	case computed != checkpoint.Hash:
+		report(CheckpointMismatch, "stored hash "+checkpoint.Hash+", computed "+computed)
+	}
+	result.ComputedHash = computed
+
+	exists, err := projectExists(ctx, db, checkpoint.Project)
+	if err != nil {
+		return CheckpointVerification{}, err
+	}
+	if !exists {
+		report(CheckpointOrphan, "project "+checkpoint.Project+" not found")
+	}
+
+	if previousID := checkpoint.PreviousCheckpointID; previousID != "" {
+		var project string
+		err := db.QueryRowContext(ctx, `SELECT project FROM checkpoints WHERE hash = ?`, previousID).Scan(&project)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			report(CheckpointMissingLink, "previous_checkpoint_id "+previousID+" not found")
+		case err != nil:
+			return CheckpointVerification{}, err
+		case project != checkpoint.Project:
+			report(CheckpointMissingLink, "previous_checkpoint_id "+previousID+" belongs to project "+project)
+		}
+	}
+
+	for _, id := range checkpoint.ArtifactIDs {
+		var one int
+		err := db.QueryRowContext(ctx, `SELECT 1 FROM intents WHERE id = ?`, id).Scan(&one)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			report(CheckpointMissingLink, "artifact "+id+" not found")
+		case err != nil:
+			return CheckpointVerification{}, err
+		}
+	}
+
+	result.Valid = len(result.Problems) == 0
+	return result, nil
+}
+
+// CheckpointLineage returns the checkpoint with the given hash and every checkpoint
+// before it, newest first, following previous_checkpoint_id back to the genesis
+// checkpoint. The walk stops early at a previous checkpoint that is missing or belongs
+// to another project, in which case the last checkpoint returned names it, or at one
+// already visited.
+func CheckpointLineage(ctx context.Context, db *sql.DB, hash string) ([]Checkpoint, error) {
+	var lineage []Checkpoint
+	seen := make(map[string]bool)
+	for current := hash; current != "" && !seen[current]; {
+		seen[current] = true
+		checkpoint, err := GetCheckpoint(ctx, db, current)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) && len(lineage) > 0 {
+				break
+			}
+			return nil, err
+		}
+		if len(lineage) > 0 && checkpoint.Project != lineage[0].Project {
+			break
+		}
+		lineage = append(lineage, checkpoint)
+		current = checkpoint.PreviousCheckpointID
+	}
+	return lineage, nil
+}
@@ -186,6 +186,12 @@ func ensureSchemaVersion(ctx context.Context, db *sql.DB) (bool, error) {
 	return true, nil
 }
 
+// migrationHooks run in the same transaction as the migration they are keyed by, for
+// backfills that SQL alone cannot compute.
+var migrationHooks = map[string]func(context.Context, *sql.Tx) error{
+	"0010_create_project_events.sql": backfillProjectLedger,
+}
+
 // migrateDB applies embedded SQL migrations that have not yet been recorded.
 func migrateDB(ctx context.Context, db *sql.DB) error {
 	if db == nil {
@@ -228,6 +234,12 @@ func migrateDB(ctx context.Context, db *sql.DB) error {
 			_ = tx.Rollback()
 			return fmt.Errorf("apply migration %s: %w", version, err)
 		}
+		if hook := migrationHooks[version]; hook != nil {
+			if err := hook(ctx, tx); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("apply migration %s: %w", version, err)
+			}
+		}
 		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, version, time.Now().UTC().Format(time.RFC3339Nano)); err != nil {
 			_ = tx.Rollback()
 			return fmt.Errorf("record migration %s: %w", version, err)
@@ -43,6 +43,7 @@ func TestInitializeCreatesRuntimeState(t *testing.T) {
 	assertTableExists(t, db, "intents")
 	assertTableExists(t, db, "projects")
 	assertTableExists(t, db, "checkpoints")
+	assertTableExists(t, db, "chain_heads")
 
 	var version int
 	if err := db.QueryRow(`SELECT version FROM schema_version LIMIT 1`).Scan(&version); err != nil {
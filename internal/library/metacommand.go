@@ -0,0 +1,164 @@
+package yanzilibrary
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MetaCommandPrefix marks a line of agent text as a Yanzi meta-command.
+const MetaCommandPrefix = "@yanzi"
+
+// MetaCommandName identifies a supported meta-command.
+type MetaCommandName string
+
+// Supported meta-commands, as documented in docs/AGENT_BOOTSTRAP.md.
+const (
+	MetaCommandPause      MetaCommandName = "pause"
+	MetaCommandResume     MetaCommandName = "resume"
+	MetaCommandCheckpoint MetaCommandName = "checkpoint"
+	MetaCommandExport     MetaCommandName = "export"
+	MetaCommandRole       MetaCommandName = "role"
+)
+
+// MetaCommand is a single parsed @yanzi command.
+type MetaCommand struct {
+	Name     MetaCommandName
+	Argument string
+	Line     int
+}
+
+// MetaCommandError reports a line that starts with @yanzi but does not follow the grammar.
+type MetaCommandError struct {
+	Line    int
+	Text    string
+	Message string
+}
+
+// Error returns the parse failure including the line number when known.
+func (e MetaCommandError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: invalid meta-command %q: %s", e.Line, e.Text, e.Message)
+	}
+	return fmt.Sprintf("invalid meta-command %q: %s", e.Text, e.Message)
+}
+
+// String renders the command in canonical single-line form.
+func (c MetaCommand) String() string {
+	switch c.Name {
+	case MetaCommandCheckpoint:
+		return MetaCommandPrefix + " " + string(c.Name) + " " + strconv.Quote(c.Argument)
+	case MetaCommandRole:
+		return MetaCommandPrefix + " " + string(c.Name) + " " + c.Argument
+	default:
+		return MetaCommandPrefix + " " + string(c.Name)
+	}
+}
+
+// NewMetaCommand validates a command name and argument without the @yanzi prefix.
+func NewMetaCommand(name, argument string) (MetaCommand, error) {
+	command := MetaCommand{Name: MetaCommandName(strings.TrimSpace(name)), Argument: strings.TrimSpace(argument)}
+	switch command.Name {
+	case MetaCommandPause, MetaCommandResume, MetaCommandExport:
+		if command.Argument != "" {
+			return MetaCommand{}, fmt.Errorf("%s takes no arguments", command.Name)
+		}
+	case MetaCommandCheckpoint:
+		if command.Argument == "" {
+			return MetaCommand{}, errors.New(`checkpoint requires a summary: @yanzi checkpoint "Summary"`)
+		}
+		if strings.ContainsAny(command.Argument, "\r\n") {
+			return MetaCommand{}, errors.New("checkpoint summary must be a single line")
+		}
+	case MetaCommandRole:
+		if !isRoleName(command.Argument) {
+			return MetaCommand{}, errors.New("role requires a single-word name: @yanzi role <RoleName>")
+		}
+	case "":
+		return MetaCommand{}, errors.New("missing command")
+	default:
+		return MetaCommand{}, fmt.Errorf("unknown command %q", command.Name)
+	}
+	return command, nil
+}
+
+// ParseMetaCommand parses one line that must start with the @yanzi prefix.
+func ParseMetaCommand(line string) (MetaCommand, error) {
+	text := strings.TrimRight(line, " \t\r")
+	rest, ok := trimMetaCommandPrefix(text)
+	if !ok {
+		return MetaCommand{}, MetaCommandError{Text: text, Message: "must start with " + MetaCommandPrefix}
+	}
+
+	rest = strings.TrimSpace(rest)
+	name, argument := rest, ""
+	if idx := strings.IndexAny(rest, " \t"); idx >= 0 {
+		name, argument = rest[:idx], strings.TrimSpace(rest[idx+1:])
+	}
+	if MetaCommandName(name) == MetaCommandCheckpoint && argument != "" {
+		summary, err := strconv.Unquote(argument)
+		if err != nil || !strings.HasPrefix(argument, `"`) {
+			return MetaCommand{}, MetaCommandError{Text: text, Message: "checkpoint summary must be a double-quoted string"}
+		}
+		argument = summary
+	}
+
+	command, err := NewMetaCommand(name, argument)
+	if err != nil {
+		return MetaCommand{}, MetaCommandError{Text: text, Message: err.Error()}
+	}
+	return command, nil
+}
+
+// ScanMetaCommands returns every meta-command found in agent text, in order.
+// Only lines that begin with @yanzi at column zero are considered; all other
+// lines are ignored. Invalid command lines are reported together as a joined error.
+func ScanMetaCommands(text string) ([]MetaCommand, error) {
+	text = normalizeNewlines(text)
+	commands := make([]MetaCommand, 0)
+	var errs []error
+	for i, line := range strings.Split(text, "\n") {
+		if _, ok := trimMetaCommandPrefix(line); !ok {
+			continue
+		}
+		command, err := ParseMetaCommand(line)
+		if err != nil {
+			var parseErr MetaCommandError
+			if errors.As(err, &parseErr) {
+				parseErr.Line = i + 1
+				err = parseErr
+			}
+			errs = append(errs, err)
+			continue
+		}
+		command.Line = i + 1
+		commands = append(commands, command)
+	}
+	return commands, errors.Join(errs...)
+}
+
+// trimMetaCommandPrefix strips the @yanzi prefix when it is followed by whitespace or the end of the line.
+func trimMetaCommandPrefix(line string) (string, bool) {
+	if !strings.HasPrefix(line, MetaCommandPrefix) {
+		return "", false
+	}
+	rest := line[len(MetaCommandPrefix):]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' && rest[0] != '\r' {
+		return "", false
+	}
+	return rest, true
+}
+
+// isRoleName reports whether value is a single word usable as a role name.
+func isRoleName(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if !(r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
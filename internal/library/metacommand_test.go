@@ -0,0 +1,91 @@
+package yanzilibrary
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseMetaCommand(t *testing.T) {
+	cases := []struct {
+		line string
+		want MetaCommand
+	}{
+		{"@yanzi pause", MetaCommand{Name: MetaCommandPause}},
+		{"@yanzi resume  ", MetaCommand{Name: MetaCommandResume}},
+		{`@yanzi checkpoint "Auth flow done"`, MetaCommand{Name: MetaCommandCheckpoint, Argument: "Auth flow done"}},
+		{"@yanzi export", MetaCommand{Name: MetaCommandExport}},
+		{"@yanzi role\tReviewer", MetaCommand{Name: MetaCommandRole, Argument: "Reviewer"}},
+	}
+	for _, tc := range cases {
+		got, err := ParseMetaCommand(tc.line)
+		if err != nil {
+			t.Fatalf("ParseMetaCommand(%q): %v", tc.line, err)
+		}
+		if got != tc.want {
+			t.Fatalf("ParseMetaCommand(%q) = %+v, want %+v", tc.line, got, tc.want)
+		}
+	}
+}
+
+func TestParseMetaCommandRejectsInvalid(t *testing.T) {
+	cases := map[string]string{
+		"@yanzi":                          "missing command",
+		"@yanzi pause now":                "takes no arguments",
+		"@yanzi checkpoint":               "requires a summary",
+		"@yanzi checkpoint Auth":          "double-quoted",
+		`@yanzi checkpoint "unterminated`: "double-quoted",
+		"@yanzi role Senior Engineer":     "single-word",
+		"@yanzi deploy":                   "unknown command",
+		"yanzi pause":                     "must start with",
+	}
+	for line, want := range cases {
+		_, err := ParseMetaCommand(line)
+		if err == nil {
+			t.Fatalf("ParseMetaCommand(%q): expected error", line)
+		}
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("ParseMetaCommand(%q): expected %q in %v", line, want, err)
+		}
+	}
+}
+
+func TestMetaCommandStringRoundTrip(t *testing.T) {
+	command := MetaCommand{Name: MetaCommandCheckpoint, Argument: `Ship "v2"`}
+	parsed, err := ParseMetaCommand(command.String())
+	if err != nil {
+		t.Fatalf("ParseMetaCommand: %v", err)
+	}
+	if parsed != command {
+		t.Fatalf("round trip mismatch: %+v vs %+v", parsed, command)
+	}
+}
+
+func TestScanMetaCommands(t *testing.T) {
+	text := "Role: Engineer\r\n" +
+		"I will pause capture now.\n" +
+		"@yanzi pause\n" +
+		"  @yanzi resume\n" +
+		"@yanzibot pause\n" +
+		"@yanzi checkpoint \"Layout complete\"\n" +
+		"@yanzi role\n"
+
+	commands, err := ScanMetaCommands(text)
+	if len(commands) != 2 {
+		t.Fatalf("expected 2 commands, got %+v", commands)
+	}
+	if commands[0].Name != MetaCommandPause || commands[0].Line != 3 {
+		t.Fatalf("unexpected first command: %+v", commands[0])
+	}
+	if commands[1].Name != MetaCommandCheckpoint || commands[1].Argument != "Layout complete" || commands[1].Line != 6 {
+		t.Fatalf("unexpected second command: %+v", commands[1])
+	}
+
+	var parseErr MetaCommandError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected MetaCommandError, got %v", err)
+	}
+	if parseErr.Line != 7 {
+		t.Fatalf("expected error on line 7, got %+v", parseErr)
+	}
+}
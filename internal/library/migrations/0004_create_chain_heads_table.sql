@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS chain_heads (
+	project TEXT PRIMARY KEY,
+	head_hash TEXT NOT NULL,
+	updated_at TEXT NOT NULL
+);
+
+INSERT OR IGNORE INTO chain_heads (project, head_hash, updated_at)
+SELECT json_extract(i.meta, '$.project'), i.hash, i.created_at
+FROM intents AS i
+WHERE json_valid(i.meta)
+	AND json_type(i.meta, '$.project') = 'text'
+	AND i.rowid = (
+		SELECT j.rowid
+		FROM intents AS j
+		WHERE json_valid(j.meta)
+			AND json_extract(j.meta, '$.project') = json_extract(i.meta, '$.project')
+		ORDER BY j.created_at DESC, j.rowid DESC
+		LIMIT 1
+	);
@@ -0,0 +1,8 @@
+ALTER TABLE intents ADD COLUMN attachments TEXT;
+
+CREATE TABLE IF NOT EXISTS attachment_blobs (
+	digest TEXT PRIMARY KEY,
+	size INTEGER NOT NULL,
+	content BLOB NOT NULL,
+	created_at TEXT NOT NULL
+);
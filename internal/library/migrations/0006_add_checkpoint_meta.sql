@@ -0,0 +1 @@
+ALTER TABLE checkpoints ADD COLUMN meta TEXT;
@@ -0,0 +1,26 @@
+CREATE VIRTUAL TABLE IF NOT EXISTS intents_fts USING fts5(
+	title,
+	prompt,
+	response,
+	content='intents',
+	content_rowid='rowid'
+);
+
+CREATE TRIGGER IF NOT EXISTS intents_fts_after_insert AFTER INSERT ON intents BEGIN
+	INSERT INTO intents_fts (rowid, title, prompt, response)
+	VALUES (new.rowid, new.title, new.prompt, new.response);
+END;
+
+CREATE TRIGGER IF NOT EXISTS intents_fts_after_delete AFTER DELETE ON intents BEGIN
+	INSERT INTO intents_fts (intents_fts, rowid, title, prompt, response)
+	VALUES ('delete', old.rowid, old.title, old.prompt, old.response);
+END;
+
+CREATE TRIGGER IF NOT EXISTS intents_fts_after_update AFTER UPDATE OF title, prompt, response ON intents BEGIN
+	INSERT INTO intents_fts (intents_fts, rowid, title, prompt, response)
+	VALUES ('delete', old.rowid, old.title, old.prompt, old.response);
+	INSERT INTO intents_fts (rowid, title, prompt, response)
+	VALUES (new.rowid, new.title, new.prompt, new.response);
+END;
+
+INSERT INTO intents_fts (intents_fts) VALUES ('rebuild');
@@ -0,0 +1,9 @@
+ALTER TABLE intents ADD COLUMN project TEXT
+	GENERATED ALWAYS AS (CASE WHEN json_valid(meta) THEN json_extract(meta, '$.project') END) VIRTUAL;
+
+DROP INDEX IF EXISTS idx_intents_created_at;
+
+CREATE INDEX IF NOT EXISTS idx_intents_created_at_id ON intents (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_intents_project_created_at_id ON intents (project, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_intents_author_created_at_id ON intents (author, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_intents_source_type_created_at_id ON intents (source_type, created_at DESC, id DESC);
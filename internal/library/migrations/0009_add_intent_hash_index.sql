@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_intents_hash ON intents (hash);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS project_events (
+	seq INTEGER PRIMARY KEY AUTOINCREMENT,
+	action TEXT NOT NULL,
+	project TEXT NOT NULL,
+	previous_name TEXT,
+	description TEXT NOT NULL DEFAULT '',
+	archived INTEGER NOT NULL DEFAULT 0,
+	created_at TEXT NOT NULL,
+	prev_hash TEXT,
+	hash TEXT NOT NULL UNIQUE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_project_events_prev_hash ON project_events (IFNULL(prev_hash, ''));
+CREATE INDEX IF NOT EXISTS idx_project_events_project ON project_events (project, seq);
+
+ALTER TABLE projects ADD COLUMN archived INTEGER NOT NULL DEFAULT 0;
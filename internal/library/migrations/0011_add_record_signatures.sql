@@ -0,0 +1,4 @@
+ALTER TABLE intents ADD COLUMN key_id TEXT;
+ALTER TABLE intents ADD COLUMN signature TEXT;
+ALTER TABLE checkpoints ADD COLUMN key_id TEXT;
+ALTER TABLE checkpoints ADD COLUMN signature TEXT;
@@ -0,0 +1,29 @@
+DROP TRIGGER IF EXISTS intents_fts_after_insert;
+DROP TRIGGER IF EXISTS intents_fts_after_delete;
+DROP TRIGGER IF EXISTS intents_fts_after_update;
+DROP TABLE IF EXISTS intents_fts;
+
+CREATE VIRTUAL TABLE intents_fts USING fts5(
+	id UNINDEXED,
+	title,
+	prompt,
+	response
+);
+
+CREATE TRIGGER intents_fts_after_insert AFTER INSERT ON intents BEGIN
+	INSERT INTO intents_fts (id, title, prompt, response)
+	VALUES (new.id, new.title, new.prompt, new.response);
+END;
+
+CREATE TRIGGER intents_fts_after_delete AFTER DELETE ON intents BEGIN
+	DELETE FROM intents_fts WHERE id = old.id;
+END;
+
+CREATE TRIGGER intents_fts_after_update AFTER UPDATE OF id, title, prompt, response ON intents BEGIN
+	DELETE FROM intents_fts WHERE id = old.id;
+	INSERT INTO intents_fts (id, title, prompt, response)
+	VALUES (new.id, new.title, new.prompt, new.response);
+END;
+
+INSERT INTO intents_fts (id, title, prompt, response)
+SELECT id, title, prompt, response FROM intents;
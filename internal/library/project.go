@@ -4,7 +4,8 @@ import "time"
 
 // Project represents a named project namespace in the library ledger.
 type Project struct {
-	Name        string
-	Description string
-	CreatedAt   time.Time
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	Archived    bool      `json:"archived"`
 }
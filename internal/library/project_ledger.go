@@ -0,0 +1,529 @@
+package yanzilibrary
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Project ledger actions.
+const (
+	ProjectCreated    = "create"
+	ProjectDescribed  = "describe"
+	ProjectArchived   = "archive"
+	ProjectUnarchived = "unarchive"
+	ProjectRenamed    = "rename"
+)
+
+// Project ledger problem kinds reported by VerifyProjectLedger. They use the same values
+// as the checkpoint problem kinds.
+const (
+	// ProjectMismatch reports a hash or projects row that differs from the ledger.
+	ProjectMismatch = "mismatch"
+	// ProjectMissingLink reports an event whose prev_hash is not the event before it, or
+	// a ledger project with no projects row.
+	ProjectMissingLink = "missing_link"
+	// ProjectOrphan reports a projects row or event that no create event accounts for.
+	ProjectOrphan = "orphan"
+)
+
+// ProjectEvent is one entry in the project ledger. Each event records the project's full
+// state after the change, so replaying the ledger reproduces the projects table. Events
+// form a single chain across all projects: PrevHash is the hash of the event before it.
+type ProjectEvent struct {
+	Seq     int64  `json:"seq"`
+	Action  string `json:"action"`
+	Project string `json:"project"`
+	// PreviousName is the name a rename event moved the project away from.
+	PreviousName string `json:"previous_name,omitempty"`
+	Description  string `json:"description"`
+	Archived     bool   `json:"archived"`
+	CreatedAt    string `json:"created_at"`
+	PrevHash     string `json:"prev_hash,omitempty"`
+	Hash         string `json:"hash"`
+}
+
+// ProjectLedgerProblem is one integrity problem found in the project ledger.
+type ProjectLedgerProblem struct {
+	Kind    string `json:"kind"`
+	Project string `json:"project"`
+	// Hash is the event hash for problems with an event, and empty for projects rows.
+	Hash   string `json:"hash,omitempty"`
+	Detail string `json:"detail"`
+}
+
+// ProjectLedgerVerification is the result of replaying the project ledger against the
+// projects table.
+type ProjectLedgerVerification struct {
+	Head     string                 `json:"head,omitempty"`
+	Events   int                    `json:"events"`
+	Projects int                    `json:"projects"`
+	Links    int                    `json:"links"`
+	Valid    bool                   `json:"valid"`
+	Problems []ProjectLedgerProblem `json:"problems"`
+}
+
+// projectEventColumns lists the project_events columns read by ListProjectEvents.
+const projectEventColumns = `seq, action, project, previous_name, description, archived, created_at, prev_hash, hash`
+
+// projectConn is the subset of *sql.DB and *sql.Tx used to append project events.
+type projectConn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// HashProjectEvent computes the SHA-256 hash of a project event's canonical JSON preimage.
+func HashProjectEvent(event ProjectEvent) (string, error) {
+	preimage, err := canonicalProjectEventPreimage(event)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(preimage)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalProjectEventPreimage renders a project event in canonical JSON key order.
+// previous_name and prev_hash are omitted when empty.
+func canonicalProjectEventPreimage(event ProjectEvent) ([]byte, error) {
+	switch event.Action {
+	case ProjectCreated, ProjectDescribed, ProjectArchived, ProjectUnarchived, ProjectRenamed:
+	default:
+		return nil, fmt.Errorf("unknown project action %q", event.Action)
+	}
+	if strings.TrimSpace(event.Project) == "" {
+		return nil, errors.New("project is required for hashing")
+	}
+	if event.Action == ProjectRenamed && strings.TrimSpace(event.PreviousName) == "" {
+		return nil, errors.New("previous_name is required for hashing a rename")
+	}
+	createdAt, err := normalizeRFC3339(event.CreatedAt)
+	if err != nil {
+		return nil, errors.New("created_at must be RFC3339")
+	}
+
+	archived := []byte("false")
+	if event.Archived {
+		archived = []byte("true")
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	first := true
+
+	addStringField(&b, &first, "action", event.Action)
+	addStringField(&b, &first, "project", event.Project)
+	if event.PreviousName != "" {
+		addStringField(&b, &first, "previous_name", event.PreviousName)
+	}
+	addStringField(&b, &first, "description", event.Description)
+	addRawField(&b, &first, "archived", archived)
+	addStringField(&b, &first, "created_at", createdAt)
+	if event.PrevHash != "" {
+		addStringField(&b, &first, "prev_hash", event.PrevHash)
+	}
+	b.WriteByte('}')
+
+	return []byte(b.String()), nil
+}
+
+// AppendProjectEvent validates a project change against the current projects table,
+// appends it to the ledger, and applies it to the projects table in one transaction.
+// Only Action, Project, PreviousName (for renames), and Description (for creates and
+// describes) are read from event; the rest of the state is carried over from the
+// project. The stored event is returned.
+func AppendProjectEvent(ctx context.Context, db *sql.DB, event ProjectEvent) (ProjectEvent, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return ProjectEvent{}, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	stored, err := appendProjectEvent(ctx, tx, event)
+	if err != nil {
+		return ProjectEvent{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return ProjectEvent{}, err
+	}
+	return stored, nil
+}
+
+func appendProjectEvent(ctx context.Context, conn projectConn, event ProjectEvent) (ProjectEvent, error) {
+	event.Project = strings.TrimSpace(event.Project)
+	event.PreviousName = strings.TrimSpace(event.PreviousName)
+	if event.Project == "" {
+		return ProjectEvent{}, errors.New("project name is required")
+	}
+
+	target := event.Project
+	if event.Action == ProjectRenamed {
+		target = event.PreviousName
+		if target == "" {
+			return ProjectEvent{}, errors.New("current project name is required")
+		}
+	} else {
+		event.PreviousName = ""
+	}
+	current, found, err := loadProjectRow(ctx, conn, target)
+	if err != nil {
+		return ProjectEvent{}, err
+	}
+	if event.Action == ProjectCreated {
+		if found {
+			return ProjectEvent{}, fmt.Errorf("project already exists: %s", target)
+		}
+	} else if !found {
+		return ProjectEvent{}, ProjectNotFoundError{Name: target}
+	}
+
+	switch event.Action {
+	case ProjectCreated:
+		event.Archived = false
+	case ProjectDescribed:
+		if event.Description == current.Description {
+			return ProjectEvent{}, fmt.Errorf("project %s already has that description", target)
+		}
+		event.Archived = current.Archived
+	case ProjectArchived, ProjectUnarchived:
+		archive := event.Action == ProjectArchived
+		if current.Archived == archive {
+			if archive {
+				return ProjectEvent{}, fmt.Errorf("project already archived: %s", target)
+			}
+			return ProjectEvent{}, fmt.Errorf("project is not archived: %s", target)
+		}
+		event.Description = current.Description
+		event.Archived = archive
+	case ProjectRenamed:
+		if event.Project == event.PreviousName {
+			return ProjectEvent{}, fmt.Errorf("project is already named %s", target)
+		}
+		if _, exists, err := loadProjectRow(ctx, conn, event.Project); err != nil {
+			return ProjectEvent{}, err
+		} else if exists {
+			return ProjectEvent{}, fmt.Errorf("project already exists: %s", event.Project)
+		}
+		// Intents and checkpoints hash the project name, so they cannot follow a rename.
+		var records int
+		if err := conn.QueryRowContext(ctx, `SELECT (SELECT COUNT(1) FROM intents WHERE project = ?) + (SELECT COUNT(1) FROM checkpoints WHERE project = ?)`, target, target).Scan(&records); err != nil {
+			return ProjectEvent{}, err
+		}
+		if records > 0 {
+			return ProjectEvent{}, fmt.Errorf("project %s has recorded intents or checkpoints, whose hashes include its name; it cannot be renamed", target)
+		}
+		event.Description = current.Description
+		event.Archived = current.Archived
+	default:
+		return ProjectEvent{}, fmt.Errorf("unknown project action %q", event.Action)
+	}
+
+	event.CreatedAt = time.Now().UTC().Format(time.RFC3339Nano)
+	if err := insertProjectEvent(ctx, conn, &event); err != nil {
+		return ProjectEvent{}, err
+	}
+
+	if event.Action == ProjectCreated {
+		_, err = conn.ExecContext(ctx, `INSERT INTO projects (name, description, created_at, archived, prev_hash, hash) VALUES (?, ?, ?, ?, ?, ?)`,
+			event.Project, event.Description, event.CreatedAt, event.Archived, nullableString(event.PrevHash), event.Hash)
+		if isUniqueViolation(err) {
+			return ProjectEvent{}, fmt.Errorf("project already exists: %s", event.Project)
+		}
+	} else {
+		_, err = conn.ExecContext(ctx, `UPDATE projects SET name = ?, description = ?, archived = ?, prev_hash = ?, hash = ? WHERE name = ?`,
+			event.Project, event.Description, event.Archived, nullableString(event.PrevHash), event.Hash, target)
+	}
+	if err != nil {
+		return ProjectEvent{}, err
+	}
+	return event, nil
+}
+
+// insertProjectEvent links event to the ledger head, hashes it, and stores it, setting
+// its Seq, PrevHash, and Hash.
+func insertProjectEvent(ctx context.Context, conn projectConn, event *ProjectEvent) error {
+	var head sql.NullString
+	err := conn.QueryRowContext(ctx, `SELECT hash FROM project_events ORDER BY seq DESC LIMIT 1`).Scan(&head)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	event.PrevHash = head.String
+
+	hash, err := HashProjectEvent(*event)
+	if err != nil {
+		return err
+	}
+	event.Hash = hash
+
+	result, err := conn.ExecContext(ctx, `INSERT INTO project_events (action, project, previous_name, description, archived, created_at, prev_hash, hash)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.Action, event.Project, nullableString(event.PreviousName), event.Description, event.Archived, event.CreatedAt, nullableString(event.PrevHash), event.Hash)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errors.New("project ledger changed concurrently; retry")
+		}
+		return err
+	}
+	event.Seq, err = result.LastInsertId()
+	return err
+}
+
+// backfillProjectLedger records a create event for every project that predates the
+// ledger, oldest first, and points each projects row at its event.
+func backfillProjectLedger(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `SELECT name, description, created_at FROM projects ORDER BY created_at ASC, name ASC`)
+	if err != nil {
+		return err
+	}
+	var events []ProjectEvent
+	for rows.Next() {
+		event := ProjectEvent{Action: ProjectCreated}
+		var description sql.NullString
+		if err := rows.Scan(&event.Project, &description, &event.CreatedAt); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		event.Description = description.String
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return err
+	}
+	_ = rows.Close()
+
+	for _, event := range events {
+		if err := insertProjectEvent(ctx, tx, &event); err != nil {
+			return fmt.Errorf("project %s: %w", event.Project, err)
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE projects SET prev_hash = ?, hash = ? WHERE name = ?`, nullableString(event.PrevHash), event.Hash, event.Project); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListProjectEvents returns the whole project ledger, oldest first.
+func ListProjectEvents(ctx context.Context, db *sql.DB) ([]ProjectEvent, error) {
+	rows, err := db.QueryContext(ctx, `SELECT `+projectEventColumns+` FROM project_events ORDER BY seq ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]ProjectEvent, 0)
+	for rows.Next() {
+		var event ProjectEvent
+		var previousName, prevHash sql.NullString
+		if err := rows.Scan(&event.Seq, &event.Action, &event.Project, &previousName, &event.Description, &event.Archived, &event.CreatedAt, &prevHash, &event.Hash); err != nil {
+			return nil, err
+		}
+		event.PreviousName = previousName.String
+		event.PrevHash = prevHash.String
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ProjectHistory returns the events, oldest first, that concern the project currently
+// named name, following renames back to the names it had before.
+func ProjectHistory(events []ProjectEvent, name string) []ProjectEvent {
+	names := map[string]bool{name: true}
+	var history []ProjectEvent
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		if !names[event.Project] {
+			continue
+		}
+		history = append(history, event)
+		switch event.Action {
+		case ProjectCreated:
+			delete(names, event.Project)
+		case ProjectRenamed:
+			delete(names, event.Project)
+			names[event.PreviousName] = true
+		}
+	}
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+	return history
+}
+
+// VerifyProjectLedger recomputes every project event hash, checks that each event links
+// to the one before it, and replays the ledger to compare the resulting state with the
+// projects table. An error is returned only when the database cannot be read.
+func VerifyProjectLedger(ctx context.Context, db *sql.DB) (ProjectLedgerVerification, error) {
+	result := ProjectLedgerVerification{Problems: []ProjectLedgerProblem{}}
+	report := func(kind, project, hash, detail string) {
+		result.Problems = append(result.Problems, ProjectLedgerProblem{Kind: kind, Project: project, Hash: hash, Detail: detail})
+	}
+
+	events, err := ListProjectEvents(ctx, db)
+	if err != nil {
+		return ProjectLedgerVerification{}, err
+	}
+	replayed := make(map[string]projectRow)
+	expectedPrev := ""
+	for _, event := range events {
+		result.Events++
+		if event.PrevHash != "" {
+			result.Links++
+		}
+		switch {
+		case event.PrevHash == expectedPrev:
+		case expectedPrev == "":
+			report(ProjectMissingLink, event.Project, event.Hash, "prev_hash "+event.PrevHash+" not found; expected the first event")
+		default:
+			report(ProjectMissingLink, event.Project, event.Hash, "prev_hash "+orNone(event.PrevHash)+", expected "+expectedPrev)
+		}
+		expectedPrev = event.Hash
+
+		computed, err := HashProjectEvent(event)
+		switch {
+		case err != nil:
+			report(ProjectMismatch, event.Project, event.Hash, "compute hash: "+err.Error())
+		case computed != event.Hash:
+			report(ProjectMismatch, event.Project, event.Hash, "stored hash "+event.Hash+", computed "+computed)
+		}
+
+		row := projectRow{Description: event.Description, Archived: event.Archived, PrevHash: event.PrevHash, Hash: event.Hash}
+		switch event.Action {
+		case ProjectCreated:
+			if _, ok := replayed[event.Project]; ok {
+				report(ProjectMismatch, event.Project, event.Hash, "creates project "+event.Project+", which already exists")
+			}
+			row.CreatedAt = event.CreatedAt
+		case ProjectRenamed:
+			previous, ok := replayed[event.PreviousName]
+			if !ok {
+				report(ProjectOrphan, event.Project, event.Hash, "renames unknown project "+event.PreviousName)
+			}
+			delete(replayed, event.PreviousName)
+			row.CreatedAt = previous.CreatedAt
+		default:
+			previous, ok := replayed[event.Project]
+			if !ok {
+				report(ProjectOrphan, event.Project, event.Hash, event.Action+" of unknown project "+event.Project)
+			}
+			row.CreatedAt = previous.CreatedAt
+		}
+		replayed[event.Project] = row
+	}
+	result.Head = expectedPrev
+
+	rows, err := db.QueryContext(ctx, `SELECT name, description, created_at, archived, prev_hash, hash FROM projects ORDER BY name ASC`)
+	if err != nil {
+		return ProjectLedgerVerification{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		var stored projectRow
+		var description, prevHash sql.NullString
+		if err := rows.Scan(&name, &description, &stored.CreatedAt, &stored.Archived, &prevHash, &stored.Hash); err != nil {
+			return ProjectLedgerVerification{}, err
+		}
+		stored.Description = description.String
+		stored.PrevHash = prevHash.String
+		result.Projects++
+
+		want, ok := replayed[name]
+		if !ok {
+			report(ProjectOrphan, name, "", "project is not recorded in the project ledger")
+			continue
+		}
+		delete(replayed, name)
+		if differ := want.diff(stored); len(differ) > 0 {
+			report(ProjectMismatch, name, "", "differs from the project ledger in "+strings.Join(differ, ", "))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return ProjectLedgerVerification{}, err
+	}
+
+	var missing []string
+	for name := range replayed {
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		report(ProjectMissingLink, name, replayed[name].Hash, "project is recorded in the project ledger but missing from projects")
+	}
+
+	result.Valid = len(result.Problems) == 0
+	return result, nil
+}
+
+// projectRow is the stored state of one projects row.
+type projectRow struct {
+	Description string
+	CreatedAt   string
+	Archived    bool
+	PrevHash    string
+	Hash        string
+}
+
+// loadProjectRow loads a projects row by name.
+func loadProjectRow(ctx context.Context, conn projectConn, name string) (projectRow, bool, error) {
+	var row projectRow
+	var description, prevHash sql.NullString
+	err := conn.QueryRowContext(ctx, `SELECT description, created_at, archived, prev_hash, hash FROM projects WHERE name = ?`, name).
+		Scan(&description, &row.CreatedAt, &row.Archived, &prevHash, &row.Hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return projectRow{}, false, nil
+	}
+	if err != nil {
+		return projectRow{}, false, err
+	}
+	row.Description = description.String
+	row.PrevHash = prevHash.String
+	return row, true, nil
+}
+
+// diff names the columns of stored that differ from the replayed row.
+func (r projectRow) diff(stored projectRow) []string {
+	var differ []string
+	if r.Hash != stored.Hash {
+		differ = append(differ, "hash")
+	}
+	if r.PrevHash != stored.PrevHash {
+		differ = append(differ, "prev_hash")
+	}
+	if r.Description != stored.Description {
+		differ = append(differ, "description")
+	}
+	if r.CreatedAt != stored.CreatedAt {
+		differ = append(differ, "created_at")
+	}
+	if r.Archived != stored.Archived {
+		differ = append(differ, "archived")
+	}
+	return differ
+}
+
+// nullableString maps an empty string to SQL NULL.
+func nullableString(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// orNone renders an empty hash as "none" in problem details.
+func orNone(hash string) string {
+	if hash == "" {
+		return "none"
+	}
+	return hash
+}
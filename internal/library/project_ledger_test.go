@@ -0,0 +1,109 @@
+package yanzilibrary
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProjectLedger(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(envDBPath, filepath.Join(t.TempDir(), "yanzi.db"))
+	db, err := InitDB()
+	if err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	// Projects that predate the ledger are adopted by the migration backfill.
+	if _, err := db.Exec(`INSERT INTO projects (name, description, created_at, prev_hash, hash)
+		VALUES ('legacy', 'old', '2025-01-01T00:00:00Z', NULL, 'legacy-hash')`); err != nil {
+		t.Fatalf("insert legacy project: %v", err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if err := backfillProjectLedger(ctx, tx); err != nil {
+		t.Fatalf("backfillProjectLedger: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	steps := []ProjectEvent{
+		{Action: ProjectCreated, Project: "alpha", Description: "first"},
+		{Action: ProjectDescribed, Project: "alpha", Description: "second"},
+		{Action: ProjectArchived, Project: "alpha"},
+		{Action: ProjectRenamed, Project: "beta", PreviousName: "alpha"},
+		{Action: ProjectCreated, Project: "alpha"},
+	}
+	var events []ProjectEvent
+	for _, step := range steps {
+		event, err := AppendProjectEvent(ctx, db, step)
+		if err != nil {
+			t.Fatalf("AppendProjectEvent %s %s: %v", step.Action, step.Project, err)
+		}
+		events = append(events, event)
+	}
+	if renamed := events[3]; renamed.Description != "second" || !renamed.Archived {
+		t.Fatalf("expected the rename to carry the project state, got %+v", renamed)
+	}
+	for _, bad := range []ProjectEvent{
+		{Action: ProjectCreated, Project: "beta"},
+		{Action: ProjectArchived, Project: "beta"},
+		{Action: ProjectUnarchived, Project: "alpha"},
+		{Action: ProjectDescribed, Project: "missing", Description: "x"},
+		{Action: ProjectRenamed, Project: "alpha", PreviousName: "beta"},
+	} {
+		if _, err := AppendProjectEvent(ctx, db, bad); err == nil {
+			t.Fatalf("expected %s of %s to fail", bad.Action, bad.Project)
+		}
+	}
+
+	all, err := ListProjectEvents(ctx, db)
+	if err != nil {
+		t.Fatalf("ListProjectEvents: %v", err)
+	}
+	if len(all) != 6 || all[0].Project != "legacy" || all[0].PrevHash != "" || all[1].PrevHash != all[0].Hash {
+		t.Fatalf("expected the backfilled event to start one chain, got %+v", all)
+	}
+	history := ProjectHistory(all, "beta")
+	if len(history) != 4 || history[0].Hash != events[0].Hash || history[3].Hash != events[3].Hash {
+		t.Fatalf("expected beta's history to follow the rename, got %+v", history)
+	}
+	if history := ProjectHistory(all, "alpha"); len(history) != 1 || history[0].Hash != events[4].Hash {
+		t.Fatalf("expected the new alpha to have only its create event, got %+v", history)
+	}
+
+	result, err := VerifyProjectLedger(ctx, db)
+	if err != nil {
+		t.Fatalf("VerifyProjectLedger: %v", err)
+	}
+	if !result.Valid || result.Events != 6 || result.Projects != 3 || result.Links != 5 || result.Head != events[4].Hash {
+		t.Fatalf("expected a valid ledger, got %+v", result)
+	}
+
+	if _, err := db.Exec(`UPDATE projects SET description = 'edited' WHERE name = 'beta'`); err != nil {
+		t.Fatalf("tamper project: %v", err)
+	}
+	if _, err := db.Exec(`DELETE FROM project_events WHERE hash = ?`, events[1].Hash); err != nil {
+		t.Fatalf("delete event: %v", err)
+	}
+	result, err = VerifyProjectLedger(ctx, db)
+	if err != nil {
+		t.Fatalf("VerifyProjectLedger: %v", err)
+	}
+	if result.Valid || len(result.Problems) != 2 {
+		t.Fatalf("expected two problems, got %+v", result.Problems)
+	}
+	link, row := result.Problems[0], result.Problems[1]
+	if link.Kind != ProjectMissingLink || link.Hash != events[2].Hash || !strings.Contains(link.Detail, events[0].Hash) {
+		t.Fatalf("expected the deleted event to break the link, got %+v", link)
+	}
+	if row.Kind != ProjectMismatch || row.Project != "beta" || row.Detail != "differs from the project ledger in description" {
+		t.Fatalf("expected the edited description, got %+v", row)
+	}
+}
@@ -2,16 +2,15 @@ package yanzilibrary
 
 import (
 	"context"
-	"crypto/sha256"
 	"database/sql"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
 )
 
-// CreateProject creates a unique project record and returns the created project.
+// CreateProject creates a unique project record, recording a create event in the project
+// ledger, and returns the created project.
 func CreateProject(name string, description string) (*Project, error) {
 	name = strings.TrimSpace(name)
 	if name == "" {
@@ -26,31 +25,12 @@ func CreateProject(name string, description string) (*Project, error) {
 		_ = db.Close()
 	}()
 
-	ctx := context.Background()
-	exists, err := projectExists(ctx, db, name)
+	event, err := AppendProjectEvent(context.Background(), db, ProjectEvent{Action: ProjectCreated, Project: name, Description: description})
 	if err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, fmt.Errorf("project already exists: %s", name)
-	}
-
-	createdAt := time.Now().UTC()
-	createdAtText := createdAt.Format(time.RFC3339Nano)
-	hash := hashProjectRecord(name, description, createdAtText)
-
-	if _, err := db.ExecContext(
-		ctx,
-		`INSERT INTO projects (name, description, created_at, prev_hash, hash) VALUES (?, ?, ?, ?, ?)`,
-		name,
-		description,
-		createdAtText,
-		nil,
-		hash,
-	); err != nil {
-		if isUniqueViolation(err) {
-			return nil, fmt.Errorf("project already exists: %s", name)
-		}
+	createdAt, err := time.Parse(time.RFC3339Nano, event.CreatedAt)
+	if err != nil {
 		return nil, err
 	}
 
@@ -61,6 +41,11 @@ func CreateProject(name string, description string) (*Project, error) {
 	}, nil
 }
 
+// ProjectExists reports whether a project with the given name has been created.
+func ProjectExists(ctx context.Context, db *sql.DB, name string) (bool, error) {
+	return projectExists(ctx, db, strings.TrimSpace(name))
+}
+
 // ListProjects returns all projects ordered by creation time, oldest first.
 func ListProjects() ([]Project, error) {
 	db, err := InitDB()
@@ -71,7 +56,7 @@ func ListProjects() ([]Project, error) {
 		_ = db.Close()
 	}()
 
-	rows, err := db.QueryContext(context.Background(), `SELECT name, description, created_at FROM projects ORDER BY created_at ASC, name ASC`)
+	rows, err := db.QueryContext(context.Background(), `SELECT name, description, created_at, archived FROM projects ORDER BY created_at ASC, name ASC`)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +67,7 @@ func ListProjects() ([]Project, error) {
 		var project Project
 		var description sql.NullString
 		var createdAtText string
-		if err := rows.Scan(&project.Name, &description, &createdAtText); err != nil {
+		if err := rows.Scan(&project.Name, &description, &createdAtText, &project.Archived); err != nil {
 			return nil, err
 		}
 		if description.Valid {
@@ -101,12 +86,6 @@ func ListProjects() ([]Project, error) {
 	return projects, nil
 }
 
-// hashProjectRecord computes a deterministic hash for the projects table hash column.
-func hashProjectRecord(name, description, createdAt string) string {
-	sum := sha256.Sum256([]byte(name + "\n" + description + "\n" + createdAt))
-	return hex.EncodeToString(sum[:])
-}
-
 // isUniqueViolation reports whether the database error is a uniqueness constraint violation.
 func isUniqueViolation(err error) bool {
 	if err == nil {
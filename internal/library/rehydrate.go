@@ -36,6 +36,13 @@ type RehydratePayload struct {
 
 // RehydrateProject loads the latest checkpoint and subsequent intents for a project.
 func RehydrateProject(project string) (*RehydratePayload, error) {
+	return RehydrateProjectInRange(project, time.Time{}, time.Time{})
+}
+
+// RehydrateProjectInRange rehydrates a project as of until: it loads the latest checkpoint
+// created at or before until and the intents after it up to until, keeping only intents
+// created at or after since. A zero bound is open.
+func RehydrateProjectInRange(project string, since, until time.Time) (*RehydratePayload, error) {
 	project = strings.TrimSpace(project)
 	if project == "" {
 		return nil, errors.New("project is required")
@@ -58,7 +65,7 @@ func RehydrateProject(project string) (*RehydratePayload, error) {
 		return nil, ProjectNotFoundError{Name: project}
 	}
 
-	latest, err := latestCheckpointByProject(ctx, db, project)
+	latest, err := latestCheckpointByProject(ctx, db, project, until)
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +77,17 @@ func RehydrateProject(project string) (*RehydratePayload, error) {
 	if err != nil {
 		return nil, err
 	}
+	inRange := intents[:0]
+	for _, intent := range intents {
+		if !since.IsZero() && intent.CreatedAt.Before(since) {
+			continue
+		}
+		if !until.IsZero() && intent.CreatedAt.After(until) {
+			continue
+		}
+		inRange = append(inRange, intent)
+	}
+	intents = inRange
 
 	return &RehydratePayload{
 		Project:          project,
@@ -78,37 +96,58 @@ func RehydrateProject(project string) (*RehydratePayload, error) {
 	}, nil
 }
 
-// latestCheckpointByProject returns the latest checkpoint for a project by created_at descending.
-func latestCheckpointByProject(ctx context.Context, db *sql.DB, project string) (*Checkpoint, error) {
-	row := db.QueryRowContext(ctx, `SELECT hash, project, summary, created_at, artifact_ids, previous_checkpoint_id FROM checkpoints WHERE project = ? ORDER BY created_at DESC LIMIT 1`, project)
-
-	var checkpoint Checkpoint
-	var artifactText string
-	var prev sql.NullString
-	if err := row.Scan(
-		&checkpoint.Hash,
-		&checkpoint.Project,
-		&checkpoint.Summary,
-		&checkpoint.CreatedAt,
-		&artifactText,
-		&prev,
-	); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
+// latestCheckpointByProject returns the latest checkpoint for a project by created_at descending,
+// skipping checkpoints created after until when until is set.
+func latestCheckpointByProject(ctx context.Context, db *sql.DB, project string, until time.Time) (*Checkpoint, error) {
+	rows, err := db.QueryContext(ctx, `SELECT hash, project, summary, created_at, artifact_ids, previous_checkpoint_id, meta FROM checkpoints WHERE project = ? ORDER BY created_at DESC`, project)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var checkpoint Checkpoint
+		var artifactText string
+		var prev sql.NullString
+		var meta sql.NullString
+		if err := rows.Scan(
+			&checkpoint.Hash,
+			&checkpoint.Project,
+			&checkpoint.Summary,
+			&checkpoint.CreatedAt,
+			&artifactText,
+			&prev,
+			&meta,
+		); err != nil {
+			return nil, err
+		}
+		if !until.IsZero() {
+			createdAt, err := time.Parse(time.RFC3339Nano, checkpoint.CreatedAt)
+			if err != nil {
+				return nil, fmt.Errorf("parse checkpoint created_at for %s: %w", checkpoint.Hash, err)
+			}
+			if createdAt.After(until) {
+				continue
+			}
+		}
 
-	if artifactText != "" {
-		if err := json.Unmarshal([]byte(artifactText), &checkpoint.ArtifactIDs); err != nil {
-			return nil, fmt.Errorf("decode checkpoint artifact_ids: %w", err)
+		if artifactText != "" {
+			if err := json.Unmarshal([]byte(artifactText), &checkpoint.ArtifactIDs); err != nil {
+				return nil, fmt.Errorf("decode checkpoint artifact_ids: %w", err)
+			}
 		}
+		if prev.Valid {
+			checkpoint.PreviousCheckpointID = prev.String
+		}
+		if meta.Valid && meta.String != "" {
+			checkpoint.Meta = json.RawMessage(meta.String)
+		}
+		return &checkpoint, nil
 	}
-	if prev.Valid {
-		checkpoint.PreviousCheckpointID = prev.String
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-
-	return &checkpoint, nil
+	return nil, nil
 }
 
 // intentsSinceCheckpoint returns intents created strictly after the provided checkpoint timestamp.